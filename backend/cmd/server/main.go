@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/timeout"
 	"github.com/joho/godotenv"
 
+	"github.com/Shrey-Yash/Masked11/config"
 	"github.com/Shrey-Yash/Masked11/internal/database"
 	"github.com/Shrey-Yash/Masked11/internal/handlers"
 	"github.com/Shrey-Yash/Masked11/internal/middleware"
@@ -93,37 +95,204 @@ func initializeRepositories() map[string]interface{} {
 	// Redis Repos with connection pooling
 	sessionRepo := redisrepo.NewSessionRepository(database.Redis, database.Ctx)
 	cartRepo := redisrepo.NewCartRepository(database.Redis, database.Ctx)
+	recentlyViewedRepo := redisrepo.NewRecentlyViewedRepository(database.Redis, database.Ctx)
 
 	// MongoDB Repos with optimized queries
 	userRepo := mongodb.NewUserRepository(database.Mongo)
 	productRepo := mongodb.NewProductRepository(database.Mongo)
+	productHistoryRepo := mongodb.NewProductHistoryRepository(database.Mongo)
+	priceListRepo := mongodb.NewPriceListRepository(database.Mongo)
+	categoryRepo := mongodb.NewCategoryRepository(database.Mongo)
+	collectionRepo := mongodb.NewCollectionRepository(database.Mongo)
+	merchandisingRepo := mongodb.NewMerchandisingRepository(database.Mongo)
+	reviewRepo := mongodb.NewReviewRepository(database.Mongo)
+	questionRepo := mongodb.NewQuestionRepository(database.Mongo)
 
 	// PostgreSQL Repos with connection pooling
 	orderRepo := postgres.NewOrderRepository(database.PostgresPool)
+	paymentRepo := postgres.NewPaymentRepository(database.PostgresPool)
+	returnRepo := postgres.NewReturnRepository(database.PostgresPool)
+	shipmentRepo := postgres.NewShipmentRepository(database.PostgresPool)
+	invoiceRepo := postgres.NewInvoiceRepository(database.PostgresPool)
+	jobRepo := postgres.NewJobRepository(database.PostgresPool)
+	analyticsRepo := postgres.NewAnalyticsRepository(database.PostgresPool)
 
 	return map[string]interface{}{
-		"sessionRepo": sessionRepo,
-		"cartRepo":    cartRepo,
-		"userRepo":    userRepo,
-		"productRepo": productRepo,
-		"orderRepo":   orderRepo,
+		"sessionRepo":        sessionRepo,
+		"cartRepo":           cartRepo,
+		"recentlyViewedRepo": recentlyViewedRepo,
+		"userRepo":           userRepo,
+		"productRepo":        productRepo,
+		"productHistoryRepo": productHistoryRepo,
+		"priceListRepo":      priceListRepo,
+		"categoryRepo":       categoryRepo,
+		"collectionRepo":     collectionRepo,
+		"merchandisingRepo":  merchandisingRepo,
+		"reviewRepo":         reviewRepo,
+		"questionRepo":       questionRepo,
+		"orderRepo":          orderRepo,
+		"paymentRepo":        paymentRepo,
+		"returnRepo":         returnRepo,
+		"shipmentRepo":       shipmentRepo,
+		"invoiceRepo":        invoiceRepo,
+		"jobRepo":            jobRepo,
+		"analyticsRepo":      analyticsRepo,
 	}
 }
 
 func initializeServices(repos map[string]interface{}) map[string]interface{} {
+	cfg := config.Load()
+
 	// Initialize services with dependency injection
 	authService := services.NewAuthService(repos["userRepo"].(interfaces.UserRepository))
-	productService := services.NewProductService(repos["productRepo"].(interfaces.ProductRepository))
+	productService := services.NewProductService(
+		repos["productRepo"].(interfaces.ProductRepository),
+		repos["categoryRepo"].(interfaces.CategoryRepository),
+		repos["productHistoryRepo"].(interfaces.ProductHistoryRepository),
+	)
+	go func() {
+		if err := productService.BackfillSlugs(context.Background()); err != nil {
+			log.Printf("Slug backfill failed: %v", err)
+		}
+		if err := productService.BackfillStatuses(context.Background()); err != nil {
+			log.Printf("Product status backfill failed: %v", err)
+		}
+	}()
+	productService.StartPublisher(cfg.Catalog.PublishInterval, middleware.InvalidateProductCache)
+	categoryService := services.NewCategoryService(
+		repos["categoryRepo"].(interfaces.CategoryRepository),
+		repos["productRepo"].(interfaces.ProductRepository),
+	)
+	go func() {
+		if err := categoryService.MigrateProductCategories(context.Background()); err != nil {
+			log.Printf("Category migration failed: %v", err)
+		}
+	}()
+	mediaService := services.NewMediaService(
+		repos["productRepo"].(interfaces.ProductRepository),
+		services.NewBlobStore(cfg.Media),
+		cfg.Media,
+	)
+	productService.StartPurger(cfg.Catalog.PurgeInterval, cfg.Catalog.DeletedRetention, mediaService.PurgeProductImages)
+	pricingService := services.NewPricingService(
+		repos["productRepo"].(interfaces.ProductRepository),
+		repos["userRepo"].(interfaces.UserRepository),
+		repos["priceListRepo"].(interfaces.PriceListRepository),
+	)
+	pricingService.StartSaleWatcher(cfg.Catalog.SaleWatchInterval, middleware.InvalidateProductCache)
+	collectionService := services.NewCollectionService(
+		repos["collectionRepo"].(interfaces.CollectionRepository),
+		repos["productRepo"].(interfaces.ProductRepository),
+		repos["categoryRepo"].(interfaces.CategoryRepository),
+	)
+	go func() {
+		if err := pricingService.ConvertLegacyPrices(context.Background()); err != nil {
+			log.Printf("Price conversion failed: %v", err)
+		}
+		if err := collectionService.ConvertLegacyPrices(context.Background()); err != nil {
+			log.Printf("Collection price conversion failed: %v", err)
+		}
+	}()
+	productImportService := services.NewProductImportService(
+		repos["productRepo"].(interfaces.ProductRepository),
+		repos["categoryRepo"].(interfaces.CategoryRepository),
+		repos["jobRepo"].(interfaces.JobRepository),
+		productService,
+		cfg.Import,
+	)
+	paymentService := services.NewPaymentService(
+		repos["paymentRepo"].(interfaces.PaymentRepository),
+		repos["orderRepo"].(interfaces.OrderRepository),
+		services.ManualPaymentGateway{},
+	)
+	paymentService.StartRefundRetrier(cfg.Returns.RefundRetryInterval)
 	orderService := services.NewOrderService(
 		repos["orderRepo"].(interfaces.OrderRepository),
 		repos["cartRepo"].(interfaces.CartRepository),
 		repos["userRepo"].(interfaces.UserRepository),
+		repos["productRepo"].(interfaces.ProductRepository),
+		repos["paymentRepo"].(interfaces.PaymentRepository),
+		paymentService,
+		pricingService,
+		cfg.COD,
+	)
+	go func() {
+		if err := orderService.BackfillCustomerEmails(context.Background()); err != nil {
+			log.Printf("Customer email backfill failed: %v", err)
+		}
+	}()
+	returnService := services.NewReturnService(
+		repos["returnRepo"].(interfaces.ReturnRepository),
+		repos["orderRepo"].(interfaces.OrderRepository),
+		repos["productRepo"].(interfaces.ProductRepository),
+		paymentService,
+		cfg.Returns,
+	)
+	shipmentService := services.NewShipmentService(
+		repos["shipmentRepo"].(interfaces.ShipmentRepository),
+		repos["orderRepo"].(interfaces.OrderRepository),
+		orderService,
+	)
+	invoiceService := services.NewInvoiceService(
+		repos["invoiceRepo"].(interfaces.InvoiceRepository),
+		repos["orderRepo"].(interfaces.OrderRepository),
+		cfg.Invoice,
+	)
+	exportService := services.NewExportService(
+		repos["orderRepo"].(interfaces.OrderRepository),
+		repos["jobRepo"].(interfaces.JobRepository),
+		cfg.Export,
+	)
+	analyticsService := services.NewAnalyticsService(
+		repos["analyticsRepo"].(interfaces.AnalyticsRepository),
+		cfg.Analytics,
+	)
+	analyticsService.StartRefresher()
+	merchandisingService := services.NewMerchandisingService(
+		repos["merchandisingRepo"].(interfaces.MerchandisingRepository),
+		repos["analyticsRepo"].(interfaces.AnalyticsRepository),
+		repos["productRepo"].(interfaces.ProductRepository),
+		cfg.Merchandising,
+	)
+	merchandisingService.StartRefresher()
+	reviewService := services.NewReviewService(
+		repos["reviewRepo"].(interfaces.ReviewRepository),
+		repos["orderRepo"].(interfaces.OrderRepository),
+		repos["productRepo"].(interfaces.ProductRepository),
+		repos["userRepo"].(interfaces.UserRepository),
+	)
+	questionService := services.NewQuestionService(
+		repos["questionRepo"].(interfaces.QuestionRepository),
+		repos["orderRepo"].(interfaces.OrderRepository),
+		repos["productRepo"].(interfaces.ProductRepository),
+		repos["userRepo"].(interfaces.UserRepository),
+		services.LogNotifier{},
+	)
+	recentlyViewedService := services.NewRecentlyViewedService(
+		repos["recentlyViewedRepo"].(interfaces.RecentlyViewedRepository),
+		repos["productRepo"].(interfaces.ProductRepository),
+		cfg.RecentlyViewed,
 	)
 
 	return map[string]interface{}{
-		"AuthService":    authService,
-		"ProductService": productService,
-		"OrderService":   orderService,
+		"AuthService":           authService,
+		"ProductService":        productService,
+		"CategoryService":       categoryService,
+		"CollectionService":     collectionService,
+		"MediaService":          mediaService,
+		"PricingService":        pricingService,
+		"ProductImportService":  productImportService,
+		"OrderService":          orderService,
+		"PaymentService":        paymentService,
+		"ReturnService":         returnService,
+		"ShipmentService":       shipmentService,
+		"InvoiceService":        invoiceService,
+		"ExportService":         exportService,
+		"AnalyticsService":      analyticsService,
+		"MerchandisingService":  merchandisingService,
+		"RecentlyViewedService": recentlyViewedService,
+		"ReviewService":         reviewService,
+		"QuestionService":       questionService,
 	}
 }
 
@@ -132,18 +301,50 @@ func initializeHandlers(services map[string]interface{}) map[string]interface{}
 	authHandler := handlers.NewAuthHandler(
 		services["AuthService"].(*services.AuthService),
 		repos["sessionRepo"].(interfaces.SessionRepository),
+		services["RecentlyViewedService"].(*services.RecentlyViewedService),
 	)
 	userHandler := handlers.NewUserHandler(services["AuthService"].(*services.AuthService))
-	productHandler := handlers.NewProductHandler(services["ProductService"].(*services.ProductService))
-	cartHandler := handlers.NewCartHandler(repos["cartRepo"].(interfaces.CartRepository))
+	pricingService := services["PricingService"].(*services.PricingService)
+	productHandler := handlers.NewProductHandler(services["ProductService"].(*services.ProductService), pricingService)
+	cartHandler := handlers.NewCartHandler(repos["cartRepo"].(interfaces.CartRepository), pricingService)
 	orderHandler := handlers.NewOrderHandler(services["OrderService"].(*services.OrderService))
+	paymentHandler := handlers.NewPaymentHandler(services["PaymentService"].(*services.PaymentService))
+	returnHandler := handlers.NewReturnHandler(services["ReturnService"].(*services.ReturnService))
+	shipmentHandler := handlers.NewShipmentHandler(services["ShipmentService"].(*services.ShipmentService))
+	invoiceHandler := handlers.NewInvoiceHandler(services["InvoiceService"].(*services.InvoiceService))
+	exportHandler := handlers.NewExportHandler(services["ExportService"].(*services.ExportService))
+	analyticsHandler := handlers.NewAnalyticsHandler(services["AnalyticsService"].(*services.AnalyticsService))
+	merchandisingHandler := handlers.NewMerchandisingHandler(services["MerchandisingService"].(*services.MerchandisingService))
+	reviewHandler := handlers.NewReviewHandler(services["ReviewService"].(*services.ReviewService))
+	questionHandler := handlers.NewQuestionHandler(services["QuestionService"].(*services.QuestionService))
+	categoryHandler := handlers.NewCategoryHandler(services["CategoryService"].(*services.CategoryService))
+	collectionHandler := handlers.NewCollectionHandler(services["CollectionService"].(*services.CollectionService), pricingService)
+	pricingHandler := handlers.NewPricingHandler(pricingService)
+	mediaHandler := handlers.NewMediaHandler(services["MediaService"].(*services.MediaService))
+	productImportHandler := handlers.NewProductImportHandler(services["ProductImportService"].(*services.ProductImportService))
+	recentlyViewedHandler := handlers.NewRecentlyViewedHandler(services["RecentlyViewedService"].(*services.RecentlyViewedService), pricingService)
 
 	return map[string]interface{}{
-		"authHandler":    authHandler,
-		"userHandler":    userHandler,
-		"productHandler": productHandler,
-		"cartHandler":    cartHandler,
-		"orderHandler":   orderHandler,
+		"authHandler":           authHandler,
+		"userHandler":           userHandler,
+		"productHandler":        productHandler,
+		"cartHandler":           cartHandler,
+		"orderHandler":          orderHandler,
+		"paymentHandler":        paymentHandler,
+		"returnHandler":         returnHandler,
+		"shipmentHandler":       shipmentHandler,
+		"invoiceHandler":        invoiceHandler,
+		"exportHandler":         exportHandler,
+		"analyticsHandler":      analyticsHandler,
+		"merchandisingHandler":  merchandisingHandler,
+		"recentlyViewedHandler": recentlyViewedHandler,
+		"reviewHandler":         reviewHandler,
+		"questionHandler":       questionHandler,
+		"categoryHandler":       categoryHandler,
+		"collectionHandler":     collectionHandler,
+		"pricingHandler":        pricingHandler,
+		"mediaHandler":          mediaHandler,
+		"productImportHandler":  productImportHandler,
 	}
 }
 
@@ -207,8 +408,12 @@ func createFiberApp() *fiber.App {
 		Level: compress.LevelBestSpeed,
 	}))
 
-	// Cache middleware for static responses
+	// Cache middleware for static responses. API responses are cached by
+	// CacheMiddleware, which knows when to invalidate them.
 	app.Use(cache.New(cache.Config{
+		Next: func(c *fiber.Ctx) bool {
+			return strings.HasPrefix(c.Path(), "/api/")
+		},
 		Expiration:   1 * time.Hour,
 		CacheControl: true,
 	}))
@@ -252,6 +457,11 @@ func setupRoutes(app *fiber.App, handlers map[string]interface{}) {
 		})
 	})
 
+	// Uploaded media, when stored on the local filesystem
+	if media := config.Load().Media; media.Store != "s3" {
+		app.Static(media.BaseURL, media.LocalDir, fiber.Static{MaxAge: 86400})
+	}
+
 	// API metrics endpoint
 	app.Get("/metrics", middleware.AdminOnly(), func(c *fiber.Ctx) error {
 		metrics := utils.GetMetrics()
@@ -282,30 +492,64 @@ func setupRoutes(app *fiber.App, handlers map[string]interface{}) {
 	app.Post("/api/logout", handlers["authHandler"].(*handlers.AuthHandler).Logout)
 
 	// Public product routes with caching
-	app.Get("/api/products", middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetAllProducts)
-	app.Get("/api/products/search", handlers["productHandler"].(*handlers.ProductHandler).SearchProducts)
-	app.Get("/api/products/categories", middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetProductCategories)
-	app.Get("/api/products/categories/:category", middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetProductsByCategory)
-	app.Get("/api/products/featured", middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetFeaturedProducts)
-	app.Get("/api/products/:id", middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetProductByID)
+	app.Get("/api/products", middleware.OptionalJWTMiddleware(), middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetAllProducts)
+	app.Get("/api/products/search", middleware.OptionalJWTMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).SearchProducts)
+	app.Get("/api/products/categories", middleware.CacheMiddleware(), handlers["categoryHandler"].(*handlers.CategoryHandler).ListCategories)
+	app.Get("/api/products/categories/tree", middleware.CacheMiddleware(), handlers["categoryHandler"].(*handlers.CategoryHandler).GetCategoryTree)
+	app.Get("/api/products/categories/:category", middleware.OptionalJWTMiddleware(), middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetProductsByCategory)
+	app.Get("/api/products/featured", middleware.OptionalJWTMiddleware(), middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetFeaturedProducts)
+	app.Get("/api/products/best-sellers", middleware.OptionalJWTMiddleware(), middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetBestSellers)
+	app.Get("/api/products/recently-viewed", middleware.GuestSession(), middleware.OptionalJWTMiddleware(), handlers["recentlyViewedHandler"].(*handlers.RecentlyViewedHandler).GetRecentlyViewed)
+	app.Get("/api/products/slug/:slug", middleware.GuestSession(), middleware.OptionalJWTMiddleware(), handlers["recentlyViewedHandler"].(*handlers.RecentlyViewedHandler).TrackView, handlers["productHandler"].(*handlers.ProductHandler).GetProductBySlug)
+	app.Get("/api/products/:id", middleware.GuestSession(), middleware.OptionalJWTMiddleware(), handlers["recentlyViewedHandler"].(*handlers.RecentlyViewedHandler).TrackView, middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetProductByID)
+	app.Get("/api/products/:id/related", middleware.OptionalJWTMiddleware(), middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetRelatedProducts)
+	app.Get("/api/products/:id/price-history", middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetPriceHistory)
+	app.Get("/api/products/:id/reviews", handlers["reviewHandler"].(*handlers.ReviewHandler).GetProductReviews)
+	app.Get("/api/products/:id/questions", handlers["questionHandler"].(*handlers.QuestionHandler).GetProductQuestions)
+	app.Get("/api/products/:id/questions/top", handlers["questionHandler"].(*handlers.QuestionHandler).GetTopAnsweredQuestions)
+	app.Get("/api/collections", middleware.CacheMiddleware(), handlers["collectionHandler"].(*handlers.CollectionHandler).ListCollections)
+	app.Get("/api/collections/:slug", middleware.OptionalJWTMiddleware(), middleware.CacheMiddleware(), handlers["collectionHandler"].(*handlers.CollectionHandler).GetCollection)
+	app.Get("/api/questions/:id/answers", handlers["questionHandler"].(*handlers.QuestionHandler).GetAnswers)
+	app.Get("/api/products/:id/bought-together", middleware.OptionalJWTMiddleware(), middleware.CacheMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).GetBoughtTogether)
 
 	// Protected user routes
 	api := app.Group("/api", middleware.JWTMiddleware())
 	api.Get("/user", handlers["userHandler"].(*handlers.UserHandler).GetUser)
 	api.Put("/user", handlers["userHandler"].(*handlers.UserHandler).UpdateUser)
 	api.Delete("/user", handlers["userHandler"].(*handlers.UserHandler).DeleteUser)
+	api.Get("/user/addresses", handlers["userHandler"].(*handlers.UserHandler).GetAddresses)
+	api.Post("/user/addresses", handlers["userHandler"].(*handlers.UserHandler).AddAddress)
+	api.Delete("/user/addresses/:addressId", handlers["userHandler"].(*handlers.UserHandler).DeleteAddress)
+	api.Put("/user/addresses/:addressId/default", handlers["userHandler"].(*handlers.UserHandler).SetDefaultAddress)
+	api.Post("/products/:id/reviews", handlers["reviewHandler"].(*handlers.ReviewHandler).CreateReview)
+	api.Post("/reviews/:id/helpful", handlers["reviewHandler"].(*handlers.ReviewHandler).MarkHelpful)
+	api.Post("/products/:id/questions", handlers["questionHandler"].(*handlers.QuestionHandler).AskQuestion)
+	api.Post("/questions/:id/answers", handlers["questionHandler"].(*handlers.QuestionHandler).AnswerQuestion)
+	api.Post("/answers/:id/upvote", handlers["questionHandler"].(*handlers.QuestionHandler).UpvoteAnswer)
 
 	// Product management (admin only)
 	productGroup := app.Group("/api/admin/products", middleware.AdminOnly())
-	productGroup.Post("/", handlers["productHandler"].(*handlers.ProductHandler).CreateProduct)
-	productGroup.Put("/:id", handlers["productHandler"].(*handlers.ProductHandler).UpdateProduct)
-	productGroup.Delete("/:id", handlers["productHandler"].(*handlers.ProductHandler).DeleteProduct)
+	productGroup.Get("/", handlers["productHandler"].(*handlers.ProductHandler).AdminListProducts)
+	productGroup.Post("/", middleware.CacheInvalidationMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).CreateProduct)
+	productGroup.Post("/import", middleware.CacheInvalidationMiddleware(), handlers["productImportHandler"].(*handlers.ProductImportHandler).ImportProducts)
+	productGroup.Get("/imports/:id", handlers["productImportHandler"].(*handlers.ProductImportHandler).GetImportJob)
+	productGroup.Get("/export", handlers["productImportHandler"].(*handlers.ProductImportHandler).ExportProducts)
+	productGroup.Get("/deleted", handlers["productHandler"].(*handlers.ProductHandler).ListDeletedProducts)
+	productGroup.Get("/:id", handlers["productHandler"].(*handlers.ProductHandler).PreviewProduct)
+	productGroup.Put("/:id", middleware.CacheInvalidationMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).UpdateProduct)
+	productGroup.Delete("/:id", middleware.CacheInvalidationMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).DeleteProduct)
+	productGroup.Post("/:id/restore", middleware.CacheInvalidationMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).RestoreProduct)
+	productGroup.Get("/:id/history", handlers["productHandler"].(*handlers.ProductHandler).GetProductHistory)
+	productGroup.Post("/:id/rollback", middleware.CacheInvalidationMiddleware(), handlers["productHandler"].(*handlers.ProductHandler).RollbackProduct)
+	productGroup.Post("/:id/images", middleware.CacheInvalidationMiddleware(), handlers["mediaHandler"].(*handlers.MediaHandler).UploadProductImage)
+	productGroup.Delete("/:id/images/:imageId", middleware.CacheInvalidationMiddleware(), handlers["mediaHandler"].(*handlers.MediaHandler).DeleteProductImage)
 
 	// Cart routes
-	app.Post("/api/cart/add", handlers["cartHandler"].(*handlers.CartHandler).AddToCart)
-	app.Get("/api/cart", handlers["cartHandler"].(*handlers.CartHandler).GetCart)
-	app.Delete("/api/cart/remove/:id", handlers["cartHandler"].(*handlers.CartHandler).RemoveFromCart)
-	app.Delete("/api/cart/clear", handlers["cartHandler"].(*handlers.CartHandler).ClearCart)
+	cartGroup := app.Group("/api/cart", middleware.GuestSession(), middleware.OptionalJWTMiddleware())
+	cartGroup.Post("/add", handlers["cartHandler"].(*handlers.CartHandler).AddToCart)
+	cartGroup.Get("/", handlers["cartHandler"].(*handlers.CartHandler).GetCart)
+	cartGroup.Delete("/remove/:id", handlers["cartHandler"].(*handlers.CartHandler).RemoveFromCart)
+	cartGroup.Delete("/clear", handlers["cartHandler"].(*handlers.CartHandler).ClearCart)
 
 	// Order routes
 	orderGroup := app.Group("/api/orders", middleware.JWTMiddleware())
@@ -313,10 +557,93 @@ func setupRoutes(app *fiber.App, handlers map[string]interface{}) {
 	orderGroup.Get("/", handlers["orderHandler"].(*handlers.OrderHandler).GetOrdersByUserID)
 	orderGroup.Get("/:id", handlers["orderHandler"].(*handlers.OrderHandler).GetOrderByID)
 	orderGroup.Put("/:id/cancel", handlers["orderHandler"].(*handlers.OrderHandler).CancelOrder)
+	orderGroup.Post("/:id/items/cancel", handlers["orderHandler"].(*handlers.OrderHandler).CancelOrderItems)
+	orderGroup.Post("/:id/returns", handlers["returnHandler"].(*handlers.ReturnHandler).RequestReturn)
+	orderGroup.Get("/:id/tracking", handlers["shipmentHandler"].(*handlers.ShipmentHandler).GetOrderTracking)
+	orderGroup.Get("/:id/invoice", handlers["invoiceHandler"].(*handlers.InvoiceHandler).GetInvoice)
+
+	// Return routes
+	returnGroup := app.Group("/api/returns", middleware.JWTMiddleware())
+	returnGroup.Get("/", handlers["returnHandler"].(*handlers.ReturnHandler).GetMyReturns)
+	returnGroup.Get("/:id", handlers["returnHandler"].(*handlers.ReturnHandler).GetReturnByID)
 
 	adminOrderGroup := app.Group("/api/admin/orders", middleware.AdminOnly())
+	adminOrderGroup.Get("/", handlers["orderHandler"].(*handlers.OrderHandler).ListOrders)
+	adminOrderGroup.Get("/export", handlers["exportHandler"].(*handlers.ExportHandler).ExportOrders)
+	adminOrderGroup.Get("/:id", handlers["orderHandler"].(*handlers.OrderHandler).GetOrderByID)
 	adminOrderGroup.Put("/:id/status", handlers["orderHandler"].(*handlers.OrderHandler).UpdateOrderStatus)
 	adminOrderGroup.Delete("/:id", handlers["orderHandler"].(*handlers.OrderHandler).DeleteOrder)
+	adminOrderGroup.Post("/:id/shipments", handlers["shipmentHandler"].(*handlers.ShipmentHandler).CreateShipment)
+	adminOrderGroup.Get("/:id/shipments", handlers["shipmentHandler"].(*handlers.ShipmentHandler).GetOrderTracking)
+
+	adminExportGroup := app.Group("/api/admin/exports", middleware.AdminOnly())
+	adminExportGroup.Get("/:id", handlers["exportHandler"].(*handlers.ExportHandler).GetExportJob)
+	adminExportGroup.Get("/:id/download", handlers["exportHandler"].(*handlers.ExportHandler).DownloadExport)
+
+	adminShipmentGroup := app.Group("/api/admin/shipments", middleware.AdminOnly())
+	adminShipmentGroup.Post("/:id/events", handlers["shipmentHandler"].(*handlers.ShipmentHandler).AddTrackingEvent)
+	adminShipmentGroup.Post("/:id/sync", handlers["shipmentHandler"].(*handlers.ShipmentHandler).SyncTracking)
+
+	adminCategoryGroup := app.Group("/api/admin/categories", middleware.AdminOnly(), middleware.CacheInvalidationMiddleware())
+	adminCategoryGroup.Get("/", handlers["categoryHandler"].(*handlers.CategoryHandler).AdminListCategories)
+	adminCategoryGroup.Post("/", handlers["categoryHandler"].(*handlers.CategoryHandler).CreateCategory)
+	adminCategoryGroup.Put("/:id", handlers["categoryHandler"].(*handlers.CategoryHandler).UpdateCategory)
+	adminCategoryGroup.Delete("/:id", handlers["categoryHandler"].(*handlers.CategoryHandler).DeleteCategory)
+
+	adminCollectionGroup := app.Group("/api/admin/collections", middleware.AdminOnly(), middleware.CacheInvalidationMiddleware())
+	adminCollectionGroup.Get("/", handlers["collectionHandler"].(*handlers.CollectionHandler).AdminListCollections)
+	adminCollectionGroup.Post("/", handlers["collectionHandler"].(*handlers.CollectionHandler).CreateCollection)
+	adminCollectionGroup.Put("/:id", handlers["collectionHandler"].(*handlers.CollectionHandler).UpdateCollection)
+	adminCollectionGroup.Delete("/:id", handlers["collectionHandler"].(*handlers.CollectionHandler).DeleteCollection)
+
+	adminFeaturedGroup := app.Group("/api/admin/featured", middleware.AdminOnly(), middleware.CacheInvalidationMiddleware())
+	adminFeaturedGroup.Get("/", handlers["merchandisingHandler"].(*handlers.MerchandisingHandler).ListFeatured)
+	adminFeaturedGroup.Post("/", handlers["merchandisingHandler"].(*handlers.MerchandisingHandler).AddFeatured)
+	adminFeaturedGroup.Put("/:id", handlers["merchandisingHandler"].(*handlers.MerchandisingHandler).UpdateFeatured)
+	adminFeaturedGroup.Delete("/:id", handlers["merchandisingHandler"].(*handlers.MerchandisingHandler).RemoveFeatured)
+	adminFeaturedGroup.Post("/best-sellers/refresh", handlers["merchandisingHandler"].(*handlers.MerchandisingHandler).RefreshBestSellers)
+	adminFeaturedGroup.Post("/bought-together/refresh", handlers["merchandisingHandler"].(*handlers.MerchandisingHandler).RefreshBoughtTogether)
+
+	adminAnalyticsGroup := app.Group("/api/admin/analytics", middleware.AdminOnly())
+	adminAnalyticsGroup.Get("/sales", handlers["analyticsHandler"].(*handlers.AnalyticsHandler).GetSales)
+	adminAnalyticsGroup.Get("/top-products", handlers["analyticsHandler"].(*handlers.AnalyticsHandler).GetTopProducts)
+	adminAnalyticsGroup.Get("/top-categories", handlers["analyticsHandler"].(*handlers.AnalyticsHandler).GetTopCategories)
+	adminAnalyticsGroup.Get("/funnel", handlers["analyticsHandler"].(*handlers.AnalyticsHandler).GetStatusFunnel)
+	adminAnalyticsGroup.Post("/refresh", handlers["analyticsHandler"].(*handlers.AnalyticsHandler).RefreshRollups)
+
+	adminReviewGroup := app.Group("/api/admin/reviews", middleware.AdminOnly(), middleware.CacheInvalidationMiddleware())
+	adminReviewGroup.Get("/", handlers["reviewHandler"].(*handlers.ReviewHandler).GetModerationQueue)
+	adminReviewGroup.Put("/:id/approve", handlers["reviewHandler"].(*handlers.ReviewHandler).ApproveReview)
+	adminReviewGroup.Put("/:id/reject", handlers["reviewHandler"].(*handlers.ReviewHandler).RejectReview)
+
+	adminAnswerGroup := app.Group("/api/admin/answers", middleware.AdminOnly())
+	adminAnswerGroup.Get("/", handlers["questionHandler"].(*handlers.QuestionHandler).GetModerationQueue)
+	adminAnswerGroup.Put("/:id/approve", handlers["questionHandler"].(*handlers.QuestionHandler).ApproveAnswer)
+	adminAnswerGroup.Put("/:id/reject", handlers["questionHandler"].(*handlers.QuestionHandler).RejectAnswer)
+	app.Post("/api/admin/questions/:id/answers", middleware.AdminOnly(), handlers["questionHandler"].(*handlers.QuestionHandler).AnswerAsStore)
+
+	adminReturnGroup := app.Group("/api/admin/returns", middleware.AdminOnly())
+	adminReturnGroup.Get("/", handlers["returnHandler"].(*handlers.ReturnHandler).GetReturns)
+	adminReturnGroup.Get("/:id", handlers["returnHandler"].(*handlers.ReturnHandler).GetReturnByID)
+	adminReturnGroup.Put("/:id/approve", handlers["returnHandler"].(*handlers.ReturnHandler).ApproveReturn)
+	adminReturnGroup.Put("/:id/reject", handlers["returnHandler"].(*handlers.ReturnHandler).RejectReturn)
+	adminReturnGroup.Put("/:id/receive", handlers["returnHandler"].(*handlers.ReturnHandler).MarkReturnReceived)
+	adminReturnGroup.Post("/:id/refund", handlers["returnHandler"].(*handlers.ReturnHandler).RefundReturn)
+
+	adminPricingGroup := app.Group("/api/admin/pricing", middleware.AdminOnly(), middleware.CacheInvalidationMiddleware())
+	adminPricingGroup.Get("/price-lists", handlers["pricingHandler"].(*handlers.PricingHandler).ListPriceLists)
+	adminPricingGroup.Post("/price-lists", handlers["pricingHandler"].(*handlers.PricingHandler).CreatePriceList)
+	adminPricingGroup.Put("/price-lists/:id", handlers["pricingHandler"].(*handlers.PricingHandler).UpdatePriceList)
+	adminPricingGroup.Delete("/price-lists/:id", handlers["pricingHandler"].(*handlers.PricingHandler).DeletePriceList)
+	app.Put("/api/admin/users/:id/customer-group", middleware.AdminOnly(), handlers["pricingHandler"].(*handlers.PricingHandler).SetCustomerGroup)
+	app.Put("/api/admin/users/:id/phone-verification", middleware.AdminOnly(), handlers["userHandler"].(*handlers.UserHandler).VerifyPhone)
+
+	// Payment reconciliation (admin only)
+	adminPaymentGroup := app.Group("/api/admin/payments", middleware.AdminOnly())
+	adminPaymentGroup.Get("/cod/pending", handlers["paymentHandler"].(*handlers.PaymentHandler).GetPendingCODPayments)
+	adminPaymentGroup.Post("/cod/reconcile", handlers["paymentHandler"].(*handlers.PaymentHandler).ReconcileCOD)
+	adminPaymentGroup.Get("/refunds/pending", handlers["paymentHandler"].(*handlers.PaymentHandler).GetUnsettledRefunds)
+	adminPaymentGroup.Post("/refunds/settle", handlers["paymentHandler"].(*handlers.PaymentHandler).SettleRefunds)
 
 	// 404 handler
 	app.Use(func(c *fiber.Ctx) error {
@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
 )
 
 // Config holds all configuration settings
@@ -15,6 +18,16 @@ type Config struct {
 	Logging  LoggingConfig
 	Monitoring MonitoringConfig
 	Performance PerformanceConfig
+	COD      CODConfig
+	Returns  ReturnsConfig
+	Invoice  InvoiceConfig
+	Export   ExportConfig
+	Import   ImportConfig
+	Analytics AnalyticsConfig
+	Merchandising MerchandisingConfig
+	RecentlyViewed RecentlyViewedConfig
+	Media MediaConfig
+	Catalog CatalogConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -127,6 +140,97 @@ type PerformanceConfig struct {
 	CompressionMinSize int
 }
 
+// CODConfig holds cash-on-delivery eligibility and fee configuration
+type CODConfig struct {
+	Enabled             bool
+	MaxOrderValue       models.Money
+	ServiceablePincodes []string
+	Fee                 models.Money
+}
+
+// ReturnsConfig holds return and refund policy configuration
+type ReturnsConfig struct {
+	Window              time.Duration
+	RefundRetryInterval time.Duration
+}
+
+// InvoiceConfig holds the seller details and tax rate printed on invoices
+type InvoiceConfig struct {
+	SellerName    string
+	SellerAddress string
+	GSTIN         string
+	Prefix        string
+	TaxRate       float64
+	Timezone      string
+}
+
+// ExportConfig holds settings for admin data exports
+type ExportConfig struct {
+	Dir       string
+	SyncLimit int
+}
+
+// ImportConfig holds settings for bulk product imports
+type ImportConfig struct {
+	SyncLimit int
+	BatchSize int
+}
+
+// AnalyticsConfig holds settings for the sales rollup refresh
+type AnalyticsConfig struct {
+	RefreshInterval time.Duration
+	Lookback        time.Duration
+	Timezone        string
+}
+
+// MerchandisingConfig holds settings for computed product lists
+type MerchandisingConfig struct {
+	BestSellerWindow          time.Duration
+	BestSellerRefreshInterval time.Duration
+	BestSellerSize            int
+	BoughtTogetherWindow      time.Duration
+	BoughtTogetherInterval    time.Duration
+	BoughtTogetherSize        int
+}
+
+// RecentlyViewedConfig holds settings for per-shopper view history
+type RecentlyViewedConfig struct {
+	Limit int
+	TTL   time.Duration
+}
+
+// CatalogConfig holds settings for the product lifecycle
+type CatalogConfig struct {
+	// PublishInterval is how often scheduled publishing and unpublishing
+	// is applied.
+	PublishInterval time.Duration
+	// DeletedRetention is how long soft deleted products can be restored
+	// before the purge job removes them for good.
+	DeletedRetention time.Duration
+	PurgeInterval    time.Duration
+	// SaleWatchInterval is how often the cached listings are checked
+	// against sales starting or ending, so a cached price is never stale
+	// by more than this.
+	SaleWatchInterval time.Duration
+}
+
+// MediaConfig holds settings for uploaded product images. Store is either
+// "local", which writes under LocalDir and serves files from BaseURL, or
+// "s3", which talks to any S3-compatible endpoint.
+type MediaConfig struct {
+	Store         string
+	MaxUploadSize int
+	JPEGQuality   int
+	LocalDir      string
+	BaseURL       string
+	S3Endpoint    string
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3PublicURL   string
+}
+
 // Load loads configuration from environment variables
 func Load() *Config {
 	return &Config{
@@ -137,6 +241,16 @@ func Load() *Config {
 		Logging:  loadLoggingConfig(),
 		Monitoring: loadMonitoringConfig(),
 		Performance: loadPerformanceConfig(),
+		COD:      loadCODConfig(),
+		Returns:  loadReturnsConfig(),
+		Invoice:  loadInvoiceConfig(),
+		Export:   loadExportConfig(),
+		Import:   loadImportConfig(),
+		Analytics: loadAnalyticsConfig(),
+		Merchandising: loadMerchandisingConfig(),
+		RecentlyViewed: loadRecentlyViewedConfig(),
+		Media: loadMediaConfig(),
+		Catalog: loadCatalogConfig(),
 	}
 }
 
@@ -248,6 +362,98 @@ func loadPerformanceConfig() PerformanceConfig {
 	}
 }
 
+func loadCODConfig() CODConfig {
+	return CODConfig{
+		Enabled:             getBoolEnv("COD_ENABLED", true),
+		MaxOrderValue:       getMoneyEnv("COD_MAX_ORDER_VALUE", "10000"),
+		ServiceablePincodes: getStringSliceEnv("COD_SERVICEABLE_PINCODES", []string{}),
+		Fee:                 getMoneyEnv("COD_FEE", "0"),
+	}
+}
+
+func loadReturnsConfig() ReturnsConfig {
+	return ReturnsConfig{
+		Window:              getDurationEnv("RETURN_WINDOW", 7*24*time.Hour),
+		RefundRetryInterval: getDurationEnv("REFUND_RETRY_INTERVAL", 5*time.Minute),
+	}
+}
+
+func loadInvoiceConfig() InvoiceConfig {
+	return InvoiceConfig{
+		SellerName:    getEnv("INVOICE_SELLER_NAME", "Masked 11"),
+		SellerAddress: getEnv("INVOICE_SELLER_ADDRESS", ""),
+		GSTIN:         getEnv("INVOICE_GSTIN", ""),
+		Prefix:        getEnv("INVOICE_PREFIX", "INV"),
+		TaxRate:       getFloatEnv("INVOICE_TAX_RATE", 0.18),
+		Timezone:      getEnv("INVOICE_TIMEZONE", "Asia/Kolkata"),
+	}
+}
+
+func loadExportConfig() ExportConfig {
+	return ExportConfig{
+		Dir:       getEnv("EXPORT_DIR", "./exports"),
+		SyncLimit: getIntEnv("EXPORT_SYNC_LIMIT", 5000),
+	}
+}
+
+func loadImportConfig() ImportConfig {
+	return ImportConfig{
+		SyncLimit: getIntEnv("IMPORT_SYNC_LIMIT", 200),
+		BatchSize: getIntEnv("IMPORT_BATCH_SIZE", 500),
+	}
+}
+
+func loadAnalyticsConfig() AnalyticsConfig {
+	return AnalyticsConfig{
+		RefreshInterval: getDurationEnv("ANALYTICS_REFRESH_INTERVAL", 15*time.Minute),
+		Lookback:        getDurationEnv("ANALYTICS_REFRESH_LOOKBACK", 30*24*time.Hour),
+		Timezone:        getEnv("ANALYTICS_TIMEZONE", "Asia/Kolkata"),
+	}
+}
+
+func loadMerchandisingConfig() MerchandisingConfig {
+	return MerchandisingConfig{
+		BestSellerWindow:          getDurationEnv("BESTSELLER_WINDOW", 30*24*time.Hour),
+		BestSellerRefreshInterval: getDurationEnv("BESTSELLER_REFRESH_INTERVAL", time.Hour),
+		BestSellerSize:            getIntEnv("BESTSELLER_SIZE", 50),
+		BoughtTogetherWindow:      getDurationEnv("BOUGHT_TOGETHER_WINDOW", 180*24*time.Hour),
+		BoughtTogetherInterval:    getDurationEnv("BOUGHT_TOGETHER_REFRESH_INTERVAL", 24*time.Hour),
+		BoughtTogetherSize:        getIntEnv("BOUGHT_TOGETHER_SIZE", 10),
+	}
+}
+
+func loadRecentlyViewedConfig() RecentlyViewedConfig {
+	return RecentlyViewedConfig{
+		Limit: getIntEnv("RECENTLY_VIEWED_LIMIT", 20),
+		TTL:   getDurationEnv("RECENTLY_VIEWED_TTL", 30*24*time.Hour),
+	}
+}
+
+func loadCatalogConfig() CatalogConfig {
+	return CatalogConfig{
+		PublishInterval:   getDurationEnv("PRODUCT_PUBLISH_INTERVAL", time.Minute),
+		DeletedRetention:  getDurationEnv("PRODUCT_DELETED_RETENTION", 30*24*time.Hour),
+		PurgeInterval:     getDurationEnv("PRODUCT_PURGE_INTERVAL", 6*time.Hour),
+		SaleWatchInterval: getDurationEnv("PRODUCT_SALE_WATCH_INTERVAL", time.Minute),
+	}
+}
+
+func loadMediaConfig() MediaConfig {
+	return MediaConfig{
+		Store:         getEnv("MEDIA_STORE", "local"),
+		MaxUploadSize: getIntEnv("MEDIA_MAX_UPLOAD_SIZE", 8*1024*1024),
+		JPEGQuality:   getIntEnv("MEDIA_JPEG_QUALITY", 85),
+		LocalDir:      getEnv("MEDIA_DIR", "./media"),
+		BaseURL:       getEnv("MEDIA_BASE_URL", "/media"),
+		S3Endpoint:    getEnv("MEDIA_S3_ENDPOINT", ""),
+		S3Region:      getEnv("MEDIA_S3_REGION", "us-east-1"),
+		S3Bucket:      getEnv("MEDIA_S3_BUCKET", ""),
+		S3AccessKey:   getEnv("MEDIA_S3_ACCESS_KEY", ""),
+		S3SecretKey:   getEnv("MEDIA_S3_SECRET_KEY", ""),
+		S3PublicURL:   getEnv("MEDIA_S3_PUBLIC_URL", ""),
+	}
+}
+
 // Helper functions for environment variable parsing
 
 func getEnv(key, defaultValue string) string {
@@ -266,6 +472,27 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getMoneyEnv reads an amount in major units of the store's currency, such
+// as "49.50".
+func getMoneyEnv(key string, defaultValue string) models.Money {
+	if value := os.Getenv(key); value != "" {
+		if amount, err := models.ParseMoney(value, models.DefaultCurrency); err == nil {
+			return amount
+		}
+	}
+	amount, _ := models.ParseMoney(defaultValue, models.DefaultCurrency)
+	return amount
+}
+
 func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -287,8 +514,14 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 func getStringSliceEnv(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		// Simple comma-separated parsing
-		// In production, you might want more sophisticated parsing
-		return []string{value}
+		parts := strings.Split(value, ",")
+		values := make([]string, 0, len(parts))
+		for _, part := range parts {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+		return values
 	}
 	return defaultValue
 }
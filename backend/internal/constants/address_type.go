@@ -0,0 +1,6 @@
+package constants
+
+const (
+	AddressTypeShipping = "SHIPPING"
+	AddressTypeBilling  = "BILLING"
+)
@@ -0,0 +1,7 @@
+package constants
+
+const (
+	AnswerStatusPending  = "PENDING"
+	AnswerStatusApproved = "APPROVED"
+	AnswerStatusRejected = "REJECTED"
+)
@@ -0,0 +1,8 @@
+package constants
+
+const (
+	// CollectionTypeManual collections list hand-picked products in order.
+	CollectionTypeManual = "MANUAL"
+	// CollectionTypeRule collections list whatever products match their rules.
+	CollectionTypeRule = "RULE"
+)
@@ -0,0 +1,13 @@
+package constants
+
+const (
+	JobTypeOrderExport   = "ORDER_EXPORT"
+	JobTypeProductImport = "PRODUCT_IMPORT"
+)
+
+const (
+	JobStatusPending   = "PENDING"
+	JobStatusRunning   = "RUNNING"
+	JobStatusCompleted = "COMPLETED"
+	JobStatusFailed    = "FAILED"
+)
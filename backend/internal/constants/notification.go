@@ -0,0 +1,5 @@
+package constants
+
+const (
+	NotificationQuestionAnswered = "QUESTION_ANSWERED"
+)
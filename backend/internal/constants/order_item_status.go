@@ -0,0 +1,6 @@
+package constants
+
+const (
+	OrderItemStatusActive    = "ACTIVE"
+	OrderItemStatusCancelled = "CANCELLED"
+)
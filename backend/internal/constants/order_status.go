@@ -1,12 +1,13 @@
 package constants
 
 const (
-	OrderStatusPending    = "PENDING"
-	OrderStatusPaid       = "PAID"
-	OrderStatusCancelled  = "CANCELLED"
-	OrderStatusShipped    = "SHIPPED"
-	OrderStatusDelivered  = "DELIVERED"
-	OrderStatusFailed     = "FAILED"
-	OrderStatusRefunder   = "REFUNDED"
-	OrderStatusCODPending = "COD_PENDING"
+	OrderStatusPending          = "PENDING"
+	OrderStatusPaid             = "PAID"
+	OrderStatusCancelled        = "CANCELLED"
+	OrderStatusShipped          = "SHIPPED"
+	OrderStatusPartiallyShipped = "PARTIALLY_SHIPPED"
+	OrderStatusDelivered        = "DELIVERED"
+	OrderStatusFailed           = "FAILED"
+	OrderStatusRefunder         = "REFUNDED"
+	OrderStatusCODPending       = "COD_PENDING"
 )
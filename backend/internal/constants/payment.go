@@ -0,0 +1,22 @@
+package constants
+
+const (
+	PaymentMethodOnline = "ONLINE"
+	PaymentMethodCOD    = "COD"
+)
+
+const (
+	PaymentStatusPending    = "PENDING"
+	PaymentStatusCaptured   = "CAPTURED"
+	PaymentStatusCollected  = "COLLECTED"
+	PaymentStatusReconciled = "RECONCILED"
+)
+
+// A refund is PENDING from when it is recorded until the gateway takes it.
+// Gateways that only hand it over to be paid by hand leave it in
+// SETTLEMENT_PENDING until finance marks it settled.
+const (
+	RefundStatusPending           = "PENDING"
+	RefundStatusSettlementPending = "SETTLEMENT_PENDING"
+	RefundStatusCompleted         = "COMPLETED"
+)
@@ -0,0 +1,9 @@
+package constants
+
+// Actions recorded in a product's change history.
+const (
+	ProductChangeCreate   = "CREATE"
+	ProductChangeUpdate   = "UPDATE"
+	ProductChangeRollback = "ROLLBACK"
+	ProductChangeImport   = "IMPORT"
+)
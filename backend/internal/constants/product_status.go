@@ -0,0 +1,12 @@
+package constants
+
+const (
+	// ProductStatusDraft products are only visible to admins.
+	ProductStatusDraft = "DRAFT"
+	// ProductStatusScheduled products go live at their publishAt time.
+	ProductStatusScheduled = "SCHEDULED"
+	// ProductStatusActive products are shown on the storefront.
+	ProductStatusActive = "ACTIVE"
+	// ProductStatusArchived products have been taken off the storefront.
+	ProductStatusArchived = "ARCHIVED"
+)
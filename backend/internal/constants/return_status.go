@@ -0,0 +1,9 @@
+package constants
+
+const (
+	ReturnStatusRequested = "REQUESTED"
+	ReturnStatusApproved  = "APPROVED"
+	ReturnStatusRejected  = "REJECTED"
+	ReturnStatusReceived  = "RECEIVED"
+	ReturnStatusRefunded  = "REFUNDED"
+)
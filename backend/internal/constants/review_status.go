@@ -0,0 +1,7 @@
+package constants
+
+const (
+	ReviewStatusPending  = "PENDING"
+	ReviewStatusApproved = "APPROVED"
+	ReviewStatusRejected = "REJECTED"
+)
@@ -0,0 +1,9 @@
+package constants
+
+const (
+	ShipmentStatusShipped        = "SHIPPED"
+	ShipmentStatusInTransit      = "IN_TRANSIT"
+	ShipmentStatusOutForDelivery = "OUT_FOR_DELIVERY"
+	ShipmentStatusDelivered      = "DELIVERED"
+	ShipmentStatusException      = "EXCEPTION"
+)
@@ -98,8 +98,8 @@ func ensureIndexes() error {
 			Options: options.Index().SetName("category_index"),
 		},
 		{
-			Keys: bson.D{{Key: "price", Value: 1}},
-			Options: options.Index().SetName("price_index"),
+			Keys: bson.D{{Key: "price.amount", Value: 1}},
+			Options: options.Index().SetName("price_amount_index"),
 		},
 		{
 			Keys: bson.D{{Key: "createdAt", Value: -1}},
@@ -115,9 +115,9 @@ func ensureIndexes() error {
 		{
 			Keys: bson.D{
 				{Key: "category", Value: 1},
-				{Key: "price", Value: 1},
+				{Key: "price.amount", Value: 1},
 			},
-			Options: options.Index().SetName("category_price_index"),
+			Options: options.Index().SetName("category_price_amount_index"),
 		},
 		{
 			Keys: bson.D{
@@ -126,6 +126,214 @@ func ensureIndexes() error {
 			},
 			Options: options.Index().SetName("stock_category_index"),
 		},
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}},
+			Options: options.Index().SetName("tags_index"),
+		},
+		{
+			Keys: bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("slug_unique").
+				SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys: bson.D{{Key: "sku", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("sku_unique").
+				SetPartialFilterExpression(bson.M{"sku": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys:    bson.D{{Key: "previousSlugs", Value: 1}},
+			Options: options.Index().SetName("previous_slugs_index"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "categoryId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("category_id_created_index"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "ratingAverage", Value: -1},
+				{Key: "ratingCount", Value: -1},
+			},
+			Options: options.Index().SetName("rating_index"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("status_created_index"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "publishAt", Value: 1},
+			},
+			Options: options.Index().SetName("status_publish_at_index"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "unpublishAt", Value: 1},
+			},
+			Options: options.Index().SetName("status_unpublish_at_index"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "sales.startsAt", Value: 1},
+				{Key: "sales.endsAt", Value: 1},
+			},
+			Options: options.Index().SetName("sales_window_index").
+				SetPartialFilterExpression(bson.M{"sales": bson.M{"$type": "array"}}),
+		},
+		{
+			Keys: bson.D{{Key: "deletedAt", Value: 1}},
+			Options: options.Index().SetName("deleted_at_index").
+				SetPartialFilterExpression(bson.M{"deletedAt": bson.M{"$type": "date"}}),
+		},
+	}
+
+	// Category indexes
+	categoryColl := Mongo.Collection("categories")
+	categoryIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("slug_unique"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "parentId", Value: 1},
+				{Key: "sortOrder", Value: 1},
+			},
+			Options: options.Index().SetName("parent_sort_index"),
+		},
+	}
+
+	// Collection indexes
+	collectionColl := Mongo.Collection("collections")
+	collectionIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("slug_unique"),
+		},
+	}
+
+	// Product history indexes
+	productVersionColl := Mongo.Collection("product_versions")
+	productVersionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "productId", Value: 1},
+				{Key: "version", Value: -1},
+			},
+			Options: options.Index().SetUnique(true).SetName("product_version_unique"),
+		},
+	}
+
+	// Price list indexes
+	priceListColl := Mongo.Collection("price_lists")
+	priceListIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "group", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("group_unique"),
+		},
+	}
+
+	// Featured list indexes
+	featuredColl := Mongo.Collection("featured_products")
+	featuredIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "startsAt", Value: 1},
+				{Key: "position", Value: 1},
+			},
+			Options: options.Index().SetName("starts_at_position_index"),
+		},
+	}
+
+	// Review indexes
+	reviewColl := Mongo.Collection("reviews")
+	reviewIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "productId", Value: 1},
+				{Key: "userId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true).SetName("product_user_unique"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "productId", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("product_status_created_index"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("status_created_index"),
+		},
+	}
+	reviewVoteColl := Mongo.Collection("review_votes")
+	reviewVoteIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "reviewId", Value: 1},
+				{Key: "userId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true).SetName("review_user_unique"),
+		},
+	}
+
+	// Q&A indexes
+	questionColl := Mongo.Collection("questions")
+	questionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "productId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("product_created_index"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "productId", Value: 1},
+				{Key: "answerCount", Value: -1},
+			},
+			Options: options.Index().SetName("product_answer_count_index"),
+		},
+	}
+	answerColl := Mongo.Collection("answers")
+	answerIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "questionId", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "upvotes", Value: -1},
+			},
+			Options: options.Index().SetName("question_status_upvotes_index"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "createdAt", Value: 1},
+			},
+			Options: options.Index().SetName("status_created_index"),
+		},
+	}
+	answerVoteColl := Mongo.Collection("answer_votes")
+	answerVoteIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "answerId", Value: 1},
+				{Key: "userId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true).SetName("answer_user_unique"),
+		},
 	}
 
 	// Create user indexes
@@ -144,6 +352,80 @@ func ensureIndexes() error {
 		}
 	}
 
+	// Create category indexes
+	for _, index := range categoryIndexes {
+		_, err := categoryColl.Indexes().CreateOne(context.TODO(), index)
+		if err != nil {
+			log.Printf("Warning: Failed to create category index: %v", err)
+		}
+	}
+
+	// Create collection indexes
+	for _, index := range collectionIndexes {
+		_, err := collectionColl.Indexes().CreateOne(context.TODO(), index)
+		if err != nil {
+			log.Printf("Warning: Failed to create collection index: %v", err)
+		}
+	}
+
+	// Create product history indexes
+	for _, index := range productVersionIndexes {
+		_, err := productVersionColl.Indexes().CreateOne(context.TODO(), index)
+		if err != nil {
+			log.Printf("Warning: Failed to create product history index: %v", err)
+		}
+	}
+
+	// Create price list indexes
+	for _, index := range priceListIndexes {
+		_, err := priceListColl.Indexes().CreateOne(context.TODO(), index)
+		if err != nil {
+			log.Printf("Warning: Failed to create price list index: %v", err)
+		}
+	}
+
+	// Create featured list indexes
+	for _, index := range featuredIndexes {
+		_, err := featuredColl.Indexes().CreateOne(context.TODO(), index)
+		if err != nil {
+			log.Printf("Warning: Failed to create featured index: %v", err)
+		}
+	}
+
+	// Create review indexes
+	for _, index := range reviewIndexes {
+		_, err := reviewColl.Indexes().CreateOne(context.TODO(), index)
+		if err != nil {
+			log.Printf("Warning: Failed to create review index: %v", err)
+		}
+	}
+	for _, index := range reviewVoteIndexes {
+		_, err := reviewVoteColl.Indexes().CreateOne(context.TODO(), index)
+		if err != nil {
+			log.Printf("Warning: Failed to create review vote index: %v", err)
+		}
+	}
+
+	// Create Q&A indexes
+	for _, index := range questionIndexes {
+		_, err := questionColl.Indexes().CreateOne(context.TODO(), index)
+		if err != nil {
+			log.Printf("Warning: Failed to create question index: %v", err)
+		}
+	}
+	for _, index := range answerIndexes {
+		_, err := answerColl.Indexes().CreateOne(context.TODO(), index)
+		if err != nil {
+			log.Printf("Warning: Failed to create answer index: %v", err)
+		}
+	}
+	for _, index := range answerVoteIndexes {
+		_, err := answerVoteColl.Indexes().CreateOne(context.TODO(), index)
+		if err != nil {
+			log.Printf("Warning: Failed to create answer vote index: %v", err)
+		}
+	}
+
 	return nil
 }
 
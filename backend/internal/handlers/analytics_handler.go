@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type AnalyticsHandler struct {
+	AnalyticsService *services.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{AnalyticsService: analyticsService}
+}
+
+// GetSales reports revenue, order count, AOV, new vs returning customers and
+// refund rate for a date range, optionally against a comparison period.
+func (h *AnalyticsHandler) GetSales(c *fiber.Ctx) error {
+	from, to, err := h.dateRange(c)
+	if err != nil {
+		return err
+	}
+
+	interval := strings.ToLower(c.Query("interval", "day"))
+	compare := strings.ToLower(c.Query("compare"))
+	report, err := h.AnalyticsService.GetSalesReport(c.Context(), from, to, interval, compare)
+	if err != nil {
+		return analyticsError(err, "Failed to fetch sales report")
+	}
+	return c.JSON(report)
+}
+
+func (h *AnalyticsHandler) GetTopProducts(c *fiber.Ctx) error {
+	from, to, err := h.dateRange(c)
+	if err != nil {
+		return err
+	}
+
+	products, err := h.AnalyticsService.GetTopProducts(c.Context(), from, to, analyticsLimit(c))
+	if err != nil {
+		return analyticsError(err, "Failed to fetch top products")
+	}
+	return c.JSON(fiber.Map{"products": products})
+}
+
+func (h *AnalyticsHandler) GetTopCategories(c *fiber.Ctx) error {
+	from, to, err := h.dateRange(c)
+	if err != nil {
+		return err
+	}
+
+	categories, err := h.AnalyticsService.GetTopCategories(c.Context(), from, to, analyticsLimit(c))
+	if err != nil {
+		return analyticsError(err, "Failed to fetch top categories")
+	}
+	return c.JSON(fiber.Map{"categories": categories})
+}
+
+func (h *AnalyticsHandler) GetStatusFunnel(c *fiber.Ctx) error {
+	from, to, err := h.dateRange(c)
+	if err != nil {
+		return err
+	}
+
+	funnel, err := h.AnalyticsService.GetStatusFunnel(c.Context(), from, to)
+	if err != nil {
+		return analyticsError(err, "Failed to fetch order funnel")
+	}
+	return c.JSON(fiber.Map{"funnel": funnel})
+}
+
+// RefreshRollups rebuilds the sales rollups now instead of waiting for the
+// next scheduled run.
+func (h *AnalyticsHandler) RefreshRollups(c *fiber.Ctx) error {
+	if err := h.AnalyticsService.Refresh(c.Context()); err != nil {
+		log.Println("RefreshRollups error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to refresh sales rollups")
+	}
+	return c.JSON(fiber.Map{"message": "Sales rollups refreshed"})
+}
+
+func (h *AnalyticsHandler) dateRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	from, to, err := h.AnalyticsService.ParseRange(c.Query("from"), c.Query("to"))
+	if err != nil {
+		return time.Time{}, time.Time{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return from, to, nil
+}
+
+func analyticsLimit(c *fiber.Ctx) int {
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	return limit
+}
+
+// analyticsError maps analytics errors onto HTTP statuses.
+func analyticsError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidDateRange),
+		errors.Is(err, services.ErrInvalidInterval),
+		errors.Is(err, services.ErrInvalidCompare):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	log.Println("Analytics error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, fallback)
+}
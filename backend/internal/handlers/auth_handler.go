@@ -15,14 +15,16 @@ import (
 )
 
 type AuthHandler struct {
-	AuthService *services.AuthService
-	SessionRepo *redisrepo.SessionRepository
+	AuthService           *services.AuthService
+	SessionRepo           *redisrepo.SessionRepository
+	RecentlyViewedService *services.RecentlyViewedService
 }
 
-func NewAuthHandler(authService *services.AuthService, sessionRepo *redisrepo.SessionRepository) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, sessionRepo *redisrepo.SessionRepository, recentlyViewedService *services.RecentlyViewedService) *AuthHandler {
 	return &AuthHandler{
-		AuthService: authService,
-		SessionRepo: sessionRepo,
+		AuthService:           authService,
+		SessionRepo:           sessionRepo,
+		RecentlyViewedService: recentlyViewedService,
 	}
 }
 
@@ -104,6 +106,12 @@ func (h *AuthHandler) LoginUser(c *fiber.Ctx) error {
 		MaxAge:   60 * 60 * 24 * 7,
 	})
 
+	if sessionID := c.Cookies("guest_sid"); sessionID != "" {
+		if err := h.RecentlyViewedService.MergeGuestHistory(sessionID, user.ID.Hex()); err != nil {
+			log.Println("MergeGuestHistory error:", err)
+		}
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Login successful",
 	})
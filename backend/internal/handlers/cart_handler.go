@@ -7,15 +7,17 @@ import (
 
 	"github.com/Shrey-Yash/Masked11/internal/models"
 	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/services"
 	"github.com/Shrey-Yash/Masked11/internal/utils"
 )
 
 type CartHandler struct {
-	CartRepo interfaces.CartRepository
+	CartRepo       interfaces.CartRepository
+	PricingService *services.PricingService
 }
 
-func NewCartHandler(cartRepo interfaces.CartRepository) *CartHandler {
-	return &CartHandler{CartRepo: cartRepo}
+func NewCartHandler(cartRepo interfaces.CartRepository, pricingService *services.PricingService) *CartHandler {
+	return &CartHandler{CartRepo: cartRepo, PricingService: pricingService}
 }
 
 func (h *CartHandler) AddToCart(c *fiber.Ctx) error {
@@ -45,17 +47,23 @@ func (h *CartHandler) AddToCart(c *fiber.Ctx) error {
 	for i, ci := range cart.Items {
 		if ci.ProductID == item.ProductID && ci.Size == item.Size {
 			cart.Items[i].Quantity += item.Quantity
-			cart.Items[i].Subtotal = float64(cart.Items[i].Quantity) * cart.Items[i].Price
+			cart.Items[i].Subtotal = cart.Items[i].Price.Mul(cart.Items[i].Quantity)
 			updated = true
 			break
 		}
 	}
 
 	if !updated {
-		item.Subtotal = float64(item.Quantity) * item.Price
+		item.Subtotal = item.Price.Mul(item.Quantity)
 		cart.Items = append(cart.Items, item)
 	}
 
+	// Prices always come from the catalog, never from the client.
+	userID, _ := c.Locals("userID").(string)
+	if err := h.PricingService.PriceCartItems(c.Context(), userID, cart.Items); err != nil {
+		return pricingError(err, "Failed to price cart")
+	}
+
 	cart.UpdatedAt = time.Now()
 	if err := h.CartRepo.SetCart(key, cart); err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update cart")
@@ -78,6 +86,13 @@ func (h *CartHandler) GetCart(c *fiber.Ctx) error {
 		return c.JSON(&models.Cart{UserID: key, Items: []models.CartItem{}})
 	}
 
+	// Reprice on every read so sales that started or ended since an item was
+	// added show up before checkout.
+	userID, _ := c.Locals("userID").(string)
+	if err := h.PricingService.PriceCartItems(c.Context(), userID, cart.Items); err != nil {
+		return pricingError(err, "Failed to price cart")
+	}
+
 	return c.JSON(cart)
 }
 
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type CategoryHandler struct {
+	CategoryService *services.CategoryService
+}
+
+func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{CategoryService: categoryService}
+}
+
+// ListCategories returns the visible categories as a flat list in display
+// order.
+func (h *CategoryHandler) ListCategories(c *fiber.Ctx) error {
+	categories, err := h.CategoryService.ListCategories(c.Context(), false)
+	if err != nil {
+		log.Println("ListCategories error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch categories")
+	}
+	return c.JSON(fiber.Map{"categories": categories})
+}
+
+func (h *CategoryHandler) GetCategoryTree(c *fiber.Ctx) error {
+	tree, err := h.CategoryService.GetTree(c.Context())
+	if err != nil {
+		log.Println("GetCategoryTree error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch categories")
+	}
+	return c.JSON(fiber.Map{"categories": tree})
+}
+
+// AdminListCategories returns every category, hidden ones included.
+func (h *CategoryHandler) AdminListCategories(c *fiber.Ctx) error {
+	categories, err := h.CategoryService.ListCategories(c.Context(), true)
+	if err != nil {
+		log.Println("AdminListCategories error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch categories")
+	}
+	return c.JSON(fiber.Map{"categories": categories})
+}
+
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
+	var req models.CategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	category, err := h.CategoryService.CreateCategory(c.Context(), req)
+	if err != nil {
+		return categoryError(err, "Failed to create category")
+	}
+	return c.Status(fiber.StatusCreated).JSON(category)
+}
+
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	var req models.CategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	category, err := h.CategoryService.UpdateCategory(c.Context(), c.Params("id"), req)
+	if err != nil {
+		return categoryError(err, "Failed to update category")
+	}
+	return c.JSON(category)
+}
+
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	if err := h.CategoryService.DeleteCategory(c.Context(), c.Params("id")); err != nil {
+		return categoryError(err, "Failed to delete category")
+	}
+	return c.JSON(fiber.Map{"message": "Category deleted"})
+}
+
+// categoryError maps category errors onto HTTP statuses.
+func categoryError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidCategory):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, interfaces.ErrCategorySlugTaken), errors.Is(err, services.ErrCategoryInUse):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	log.Println("Categories error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, fallback)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type CollectionHandler struct {
+	CollectionService *services.CollectionService
+	PricingService    *services.PricingService
+}
+
+func NewCollectionHandler(collectionService *services.CollectionService, pricingService *services.PricingService) *CollectionHandler {
+	return &CollectionHandler{CollectionService: collectionService, PricingService: pricingService}
+}
+
+func (h *CollectionHandler) ListCollections(c *fiber.Ctx) error {
+	collections, err := h.CollectionService.ListCollections(c.Context(), false)
+	if err != nil {
+		log.Println("ListCollections error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch collections")
+	}
+	return c.JSON(fiber.Map{"collections": collections})
+}
+
+// GetCollection returns a collection with one page of its products.
+func (h *CollectionHandler) GetCollection(c *fiber.Ctx) error {
+	page, limit := pageParams(c)
+	collection, products, total, err := h.CollectionService.GetCollectionProducts(c.Context(), c.Params("slug"), page, limit)
+	if err != nil {
+		return collectionError(err, "Failed to fetch collection")
+	}
+	if err := priceProducts(c, h.PricingService, products...); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"collection": collection,
+		"products":   products,
+		"pagination": pageInfo(page, limit, total),
+	})
+}
+
+// AdminListCollections returns every collection, hidden ones included.
+func (h *CollectionHandler) AdminListCollections(c *fiber.Ctx) error {
+	collections, err := h.CollectionService.ListCollections(c.Context(), true)
+	if err != nil {
+		log.Println("AdminListCollections error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch collections")
+	}
+	return c.JSON(fiber.Map{"collections": collections})
+}
+
+func (h *CollectionHandler) CreateCollection(c *fiber.Ctx) error {
+	var req models.CollectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	collection, err := h.CollectionService.CreateCollection(c.Context(), req)
+	if err != nil {
+		return collectionError(err, "Failed to create collection")
+	}
+	return c.Status(fiber.StatusCreated).JSON(collection)
+}
+
+func (h *CollectionHandler) UpdateCollection(c *fiber.Ctx) error {
+	var req models.CollectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	collection, err := h.CollectionService.UpdateCollection(c.Context(), c.Params("id"), req)
+	if err != nil {
+		return collectionError(err, "Failed to update collection")
+	}
+	return c.JSON(collection)
+}
+
+func (h *CollectionHandler) DeleteCollection(c *fiber.Ctx) error {
+	if err := h.CollectionService.DeleteCollection(c.Context(), c.Params("id")); err != nil {
+		return collectionError(err, "Failed to delete collection")
+	}
+	return c.JSON(fiber.Map{"message": "Collection deleted"})
+}
+
+// collectionError maps collection errors onto HTTP statuses.
+func collectionError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrCollectionNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidCollection):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, interfaces.ErrCollectionSlugTaken):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	log.Println("Collections error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, fallback)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type ExportHandler struct {
+	ExportService *services.ExportService
+}
+
+func NewExportHandler(exportService *services.ExportService) *ExportHandler {
+	return &ExportHandler{ExportService: exportService}
+}
+
+// ExportOrders streams matching orders as CSV or XLSX, or starts a background
+// job and responds 202 when the export is too large to stream.
+func (h *ExportHandler) ExportOrders(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format", services.ExportFormatCSV))
+	filters, err := orderFiltersFromQuery(c)
+	if err != nil {
+		return err
+	}
+
+	adminID, _ := c.Locals("adminID").(string)
+	job, err := h.ExportService.StartOrderExport(c.Context(), filters, format, adminID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidExportFormat) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		log.Println("ExportOrders error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to export orders")
+	}
+
+	if job != nil {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"message":   "Export started",
+			"job":       job,
+			"statusUrl": "/api/admin/exports/" + job.ID.String(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, services.ExportContentType(format))
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+services.ExportFileName(format, time.Now())+`"`)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The request context ends once the handler returns, so the stream
+		// runs on its own context.
+		if err := h.ExportService.WriteOrderExport(context.Background(), filters, format, w); err != nil {
+			log.Println("ExportOrders stream error:", err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+func (h *ExportHandler) GetExportJob(c *fiber.Ctx) error {
+	job, err := h.ExportService.GetJob(c.Context(), c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Export not found")
+	}
+	return c.JSON(job)
+}
+
+func (h *ExportHandler) DownloadExport(c *fiber.Ctx) error {
+	job, err := h.ExportService.GetJob(c.Context(), c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Export not found")
+	}
+	if job.Status != constants.JobStatusCompleted || job.FilePath == "" {
+		return fiber.NewError(fiber.StatusConflict, "Export is not ready yet")
+	}
+
+	format, _ := job.Params["format"].(string)
+	return c.Download(job.FilePath, services.ExportFileName(format, job.CreatedAt))
+}
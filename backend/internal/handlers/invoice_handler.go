@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/services"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+type InvoiceHandler struct {
+	InvoiceService *services.InvoiceService
+}
+
+func NewInvoiceHandler(invoiceService *services.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{InvoiceService: invoiceService}
+}
+
+func (h *InvoiceHandler) GetInvoice(c *fiber.Ctx) error {
+	userID, _ := utils.GetCartKey(c)
+	isAdmin, _ := c.Locals("isAdmin").(bool)
+
+	invoice, err := h.InvoiceService.GetInvoice(c.Context(), c.Params("id"), isAdmin, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderForbidden):
+			return fiber.NewError(fiber.StatusForbidden, "Unauthorized attempt to access this order.")
+		case errors.Is(err, services.ErrInvoiceUnavailable):
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		log.Println("GetInvoice error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to generate invoice")
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+invoice.Number+`.pdf"`)
+	return c.Send(invoice.PDF)
+}
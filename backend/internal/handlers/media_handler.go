@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type MediaHandler struct {
+	MediaService *services.MediaService
+}
+
+func NewMediaHandler(mediaService *services.MediaService) *MediaHandler {
+	return &MediaHandler{MediaService: mediaService}
+}
+
+// UploadProductImage accepts a multipart upload in the "image" field and
+// adds it to the product's images.
+func (h *MediaHandler) UploadProductImage(c *fiber.Ctx) error {
+	file, err := c.FormFile("image")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Image file required")
+	}
+	if file.Size > int64(h.MediaService.Config.MaxUploadSize) {
+		return mediaError(services.ErrImageTooLarge, "")
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Failed to read image")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, int64(h.MediaService.Config.MaxUploadSize)+1))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Failed to read image")
+	}
+
+	image, err := h.MediaService.UploadProductImage(c.Context(), c.Params("id"), data)
+	if err != nil {
+		return mediaError(err, "Failed to upload image")
+	}
+	return c.Status(fiber.StatusCreated).JSON(image)
+}
+
+func (h *MediaHandler) DeleteProductImage(c *fiber.Ctx) error {
+	if err := h.MediaService.DeleteProductImage(c.Context(), c.Params("id"), c.Params("imageId")); err != nil {
+		return mediaError(err, "Failed to delete image")
+	}
+	return c.JSON(fiber.Map{"message": "Image deleted"})
+}
+
+// mediaError maps upload errors onto HTTP statuses.
+func mediaError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound), errors.Is(err, services.ErrProductImageNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrImageTooLarge):
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, services.ErrUnsupportedImage):
+		return fiber.NewError(fiber.StatusUnsupportedMediaType, err.Error())
+	}
+	log.Println("Media error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, fallback)
+}
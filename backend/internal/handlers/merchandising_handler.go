@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type MerchandisingHandler struct {
+	MerchandisingService *services.MerchandisingService
+}
+
+func NewMerchandisingHandler(merchandisingService *services.MerchandisingService) *MerchandisingHandler {
+	return &MerchandisingHandler{MerchandisingService: merchandisingService}
+}
+
+// ListFeatured returns every featured entry, including scheduled and expired
+// ones, in display order.
+func (h *MerchandisingHandler) ListFeatured(c *fiber.Ctx) error {
+	featured, err := h.MerchandisingService.ListFeatured(c.Context())
+	if err != nil {
+		log.Println("ListFeatured error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch featured products")
+	}
+	return c.JSON(fiber.Map{"featured": featured})
+}
+
+func (h *MerchandisingHandler) AddFeatured(c *fiber.Ctx) error {
+	var req models.FeaturedProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	adminID, _ := c.Locals("adminID").(string)
+	featured, err := h.MerchandisingService.AddFeatured(c.Context(), req, adminID)
+	if err != nil {
+		return featuredError(err, "Failed to add featured product")
+	}
+	return c.Status(fiber.StatusCreated).JSON(featured)
+}
+
+func (h *MerchandisingHandler) UpdateFeatured(c *fiber.Ctx) error {
+	var req models.FeaturedProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	featured, err := h.MerchandisingService.UpdateFeatured(c.Context(), c.Params("id"), req)
+	if err != nil {
+		return featuredError(err, "Failed to update featured product")
+	}
+	return c.JSON(featured)
+}
+
+func (h *MerchandisingHandler) RemoveFeatured(c *fiber.Ctx) error {
+	if err := h.MerchandisingService.RemoveFeatured(c.Context(), c.Params("id")); err != nil {
+		return featuredError(err, "Failed to remove featured product")
+	}
+	return c.JSON(fiber.Map{"message": "Featured product removed"})
+}
+
+// RefreshBestSellers recomputes the best seller ranking now instead of
+// waiting for the next scheduled run.
+func (h *MerchandisingHandler) RefreshBestSellers(c *fiber.Ctx) error {
+	if err := h.MerchandisingService.RefreshBestSellers(c.Context()); err != nil {
+		log.Println("RefreshBestSellers error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to refresh best sellers")
+	}
+	return c.JSON(fiber.Map{"message": "Best sellers refreshed"})
+}
+
+// RefreshBoughtTogether re-mines the co-purchase lists now.
+func (h *MerchandisingHandler) RefreshBoughtTogether(c *fiber.Ctx) error {
+	if err := h.MerchandisingService.RefreshBoughtTogether(c.Context()); err != nil {
+		log.Println("RefreshBoughtTogether error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to refresh bought together lists")
+	}
+	return c.JSON(fiber.Map{"message": "Bought together lists refreshed"})
+}
+
+// featuredError maps featured list errors onto HTTP statuses.
+func featuredError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrFeaturedNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidFeaturedEntry):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	log.Println("Featured products error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, fallback)
+}
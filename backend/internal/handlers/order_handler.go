@@ -2,9 +2,16 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
 	"github.com/Shrey-Yash/Masked11/internal/services"
 	"github.com/Shrey-Yash/Masked11/internal/utils"
 )
@@ -25,13 +32,26 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 		return err
 	}
 
-	erro := h.OrderService.CreateOrder(key)
+	var req models.CheckoutRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+	req.CustomerID, _ = c.Locals("userID").(string)
+	req.CustomerEmail, _ = c.Locals("userEmail").(string)
+
+	order, erro := h.OrderService.CreateOrder(key, req)
 	if erro != nil {
+		if isCheckoutError(erro) {
+			return fiber.NewError(fiber.StatusBadRequest, erro.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, erro.Error())
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"message": "Order Created Successfully",
+		"order":   order,
 	})
 }
 
@@ -49,12 +69,103 @@ func (h *OrderHandler) GetOrdersByUserID(c *fiber.Ctx) error {
 	return c.JSON(orders)
 }
 
+// ListOrders serves the admin order list.
+func (h *OrderHandler) ListOrders(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	sortBy := c.Query("sortBy", "createdAt")
+	if valid, msg := utils.ValidateEnum(sortBy, []string{"createdAt", "total"}, "sortBy"); !valid {
+		return fiber.NewError(fiber.StatusBadRequest, msg)
+	}
+
+	filters, err := orderFiltersFromQuery(c)
+	if err != nil {
+		return err
+	}
+	filters["sortBy"] = sortBy
+	filters["sortOrder"] = c.Query("sortOrder", "desc")
+	filters["cursor"] = c.Query("cursor")
+	filters["limit"] = limit
+
+	orders, total, next, err := h.OrderService.ListOrders(c.Context(), filters)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"orders": orders,
+		"pagination": fiber.Map{
+			"totalItems": total,
+			"limit":      limit,
+			"nextCursor": next,
+			"hasNext":    next != "",
+		},
+	})
+}
+
+// orderFiltersFromQuery reads the admin order filters from the query string.
+func orderFiltersFromQuery(c *fiber.Ctx) (map[string]interface{}, error) {
+	// Totals are given in major units, such as rupees.
+	minTotal, err := models.ParseMoney(c.Query("minTotal", "0"), models.DefaultCurrency)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid minTotal")
+	}
+	maxTotal, err := models.ParseMoney(c.Query("maxTotal", "0"), models.DefaultCurrency)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid maxTotal")
+	}
+
+	from, err := parseDateQuery(c.Query("from"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid from date")
+	}
+	to, err := parseDateQuery(c.Query("to"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid to date")
+	}
+	// A plain date includes the whole of that day.
+	if !to.IsZero() && len(c.Query("to")) == len("2006-01-02") {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	return map[string]interface{}{
+		"status":        strings.ToUpper(c.Query("status")),
+		"userId":        c.Query("userId"),
+		"paymentMethod": strings.ToUpper(c.Query("paymentMethod")),
+		"from":          from,
+		"to":            to,
+		"minTotal":      minTotal.Amount,
+		"maxTotal":      maxTotal.Amount,
+		"search":        strings.TrimSpace(c.Query("search")),
+	}, nil
+}
+
+// parseDateQuery accepts either a date or an RFC 3339 timestamp.
+func parseDateQuery(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
 func (h *OrderHandler) GetOrderByID(c *fiber.Ctx) error {
 	orderID := c.Params("id")
 	userID, _ := utils.GetCartKey(c)
 	isAdmin, _ := c.Locals("isAdmin").(bool)
 
 	order, err := h.OrderService.GetOrderByID(context.Background(), orderID, isAdmin, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fiber.NewError(fiber.StatusNotFound, "Order not found")
+	}
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -77,6 +188,9 @@ func (h *OrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
 	}
 
 	if err := h.OrderService.UpdateOrderStatus(orderID, p.Status); err != nil {
+		if errors.Is(err, services.ErrInvalidTransition) || errors.Is(err, services.ErrShipmentRequired) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -93,10 +207,7 @@ func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
 	}
 
 	if err := h.OrderService.CancelOrder(c.Context(), userID, orderID); err != nil {
-		if err.Error() == "unauthorized access to cancel order" {
-			return fiber.NewError(fiber.StatusForbidden, "You are not allowed to cancel this order.")
-		}
-		return fiber.NewError(fiber.StatusInternalServerError, "Unable to cancel this order.")
+		return cancelError(err, "Unable to cancel this order.")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -104,9 +215,51 @@ func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
 	})
 }
 
+func (h *OrderHandler) CancelOrderItems(c *fiber.Ctx) error {
+	orderID := c.Params("id")
+	userID, err := utils.GetCartKey(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "User not Authenticated")
+	}
+
+	var body struct {
+		ItemIDs []string `json:"itemIds"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	order, refund, err := h.OrderService.CancelOrderItems(c.Context(), userID, orderID, body.ItemIDs)
+	if err != nil {
+		return cancelError(err, "Unable to cancel these items.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Items cancelled successfully.",
+		"order":   order,
+		"refund":  refund,
+	})
+}
+
+// cancelError maps cancellation errors onto HTTP statuses.
+func cancelError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrOrderForbidden):
+		return fiber.NewError(fiber.StatusForbidden, "You are not allowed to cancel this order.")
+	case errors.Is(err, services.ErrOrderNotCancellable),
+		errors.Is(err, services.ErrNoItemsToCancel),
+		errors.Is(err, services.ErrInvalidRefundAmount):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, fallback)
+}
+
 func (h *OrderHandler) DeleteOrder(c *fiber.Ctx) error {
 	orderID := c.Params("id")
 	if err := h.OrderService.DeleteOrder(orderID); err != nil {
+		if errors.Is(err, services.ErrOrderInvoiced) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -114,3 +267,22 @@ func (h *OrderHandler) DeleteOrder(c *fiber.Ctx) error {
 		"message": "Order Deleted",
 	})
 }
+
+// isCheckoutError reports whether err was caused by the buyer's checkout
+// choices rather than a server-side failure.
+func isCheckoutError(err error) bool {
+	switch {
+	case errors.Is(err, services.ErrInvalidPaymentMethod),
+		errors.Is(err, services.ErrCODUnavailable),
+		errors.Is(err, services.ErrCODOrderLimit),
+		errors.Is(err, services.ErrCODPincode),
+		errors.Is(err, services.ErrCODPhoneUnverified),
+		errors.Is(err, services.ErrAddressRequired),
+		errors.Is(err, services.ErrAddressNotFound),
+		errors.Is(err, services.ErrInvalidAddress),
+		errors.Is(err, services.ErrProductUnavailable),
+		errors.Is(err, interfaces.ErrInsufficientStock):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type PaymentHandler struct {
+	PaymentService *services.PaymentService
+}
+
+func NewPaymentHandler(paymentService *services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{PaymentService: paymentService}
+}
+
+func (h *PaymentHandler) GetPendingCODPayments(c *fiber.Ctx) error {
+	payments, err := h.PaymentService.GetPendingCODPayments(c.Context())
+	if err != nil {
+		log.Println("GetPendingCODPayments error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch COD payments")
+	}
+
+	return c.JSON(fiber.Map{
+		"payments": payments,
+	})
+}
+
+func (h *PaymentHandler) ReconcileCOD(c *fiber.Ctx) error {
+	var body struct {
+		OrderIDs []string `json:"orderIds"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	adminID, _ := c.Locals("adminID").(string)
+	reconciled, err := h.PaymentService.ReconcileCOD(c.Context(), body.OrderIDs, adminID)
+	if err != nil {
+		log.Println("ReconcileCOD error:", err)
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"message":    "COD payments reconciled",
+		"reconciled": reconciled,
+	})
+}
+
+// GetUnsettledRefunds lists refunds finance still has to pay by hand.
+func (h *PaymentHandler) GetUnsettledRefunds(c *fiber.Ctx) error {
+	refunds, err := h.PaymentService.GetUnsettledRefunds(c.Context())
+	if err != nil {
+		log.Println("GetUnsettledRefunds error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch refunds")
+	}
+
+	return c.JSON(fiber.Map{
+		"refunds": refunds,
+	})
+}
+
+func (h *PaymentHandler) SettleRefunds(c *fiber.Ctx) error {
+	var body struct {
+		RefundIDs []string `json:"refundIds"`
+		Reference string   `json:"reference"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	adminID, _ := c.Locals("adminID").(string)
+	settled, err := h.PaymentService.SettleRefunds(c.Context(), body.RefundIDs, body.Reference, adminID)
+	if err != nil {
+		log.Println("SettleRefunds error:", err)
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Refunds settled",
+		"settled": settled,
+	})
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type PricingHandler struct {
+	PricingService *services.PricingService
+}
+
+func NewPricingHandler(pricingService *services.PricingService) *PricingHandler {
+	return &PricingHandler{PricingService: pricingService}
+}
+
+func (h *PricingHandler) ListPriceLists(c *fiber.Ctx) error {
+	lists, err := h.PricingService.ListPriceLists(c.Context())
+	if err != nil {
+		return pricingError(err, "Failed to fetch price lists")
+	}
+	return c.JSON(fiber.Map{"priceLists": lists})
+}
+
+func (h *PricingHandler) CreatePriceList(c *fiber.Ctx) error {
+	var req models.PriceListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	list, err := h.PricingService.CreatePriceList(c.Context(), req)
+	if err != nil {
+		return pricingError(err, "Failed to create price list")
+	}
+	return c.Status(fiber.StatusCreated).JSON(list)
+}
+
+func (h *PricingHandler) UpdatePriceList(c *fiber.Ctx) error {
+	var req models.PriceListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	list, err := h.PricingService.UpdatePriceList(c.Context(), c.Params("id"), req)
+	if err != nil {
+		return pricingError(err, "Failed to update price list")
+	}
+	return c.JSON(list)
+}
+
+func (h *PricingHandler) DeletePriceList(c *fiber.Ctx) error {
+	if err := h.PricingService.DeletePriceList(c.Context(), c.Params("id")); err != nil {
+		return pricingError(err, "Failed to delete price list")
+	}
+	return c.JSON(fiber.Map{"message": "Price list deleted"})
+}
+
+// SetCustomerGroup moves a customer into a pricing group, or back onto
+// standard prices with an empty group.
+func (h *PricingHandler) SetCustomerGroup(c *fiber.Ctx) error {
+	var req models.CustomerGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.PricingService.SetCustomerGroup(c.Context(), c.Params("id"), req); err != nil {
+		return pricingError(err, "Failed to update customer group")
+	}
+	return c.JSON(fiber.Map{"message": "Customer group updated"})
+}
+
+// priceProducts fills in the prices the requesting customer pays.
+func priceProducts(c *fiber.Ctx, pricing *services.PricingService, products ...*models.Product) error {
+	userID, _ := c.Locals("userID").(string)
+	if err := pricing.ApplyPricing(c.Context(), userID, products...); err != nil {
+		log.Println("ApplyPricing error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to price products")
+	}
+	return nil
+}
+
+// pricingError maps pricing errors onto HTTP statuses.
+func pricingError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrPriceListNotFound), errors.Is(err, services.ErrUserNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidPriceList), errors.Is(err, services.ErrInvalidCustomerGroup):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, interfaces.ErrPriceListGroupTaken):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrProductUnavailable):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	log.Println("Pricing error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, fallback)
+}
@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/Shrey-Yash/Masked11/internal/models"
 	"github.com/Shrey-Yash/Masked11/internal/services"
@@ -14,10 +18,11 @@ import (
 
 type ProductHandler struct {
 	Service *services.ProductService
+	Pricing *services.PricingService
 }
 
-func NewProductHandler(service *services.ProductService) *ProductHandler {
-	return &ProductHandler{Service: service}
+func NewProductHandler(service *services.ProductService, pricing *services.PricingService) *ProductHandler {
+	return &ProductHandler{Service: service, Pricing: pricing}
 }
 
 func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
@@ -26,10 +31,9 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON body")
 	}
 
-	err := h.Service.CreateProduct(&product)
-	if err != nil {
-		log.Println("CreateProduct error:", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create product")
+	adminID, _ := c.Locals("adminID").(string)
+	if err := h.Service.CreateProduct(&product, adminID); err != nil {
+		return productError(err, "Failed to create product")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -44,23 +48,78 @@ func (h *ProductHandler) GetProductByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Product ID required")
 	}
 
-	product, err := h.Service.GetProductByID(id)
+	product, err := h.Service.GetPublishedProduct(id)
 	if err != nil {
 		log.Println("GetProductByID error:", err)
 		return fiber.NewError(fiber.StatusNotFound, "Product not found")
 	}
+	if err := priceProducts(c, h.Pricing, product); err != nil {
+		return err
+	}
+
+	return c.JSON(product)
+}
+
+// PreviewProduct lets admins see a product in any status, including drafts
+// and scheduled products that are not live yet.
+func (h *ProductHandler) PreviewProduct(c *fiber.Ctx) error {
+	product, err := h.Service.GetProductByID(c.Params("id"))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return fiber.NewError(fiber.StatusNotFound, "Product not found")
+		}
+		log.Println("PreviewProduct error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch product")
+	}
+	if err := priceProducts(c, h.Pricing, product); err != nil {
+		return err
+	}
+
+	return c.JSON(product)
+}
+
+// GetProductBySlug serves a product by its slug. Old slugs answer with a
+// permanent redirect to the current one.
+func (h *ProductHandler) GetProductBySlug(c *fiber.Ctx) error {
+	product, moved, err := h.Service.GetProductBySlug(c.Params("slug"))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fiber.NewError(fiber.StatusNotFound, "Product not found")
+		}
+		log.Println("GetProductBySlug error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch product")
+	}
 
+	c.Locals("productID", product.ID.Hex())
+	if moved {
+		c.Location("/api/products/slug/" + product.Slug)
+		return c.Status(fiber.StatusMovedPermanently).JSON(fiber.Map{
+			"slug": product.Slug,
+		})
+	}
+	if err := priceProducts(c, h.Pricing, product); err != nil {
+		return err
+	}
 	return c.JSON(product)
 }
 
 func (h *ProductHandler) GetAllProducts(c *fiber.Ctx) error {
+	return h.listProducts(c, h.Service.GetAllProductsWithFilters)
+}
+
+// AdminListProducts lists products in every status, narrowed by ?status=.
+func (h *ProductHandler) AdminListProducts(c *fiber.Ctx) error {
+	return h.listProducts(c, h.Service.AdminListProducts)
+}
+
+func (h *ProductHandler) listProducts(c *fiber.Ctx, fetch func(filters map[string]interface{}) ([]*models.Product, int64, error)) error {
 	// Get query parameters for filtering and pagination
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	limit, _ := strconv.Atoi(c.Query("limit", "12"))
 	search := c.Query("search", "")
 	category := c.Query("category", "")
-	minPrice, _ := strconv.ParseFloat(c.Query("minPrice", "0"), 64)
-	maxPrice, _ := strconv.ParseFloat(c.Query("maxPrice", "0"), 64)
+	minPrice, _ := models.ParseMoney(c.Query("minPrice", "0"), models.DefaultCurrency)
+	maxPrice, _ := models.ParseMoney(c.Query("maxPrice", "0"), models.DefaultCurrency)
 	sortBy := c.Query("sortBy", "createdAt")
 	sortOrder := c.Query("sortOrder", "desc")
 
@@ -74,20 +133,23 @@ func (h *ProductHandler) GetAllProducts(c *fiber.Ctx) error {
 
 	// Create filter options
 	filters := map[string]interface{}{
+		"status":     strings.ToUpper(c.Query("status")),
 		"search":     search,
 		"category":   category,
-		"minPrice":   minPrice,
-		"maxPrice":   maxPrice,
+		"minPrice":   minPrice.Amount,
+		"maxPrice":   maxPrice.Amount,
 		"sortBy":     sortBy,
 		"sortOrder":  sortOrder,
 		"page":       page,
 		"limit":      limit,
 	}
 
-	products, total, err := h.Service.GetAllProductsWithFilters(filters)
+	products, total, err := fetch(filters)
 	if err != nil {
-		log.Println("GetAllProducts error:", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch products")
+		return productError(err, "Failed to fetch products")
+	}
+	if err := priceProducts(c, h.Pricing, products...); err != nil {
+		return err
 	}
 
 	// Calculate pagination info
@@ -130,6 +192,9 @@ func (h *ProductHandler) SearchProducts(c *fiber.Ctx) error {
 		log.Println("SearchProducts error:", err)
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to search products")
 	}
+	if err := priceProducts(c, h.Pricing, products...); err != nil {
+		return err
+	}
 
 	totalPages := (total + limit - 1) / limit
 
@@ -146,8 +211,8 @@ func (h *ProductHandler) SearchProducts(c *fiber.Ctx) error {
 }
 
 func (h *ProductHandler) GetProductsByCategory(c *fiber.Ctx) error {
-	category := c.Params("category")
-	if category == "" {
+	slug := c.Params("category")
+	if slug == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "Category required")
 	}
 
@@ -161,11 +226,17 @@ func (h *ProductHandler) GetProductsByCategory(c *fiber.Ctx) error {
 		limit = 12
 	}
 
-	products, total, err := h.Service.GetProductsByCategory(category, page, limit)
+	category, products, total, err := h.Service.GetProductsByCategory(slug, page, limit)
+	if errors.Is(err, services.ErrCategoryNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Category not found")
+	}
 	if err != nil {
 		log.Println("GetProductsByCategory error:", err)
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch products by category")
 	}
+	if err := priceProducts(c, h.Pricing, products...); err != nil {
+		return err
+	}
 
 	totalPages := (total + limit - 1) / limit
 
@@ -192,6 +263,62 @@ func (h *ProductHandler) GetFeaturedProducts(c *fiber.Ctx) error {
 		log.Println("GetFeaturedProducts error:", err)
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch featured products")
 	}
+	if err := priceProducts(c, h.Pricing, products...); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"products": products,
+	})
+}
+
+func (h *ProductHandler) GetBestSellers(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "8"))
+	if limit < 1 || limit > 50 {
+		limit = 8
+	}
+
+	products, err := h.Service.GetBestSellers(limit)
+	if err != nil {
+		log.Println("GetBestSellers error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch best sellers")
+	}
+	if err := priceProducts(c, h.Pricing, products...); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"products": products,
+	})
+}
+
+func (h *ProductHandler) GetRelatedProducts(c *fiber.Ctx) error {
+	return h.recommendations(c, "GetRelatedProducts", h.Service.GetRelatedProducts)
+}
+
+func (h *ProductHandler) GetBoughtTogether(c *fiber.Ctx) error {
+	return h.recommendations(c, "GetBoughtTogether", h.Service.GetBoughtTogether)
+}
+
+// recommendations serves a list of products recommended alongside the
+// product in the path.
+func (h *ProductHandler) recommendations(c *fiber.Ctx, name string, fetch func(productID string, limit int) ([]*models.Product, error)) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "8"))
+	if limit < 1 || limit > 20 {
+		limit = 8
+	}
+
+	products, err := fetch(c.Params("id"), limit)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return fiber.NewError(fiber.StatusNotFound, "Product not found")
+		}
+		log.Println(name, "error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch recommendations")
+	}
+	if err := priceProducts(c, h.Pricing, products...); err != nil {
+		return err
+	}
 
 	return c.JSON(fiber.Map{
 		"products": products,
@@ -212,10 +339,9 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	delete(updates, "_id")
 	updates["updatedAt"] = time.Now()
 
-	err := h.Service.UpdateProduct(id, updates)
-	if err != nil {
-		log.Println("UpdateProduct error:", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update product")
+	adminID, _ := c.Locals("adminID").(string)
+	if err := h.Service.UpdateProduct(id, updates, adminID); err != nil {
+		return productError(err, "Failed to update product")
 	}
 
 	return c.JSON(fiber.Map{
@@ -229,10 +355,8 @@ func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Product ID required")
 	}
 
-	err := h.Service.DeleteProduct(id)
-	if err != nil {
-		log.Println("DeleteProduct error:", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete product")
+	if err := h.Service.DeleteProduct(id); err != nil {
+		return productError(err, "Failed to delete product")
 	}
 
 	return c.JSON(fiber.Map{
@@ -240,14 +364,90 @@ func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 	})
 }
 
-func (h *ProductHandler) GetProductCategories(c *fiber.Ctx) error {
-	categories, err := h.Service.GetProductCategories()
+// GetPriceHistory lists the prices a product has had, so a "was" price shown
+// next to the current one can be checked against real history.
+func (h *ProductHandler) GetPriceHistory(c *fiber.Ctx) error {
+	history, err := h.Service.GetPriceHistory(c.Context(), c.Params("id"))
+	if err != nil {
+		return productError(err, "Failed to fetch price history")
+	}
+
+	return c.JSON(fiber.Map{
+		"history": history,
+	})
+}
+
+// GetProductHistory lists a product's recorded changes, newest first.
+func (h *ProductHandler) GetProductHistory(c *fiber.Ctx) error {
+	page, limit := pageParams(c)
+	versions, total, err := h.Service.GetProductHistory(c.Context(), c.Params("id"), page, limit)
 	if err != nil {
-		log.Println("GetProductCategories error:", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch categories")
+		return productError(err, "Failed to fetch product history")
 	}
 
 	return c.JSON(fiber.Map{
-		"categories": categories,
+		"versions":   versions,
+		"pagination": pageInfo(page, limit, total),
 	})
 }
+
+// RollbackProduct restores a product to an earlier version.
+func (h *ProductHandler) RollbackProduct(c *fiber.Ctx) error {
+	var req models.RollbackRequest
+	if err := c.BodyParser(&req); err != nil || req.Version < 1 {
+		return fiber.NewError(fiber.StatusBadRequest, "version must be a positive number")
+	}
+
+	adminID, _ := c.Locals("adminID").(string)
+	version, err := h.Service.RollbackProduct(c.Context(), c.Params("id"), req.Version, adminID)
+	if err != nil {
+		return productError(err, "Failed to roll back product")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Product rolled back successfully",
+		"version": version,
+	})
+}
+
+// ListDeletedProducts lists the soft deleted products admins can restore.
+func (h *ProductHandler) ListDeletedProducts(c *fiber.Ctx) error {
+	page, limit := pageParams(c)
+	products, total, err := h.Service.GetDeletedProducts(page, limit)
+	if err != nil {
+		return productError(err, "Failed to fetch deleted products")
+	}
+
+	return c.JSON(fiber.Map{
+		"products":   products,
+		"pagination": pageInfo(page, limit, total),
+	})
+}
+
+func (h *ProductHandler) RestoreProduct(c *fiber.Ctx) error {
+	if err := h.Service.RestoreProduct(c.Params("id")); err != nil {
+		return productError(err, "Failed to restore product")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Product restored successfully",
+	})
+}
+
+func productError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "Product not found")
+	case errors.Is(err, services.ErrProductVersionNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrNothingToRollBack):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrCategoryNotFound):
+		return fiber.NewError(fiber.StatusBadRequest, "Unknown category")
+	case errors.Is(err, services.ErrInvalidProductStatus), errors.Is(err, services.ErrInvalidProductSchedule),
+		errors.Is(err, services.ErrInvalidProductPricing):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	log.Println("Products error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, fallback)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type ProductImportHandler struct {
+	ProductImportService *services.ProductImportService
+}
+
+func NewProductImportHandler(productImportService *services.ProductImportService) *ProductImportHandler {
+	return &ProductImportHandler{ProductImportService: productImportService}
+}
+
+// ImportProducts takes a CSV in the multipart "file" field. With
+// ?dryRun=true it only reports what would happen. Large imports respond 202
+// with a job to poll.
+func (h *ProductImportHandler) ImportProducts(c *fiber.Ctx) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "CSV file required")
+	}
+	f, err := file.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Failed to read file")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Failed to read file")
+	}
+
+	adminID, _ := c.Locals("adminID").(string)
+	report, job, err := h.ProductImportService.Import(c.Context(), data, c.QueryBool("dryRun"), adminID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidImportFile) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		log.Println("ImportProducts error:", err)
+		if report != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":  "Import stopped partway",
+				"report": report,
+			})
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to import products")
+	}
+
+	if job != nil {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"message":   "Import started",
+			"job":       job,
+			"statusUrl": "/api/admin/products/imports/" + job.ID.String(),
+		})
+	}
+	return c.JSON(report)
+}
+
+func (h *ProductImportHandler) GetImportJob(c *fiber.Ctx) error {
+	job, err := h.ProductImportService.GetImportJob(c.Context(), c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Import not found")
+	}
+	return c.JSON(job)
+}
+
+// ExportProducts streams the whole catalog as CSV or XLSX.
+func (h *ProductImportHandler) ExportProducts(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format", services.ExportFormatCSV))
+	if format != services.ExportFormatCSV && format != services.ExportFormatXLSX {
+		return fiber.NewError(fiber.StatusBadRequest, services.ErrInvalidExportFormat.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, services.ExportContentType(format))
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="products-`+time.Now().Format("20060102-150405")+"."+format+`"`)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The request context ends once the handler returns, so the stream
+		// runs on its own context.
+		if err := h.ProductImportService.WriteCatalogExport(context.Background(), format, w); err != nil {
+			log.Println("ExportProducts stream error:", err)
+		}
+		w.Flush()
+	})
+	return nil
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type QuestionHandler struct {
+	QuestionService *services.QuestionService
+}
+
+func NewQuestionHandler(questionService *services.QuestionService) *QuestionHandler {
+	return &QuestionHandler{QuestionService: questionService}
+}
+
+func (h *QuestionHandler) AskQuestion(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	if userID == "" {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req models.QuestionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	question, err := h.QuestionService.AskQuestion(c.Context(), userID, c.Params("id"), req)
+	if err != nil {
+		return questionError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(question)
+}
+
+func (h *QuestionHandler) GetProductQuestions(c *fiber.Ctx) error {
+	page, limit := pageParams(c)
+
+	questions, total, err := h.QuestionService.GetProductQuestions(c.Context(), c.Params("id"), page, limit)
+	if err != nil {
+		return questionError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"questions":  questions,
+		"pagination": pageInfo(page, limit, total),
+	})
+}
+
+func (h *QuestionHandler) GetTopAnsweredQuestions(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "5"))
+	if limit < 1 || limit > 20 {
+		limit = 5
+	}
+
+	questions, err := h.QuestionService.GetTopAnsweredQuestions(c.Context(), c.Params("id"), limit)
+	if err != nil {
+		return questionError(err)
+	}
+	return c.JSON(fiber.Map{"questions": questions})
+}
+
+func (h *QuestionHandler) GetAnswers(c *fiber.Ctx) error {
+	page, limit := pageParams(c)
+
+	answers, total, err := h.QuestionService.GetAnswers(c.Context(), c.Params("id"), page, limit)
+	if err != nil {
+		return questionError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"answers":    answers,
+		"pagination": pageInfo(page, limit, total),
+	})
+}
+
+func (h *QuestionHandler) AnswerQuestion(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	if userID == "" {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req models.AnswerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	answer, err := h.QuestionService.AnswerQuestion(c.Context(), userID, c.Params("id"), req)
+	if err != nil {
+		return questionError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Answer submitted for moderation",
+		"answer":  answer,
+	})
+}
+
+// AnswerAsStore posts an admin's answer, which is published immediately.
+func (h *QuestionHandler) AnswerAsStore(c *fiber.Ctx) error {
+	var req models.AnswerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	adminID, _ := c.Locals("adminID").(string)
+	answer, err := h.QuestionService.AnswerAsStore(c.Context(), adminID, c.Params("id"), req)
+	if err != nil {
+		return questionError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(answer)
+}
+
+func (h *QuestionHandler) UpvoteAnswer(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	if userID == "" {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	if err := h.QuestionService.UpvoteAnswer(c.Context(), c.Params("id"), userID); err != nil {
+		return questionError(err)
+	}
+	return c.JSON(fiber.Map{"message": "Thanks for your feedback"})
+}
+
+// GetModerationQueue lists answers for admins, pending ones by default.
+func (h *QuestionHandler) GetModerationQueue(c *fiber.Ctx) error {
+	page, limit := pageParams(c)
+
+	answers, total, err := h.QuestionService.GetModerationQueue(c.Context(), strings.ToUpper(c.Query("status")), page, limit)
+	if err != nil {
+		return questionError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"answers":    answers,
+		"pagination": pageInfo(page, limit, total),
+	})
+}
+
+func (h *QuestionHandler) ApproveAnswer(c *fiber.Ctx) error {
+	return h.moderateAnswer(c, h.QuestionService.ApproveAnswer, "Answer approved")
+}
+
+func (h *QuestionHandler) RejectAnswer(c *fiber.Ctx) error {
+	return h.moderateAnswer(c, h.QuestionService.RejectAnswer, "Answer rejected")
+}
+
+type answerModerationFunc func(ctx context.Context, id, adminID string) (*models.Answer, error)
+
+func (h *QuestionHandler) moderateAnswer(c *fiber.Ctx, moderate answerModerationFunc, message string) error {
+	adminID, _ := c.Locals("adminID").(string)
+	answer, err := moderate(c.Context(), c.Params("id"), adminID)
+	if err != nil {
+		return questionError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": message,
+		"answer":  answer,
+	})
+}
+
+// questionError maps Q&A errors onto HTTP statuses.
+func questionError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrQuestionNotFound),
+		errors.Is(err, services.ErrAnswerNotFound),
+		errors.Is(err, services.ErrProductNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrAnswerNotAllowed),
+		errors.Is(err, services.ErrOwnAnswerVote):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, interfaces.ErrAlreadyVoted),
+		errors.Is(err, services.ErrAnswerNotModeratable):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidQuestion),
+		errors.Is(err, services.ErrInvalidAnswer):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	log.Println("Q&A error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, "Unable to process this request.")
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/services"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+type RecentlyViewedHandler struct {
+	RecentlyViewedService *services.RecentlyViewedService
+	PricingService        *services.PricingService
+}
+
+func NewRecentlyViewedHandler(recentlyViewedService *services.RecentlyViewedService, pricingService *services.PricingService) *RecentlyViewedHandler {
+	return &RecentlyViewedHandler{RecentlyViewedService: recentlyViewedService, PricingService: pricingService}
+}
+
+// TrackView records a product page view once the rest of the chain has
+// served the product, whether from the cache or the database. Handlers that
+// look products up by something other than the :id param set the
+// productID local.
+func (h *RecentlyViewedHandler) TrackView(c *fiber.Ctx) error {
+	if err := c.Next(); err != nil {
+		return err
+	}
+	if c.Response().StatusCode() != fiber.StatusOK {
+		return nil
+	}
+
+	key, err := utils.GetRecentlyViewedKey(c)
+	if err != nil {
+		return nil
+	}
+	productID, _ := c.Locals("productID").(string)
+	if productID == "" {
+		productID = c.Params("id")
+	}
+	if err := h.RecentlyViewedService.RecordView(key, productID); err != nil {
+		log.Println("RecordView error:", err)
+	}
+	return nil
+}
+
+func (h *RecentlyViewedHandler) GetRecentlyViewed(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	key, err := utils.GetRecentlyViewedKey(c)
+	if err != nil {
+		return err
+	}
+
+	products, err := h.RecentlyViewedService.GetRecentlyViewed(key, limit)
+	if err != nil {
+		log.Println("GetRecentlyViewed error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch recently viewed products")
+	}
+	if err := priceProducts(c, h.PricingService, products...); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"products": products,
+	})
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/services"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+type ReturnHandler struct {
+	ReturnService *services.ReturnService
+}
+
+func NewReturnHandler(returnService *services.ReturnService) *ReturnHandler {
+	return &ReturnHandler{ReturnService: returnService}
+}
+
+func (h *ReturnHandler) RequestReturn(c *fiber.Ctx) error {
+	userID, err := utils.GetCartKey(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "User not Authenticated")
+	}
+
+	var body struct {
+		OrderItemID string `json:"orderItemId"`
+		Quantity    int    `json:"quantity"`
+		Reason      string `json:"reason"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	ret, err := h.ReturnService.RequestReturn(c.Context(), userID, c.Params("id"), body.OrderItemID, body.Quantity, body.Reason)
+	if err != nil {
+		return returnError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Return requested",
+		"return":  ret,
+	})
+}
+
+func (h *ReturnHandler) GetMyReturns(c *fiber.Ctx) error {
+	userID, err := utils.GetCartKey(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "User not Authenticated")
+	}
+
+	returns, err := h.ReturnService.GetReturnsByUserID(c.Context(), userID)
+	if err != nil {
+		log.Println("GetMyReturns error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch returns")
+	}
+
+	return c.JSON(fiber.Map{
+		"returns": returns,
+	})
+}
+
+func (h *ReturnHandler) GetReturnByID(c *fiber.Ctx) error {
+	userID, _ := utils.GetCartKey(c)
+	isAdmin, _ := c.Locals("isAdmin").(bool)
+
+	ret, err := h.ReturnService.GetReturnByID(c.Context(), c.Params("id"), isAdmin, userID)
+	if err != nil {
+		return returnError(err)
+	}
+
+	return c.JSON(ret)
+}
+
+func (h *ReturnHandler) GetReturns(c *fiber.Ctx) error {
+	returns, err := h.ReturnService.GetReturns(c.Context(), c.Query("status"))
+	if err != nil {
+		log.Println("GetReturns error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch returns")
+	}
+
+	return c.JSON(fiber.Map{
+		"returns": returns,
+	})
+}
+
+func (h *ReturnHandler) ApproveReturn(c *fiber.Ctx) error {
+	return h.updateReturn(c, h.ReturnService.ApproveReturn, "Return approved")
+}
+
+func (h *ReturnHandler) RejectReturn(c *fiber.Ctx) error {
+	return h.updateReturn(c, h.ReturnService.RejectReturn, "Return rejected")
+}
+
+func (h *ReturnHandler) MarkReturnReceived(c *fiber.Ctx) error {
+	return h.updateReturn(c, h.ReturnService.MarkReceived, "Return marked as received")
+}
+
+func (h *ReturnHandler) RefundReturn(c *fiber.Ctx) error {
+	var body struct {
+		Amount *models.Money `json:"amount"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	adminID, _ := c.Locals("adminID").(string)
+	ret, refund, err := h.ReturnService.RefundReturn(c.Context(), c.Params("id"), adminID, body.Amount)
+	if err != nil {
+		return returnError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Return refunded",
+		"return":  ret,
+		"refund":  refund,
+	})
+}
+
+type returnUpdateFunc func(ctx context.Context, id, adminID, note string) (*models.ReturnRequest, error)
+
+func (h *ReturnHandler) updateReturn(c *fiber.Ctx, update returnUpdateFunc, message string) error {
+	var body struct {
+		Note string `json:"note"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	adminID, _ := c.Locals("adminID").(string)
+	ret, err := update(c.Context(), c.Params("id"), adminID, body.Note)
+	if err != nil {
+		return returnError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": message,
+		"return":  ret,
+	})
+}
+
+// returnError maps return workflow errors onto HTTP statuses.
+func returnError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrReturnForbidden):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrReturnNotAllowed),
+		errors.Is(err, services.ErrReturnWindowExpired),
+		errors.Is(err, services.ErrReturnQuantity),
+		errors.Is(err, services.ErrReturnReason),
+		errors.Is(err, services.ErrOrderItemNotFound),
+		errors.Is(err, services.ErrInvalidTransition),
+		errors.Is(err, services.ErrInvalidRefundAmount),
+		errors.Is(err, services.ErrOrderNotPaid):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	log.Println("Return workflow error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, "Unable to process this return.")
+}
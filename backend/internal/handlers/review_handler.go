@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/services"
+)
+
+type ReviewHandler struct {
+	ReviewService *services.ReviewService
+}
+
+func NewReviewHandler(reviewService *services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{ReviewService: reviewService}
+}
+
+func (h *ReviewHandler) CreateReview(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	if userID == "" {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req models.CreateReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	review, err := h.ReviewService.CreateReview(c.Context(), userID, c.Params("id"), req)
+	if err != nil {
+		return reviewError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Review submitted for moderation",
+		"review":  review,
+	})
+}
+
+// GetProductReviews lists a product's approved reviews. sortBy is one of
+// recent, helpful, highest or lowest.
+func (h *ReviewHandler) GetProductReviews(c *fiber.Ctx) error {
+	page, limit := pageParams(c)
+	sortBy := strings.ToLower(c.Query("sortBy", "recent"))
+
+	reviews, total, err := h.ReviewService.GetProductReviews(c.Context(), c.Params("id"), sortBy, page, limit)
+	if err != nil {
+		return reviewError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"reviews":    reviews,
+		"pagination": pageInfo(page, limit, total),
+	})
+}
+
+func (h *ReviewHandler) MarkHelpful(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	if userID == "" {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	if err := h.ReviewService.MarkHelpful(c.Context(), c.Params("id"), userID); err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(fiber.Map{"message": "Thanks for your feedback"})
+}
+
+// GetModerationQueue lists reviews for admins, pending ones by default.
+func (h *ReviewHandler) GetModerationQueue(c *fiber.Ctx) error {
+	page, limit := pageParams(c)
+
+	reviews, total, err := h.ReviewService.GetModerationQueue(c.Context(), strings.ToUpper(c.Query("status")), page, limit)
+	if err != nil {
+		return reviewError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"reviews":    reviews,
+		"pagination": pageInfo(page, limit, total),
+	})
+}
+
+func (h *ReviewHandler) ApproveReview(c *fiber.Ctx) error {
+	return h.moderateReview(c, h.ReviewService.ApproveReview, "Review approved")
+}
+
+func (h *ReviewHandler) RejectReview(c *fiber.Ctx) error {
+	return h.moderateReview(c, h.ReviewService.RejectReview, "Review rejected")
+}
+
+type reviewModerationFunc func(ctx context.Context, id, adminID, note string) (*models.Review, error)
+
+func (h *ReviewHandler) moderateReview(c *fiber.Ctx, moderate reviewModerationFunc, message string) error {
+	var body struct {
+		Note string `json:"note"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	adminID, _ := c.Locals("adminID").(string)
+	review, err := moderate(c.Context(), c.Params("id"), adminID, body.Note)
+	if err != nil {
+		return reviewError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": message,
+		"review":  review,
+	})
+}
+
+func pageParams(c *fiber.Ctx) (int, int) {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+	return page, limit
+}
+
+func pageInfo(page, limit int, total int64) fiber.Map {
+	totalPages := (int(total) + limit - 1) / limit
+	return fiber.Map{
+		"currentPage": page,
+		"totalPages":  totalPages,
+		"totalItems":  total,
+		"limit":       limit,
+		"hasNext":     page < totalPages,
+	}
+}
+
+// reviewError maps review errors onto HTTP statuses.
+func reviewError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrReviewNotFound),
+		errors.Is(err, services.ErrProductNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrNotVerifiedPurchaser),
+		errors.Is(err, services.ErrOwnReviewVote):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, interfaces.ErrDuplicateReview),
+		errors.Is(err, interfaces.ErrAlreadyVoted),
+		errors.Is(err, services.ErrReviewNotModeratable):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidReview):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	log.Println("Review error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, "Unable to process this review.")
+}
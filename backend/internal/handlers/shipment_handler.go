@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/services"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+type ShipmentHandler struct {
+	ShipmentService *services.ShipmentService
+}
+
+func NewShipmentHandler(shipmentService *services.ShipmentService) *ShipmentHandler {
+	return &ShipmentHandler{ShipmentService: shipmentService}
+}
+
+func (h *ShipmentHandler) CreateShipment(c *fiber.Ctx) error {
+	var req models.CreateShipmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	shipment, err := h.ShipmentService.CreateShipment(c.Context(), c.Params("id"), req)
+	if err != nil {
+		return shipmentError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":  "Shipment created",
+		"shipment": shipment,
+	})
+}
+
+func (h *ShipmentHandler) AddTrackingEvent(c *fiber.Ctx) error {
+	var event models.TrackingEvent
+	if err := c.BodyParser(&event); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	shipment, err := h.ShipmentService.AddTrackingEvent(c.Context(), c.Params("id"), event)
+	if err != nil {
+		return shipmentError(err)
+	}
+
+	return c.JSON(shipment)
+}
+
+func (h *ShipmentHandler) SyncTracking(c *fiber.Ctx) error {
+	shipment, err := h.ShipmentService.SyncTracking(c.Context(), c.Params("id"))
+	if err != nil {
+		return shipmentError(err)
+	}
+
+	return c.JSON(shipment)
+}
+
+func (h *ShipmentHandler) GetOrderTracking(c *fiber.Ctx) error {
+	userID, _ := utils.GetCartKey(c)
+	isAdmin, _ := c.Locals("isAdmin").(bool)
+
+	shipments, err := h.ShipmentService.GetTracking(c.Context(), c.Params("id"), isAdmin, userID)
+	if err != nil {
+		return shipmentError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"shipments": shipments,
+	})
+}
+
+// shipmentError maps shipment errors onto HTTP statuses.
+func shipmentError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrOrderForbidden):
+		return fiber.NewError(fiber.StatusForbidden, "Unauthorized attempt to access this order.")
+	case errors.Is(err, services.ErrOrderNotShippable),
+		errors.Is(err, services.ErrTrackingRequired),
+		errors.Is(err, services.ErrShipmentQuantity),
+		errors.Is(err, services.ErrNothingToShip),
+		errors.Is(err, services.ErrInvalidTrackingStep),
+		errors.Is(err, services.ErrInvalidTransition):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	log.Println("Shipment error:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, "Unable to process this shipment.")
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"strings"
 
@@ -66,6 +67,92 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"message": "User updated successfully"})
 }
 
+func (h *UserHandler) GetAddresses(c *fiber.Ctx) error {
+	userId := c.Locals("userID")
+	if userId == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	addresses, err := h.UserService.GetAddresses(userId.(string))
+	if err != nil {
+		log.Println("GetAddresses error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch addresses")
+	}
+
+	return c.JSON(fiber.Map{"addresses": addresses})
+}
+
+func (h *UserHandler) AddAddress(c *fiber.Ctx) error {
+	userId := c.Locals("userID")
+	if userId == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var address models.Address
+	if err := c.BodyParser(&address); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	saved, err := h.UserService.AddAddress(userId.(string), address)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAddress) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		log.Println("AddAddress error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to save address")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(saved)
+}
+
+func (h *UserHandler) SetDefaultAddress(c *fiber.Ctx) error {
+	userId := c.Locals("userID")
+	if userId == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	if err := h.UserService.SetDefaultAddress(userId.(string), c.Params("addressId")); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Address not found")
+	}
+
+	return c.JSON(fiber.Map{"message": "Default address updated"})
+}
+
+func (h *UserHandler) DeleteAddress(c *fiber.Ctx) error {
+	userId := c.Locals("userID")
+	if userId == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	if err := h.UserService.DeleteAddress(userId.(string), c.Params("addressId")); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Address not found")
+	}
+
+	return c.JSON(fiber.Map{"message": "Address deleted successfully"})
+}
+
+// VerifyPhone lets an admin mark a customer's phone number verified after
+// confirming it with them.
+func (h *UserHandler) VerifyPhone(c *fiber.Ctx) error {
+	var req models.PhoneVerificationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.UserService.VerifyPhone(c.Params("id"), req); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrPhoneMismatch):
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		log.Println("VerifyPhone error:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to verify phone")
+	}
+
+	return c.JSON(fiber.Map{"message": "Phone verified"})
+}
+
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	userId := c.Locals("userID")
 	if userId == nil {
@@ -23,6 +23,7 @@ func AdminOnly() fiber.Handler {
         }
 
         c.Locals("isAdmin", true)
+        c.Locals("adminID", claims["sub"])
         return c.Next()
     }
 }
\ No newline at end of file
@@ -19,8 +19,9 @@ func CacheMiddleware() fiber.Handler {
 			return c.Next()
 		}
 
-		// Skip caching for authenticated requests
-		if c.Get("Authorization") != "" {
+		// Skip caching for authenticated requests, whose prices may come
+		// from their customer group's price list
+		if c.Get("Authorization") != "" || c.Locals("userID") != nil {
 			return c.Next()
 		}
 
@@ -92,10 +93,12 @@ func generateCacheKey(c *fiber.Ctx) string {
 // getCacheTTL returns appropriate TTL based on endpoint
 func getCacheTTL(path string) time.Duration {
 	switch {
-	case path == "/api/products/categories":
+	case path == "/api/products/categories", path == "/api/products/categories/tree":
 		return 24 * time.Hour // Categories change rarely
 	case path == "/api/products/featured":
 		return 1 * time.Hour // Featured products update periodically
+	case path == "/api/products/best-sellers":
+		return 1 * time.Hour // Rankings are refreshed on a schedule
 	case path == "/api/products":
 		return 30 * time.Minute // Product list with filters
 	default:
@@ -113,15 +116,16 @@ func CacheInvalidationMiddleware() fiber.Handler {
 
 		// Invalidate cache for write operations
 		if c.Method() == "POST" || c.Method() == "PUT" || c.Method() == "DELETE" {
-			go invalidateRelatedCache(c.Path())
+			go InvalidateProductCache()
 		}
 
 		return nil
 	}
 }
 
-// invalidateRelatedCache removes related cache entries
-func invalidateRelatedCache(path string) {
+// InvalidateProductCache removes the cached product listings. Background
+// jobs that change what the storefront shows call it directly.
+func InvalidateProductCache() {
 	ctx := context.Background()
 
 	// Define cache patterns to invalidate
@@ -129,6 +133,7 @@ func invalidateRelatedCache(path string) {
 		"cache:GET:/api/products*",
 		"cache:GET:/api/products/categories*",
 		"cache:GET:/api/products/featured*",
+		"cache:GET:/api/collections*",
 	}
 
 	for _, pattern := range patterns {
@@ -10,29 +10,47 @@ import (
 
 func JWTMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		cookie := c.Cookies("token")
-		if cookie == "" {
-			return fiber.NewError(fiber.StatusUnauthorized, "Missing auth token")
+		if err := authenticate(c); err != nil {
+			return err
 		}
+		return c.Next()
+	}
+}
 
-		claims, err := utils.ParseJWT(cookie)
-		if err != nil {
-			return fiber.NewError(fiber.StatusUnauthorized, "Invalid token")
+// OptionalJWTMiddleware identifies the user when a valid session cookie is
+// present and otherwise lets the request through anonymously.
+func OptionalJWTMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Cookies("token") != "" {
+			_ = authenticate(c)
 		}
+		return c.Next()
+	}
+}
 
-		jti, ok := claims["jti"].(string)
-		if !ok || strings.TrimSpace(jti) == "" {
-			return fiber.NewError(fiber.StatusUnauthorized, "Invalid session token")
-		}
+// authenticate validates the session cookie and stores the user in Locals.
+func authenticate(c *fiber.Ctx) error {
+	cookie := c.Cookies("token")
+	if cookie == "" {
+		return fiber.NewError(fiber.StatusUnauthorized, "Missing auth token")
+	}
 
-		exists, err := database.Redis.Exists(database.Ctx, jti).Result()
-		if err != nil || exists == 0 {
-			return fiber.NewError(fiber.StatusUnauthorized, "Session expired or invalid")
-		}
+	claims, err := utils.ParseJWT(cookie)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid token")
+	}
 
-		c.Locals("userID", claims["sub"])
-		c.Locals("userEmail", claims["email"])
+	jti, ok := claims["jti"].(string)
+	if !ok || strings.TrimSpace(jti) == "" {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid session token")
+	}
 
-		return c.Next()
+	exists, err := database.Redis.Exists(database.Ctx, jti).Result()
+	if err != nil || exists == 0 {
+		return fiber.NewError(fiber.StatusUnauthorized, "Session expired or invalid")
 	}
+
+	c.Locals("userID", claims["sub"])
+	c.Locals("userEmail", claims["email"])
+	return nil
 }
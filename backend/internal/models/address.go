@@ -0,0 +1,18 @@
+package models
+
+// Address is a structured postal address. Users keep a list of saved
+// addresses, one of which may be their default, and orders store a copy
+// taken at checkout so later profile edits never change where an order was
+// shipped.
+type Address struct {
+	ID      string `bson:"id" json:"id,omitempty"`
+	Name    string `bson:"name" json:"name" validate:"required"`
+	Phone   string `bson:"phone" json:"phone" validate:"required,min=10,max=13"`
+	Line1   string `bson:"line1" json:"line1" validate:"required"`
+	Line2   string `bson:"line2" json:"line2,omitempty"`
+	City    string `bson:"city" json:"city" validate:"required"`
+	State   string `bson:"state" json:"state" validate:"required"`
+	Pincode string `bson:"pincode" json:"pincode" validate:"required,len=6,numeric"`
+	Country string `bson:"country" json:"country"`
+	Default bool   `bson:"default,omitempty" json:"default,omitempty"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// SalesSummary totals sales over a period.
+type SalesSummary struct {
+	Orders                  int64   `json:"orders"`
+	Revenue                 Money   `json:"revenue"`
+	AOV                     Money   `json:"aov"`
+	NewCustomers            int64   `json:"newCustomers"`
+	ReturningCustomerOrders int64   `json:"returningCustomerOrders"`
+	Refunds                 Money   `json:"refunds"`
+	RefundedOrders          int64   `json:"refundedOrders"`
+	RefundRate              float64 `json:"refundRate"`
+}
+
+// SalesPoint is one bucket of a sales time series.
+type SalesPoint struct {
+	Period  time.Time `json:"period"`
+	Orders  int64     `json:"orders"`
+	Revenue Money     `json:"revenue"`
+	AOV     Money     `json:"aov"`
+}
+
+// SalesReport is the sales summary and time series for a date range.
+type SalesReport struct {
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	Interval string       `json:"interval"`
+	Summary  SalesSummary `json:"summary"`
+	Series   []SalesPoint `json:"series"`
+}
+
+// SalesComparison pairs a report with the same figures for an earlier
+// period and the percentage change between the two.
+type SalesComparison struct {
+	Current  SalesReport        `json:"current"`
+	Previous *SalesReport       `json:"previous,omitempty"`
+	Change   map[string]float64 `json:"change,omitempty"`
+}
+
+type TopProduct struct {
+	ProductID string `json:"productId"`
+	Name      string `json:"name"`
+	Category  string `json:"category"`
+	Quantity  int64  `json:"quantity"`
+	Revenue   Money  `json:"revenue"`
+}
+
+type TopCategory struct {
+	Category string `json:"category"`
+	Quantity int64  `json:"quantity"`
+	Revenue  Money  `json:"revenue"`
+}
+
+type StatusCount struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
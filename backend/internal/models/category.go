@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Category is a node in the catalog hierarchy. Root categories have no
+// ParentID. Hidden categories, and everything below them, are left out of
+// the storefront.
+type Category struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"_id"`
+	Name        string              `bson:"name" json:"name"`
+	Slug        string              `bson:"slug" json:"slug"`
+	ParentID    *primitive.ObjectID `bson:"parentId,omitempty" json:"parentId,omitempty"`
+	Description string              `bson:"description,omitempty" json:"description,omitempty"`
+	Image       string              `bson:"image,omitempty" json:"image,omitempty"`
+	SortOrder   int                 `bson:"sortOrder" json:"sortOrder"`
+	Visible     bool                `bson:"visible" json:"visible"`
+	CreatedAt   time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+// CategoryNode is a category with its subcategories, as served by the
+// category tree.
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// CategoryRequest creates or replaces a category. Slug defaults to one
+// derived from Name, and Visible defaults to true on create.
+type CategoryRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=80"`
+	Slug        string `json:"slug"`
+	ParentID    string `json:"parentId"`
+	Description string `json:"description" validate:"max=2000"`
+	Image       string `json:"image"`
+	SortOrder   int    `json:"sortOrder"`
+	Visible     *bool  `json:"visible"`
+}
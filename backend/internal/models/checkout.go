@@ -0,0 +1,16 @@
+package models
+
+// CheckoutRequest carries the buyer's choices when turning a cart into an order.
+// Addresses can be given inline or picked from the customer's saved
+// addresses by ID. Without either, the order ships to the customer's default
+// saved address, or their only one. The billing address defaults to the
+// shipping address.
+type CheckoutRequest struct {
+	PaymentMethod     string   `json:"paymentMethod"`
+	ShippingAddress   *Address `json:"shippingAddress,omitempty"`
+	ShippingAddressID string   `json:"shippingAddressId,omitempty"`
+	BillingAddress    *Address `json:"billingAddress,omitempty"`
+	BillingAddressID  string   `json:"billingAddressId,omitempty"`
+	CustomerEmail     string   `json:"-"`
+	CustomerID        string   `json:"-"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Collection is a curated group of products such as "Summer Drop". A manual
+// collection lists ProductIDs in order; a rule collection lists the
+// products matching Rules, sorted by SortBy.
+type Collection struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"_id"`
+	Title       string               `bson:"title" json:"title"`
+	Slug        string               `bson:"slug" json:"slug"`
+	Description string               `bson:"description,omitempty" json:"description,omitempty"`
+	Image       string               `bson:"image,omitempty" json:"image,omitempty"`
+	Type        string               `bson:"type" json:"type"`
+	ProductIDs  []primitive.ObjectID `bson:"productIds,omitempty" json:"productIds,omitempty"`
+	Rules       *CollectionRules     `bson:"rules,omitempty" json:"rules,omitempty"`
+	SortBy      string               `bson:"sortBy,omitempty" json:"sortBy,omitempty"`
+	SortOrder   string               `bson:"sortOrder,omitempty" json:"sortOrder,omitempty"`
+	Visible     bool                 `bson:"visible" json:"visible"`
+	CreatedAt   time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// CollectionRules are the conditions a product must all meet to belong to a
+// rule collection. Unset conditions match every product.
+type CollectionRules struct {
+	MinPrice *Money `bson:"minPrice,omitempty" json:"minPrice,omitempty" validate:"omitempty,gte=0"`
+	MaxPrice *Money `bson:"maxPrice,omitempty" json:"maxPrice,omitempty" validate:"omitempty,gt=0"`
+	// Tags match products carrying any one of them.
+	Tags []string `bson:"tags,omitempty" json:"tags,omitempty"`
+	// CategoryID matches the category and all of its subcategories.
+	CategoryID   *primitive.ObjectID `bson:"categoryId,omitempty" json:"categoryId,omitempty"`
+	InStockOnly  bool                `bson:"inStockOnly,omitempty" json:"inStockOnly,omitempty"`
+	CreatedAfter *time.Time          `bson:"createdAfter,omitempty" json:"createdAfter,omitempty"`
+	// NewWithinDays matches products added in the last N days, counted from
+	// the time the collection is viewed.
+	NewWithinDays int `bson:"newWithinDays,omitempty" json:"newWithinDays,omitempty" validate:"gte=0"`
+}
+
+// CollectionRequest creates or replaces a collection. Slug defaults to one
+// derived from Title, and Visible defaults to true on create.
+type CollectionRequest struct {
+	Title       string           `json:"title" validate:"required,min=2,max=120"`
+	Slug        string           `json:"slug"`
+	Description string           `json:"description" validate:"max=2000"`
+	Image       string           `json:"image"`
+	Type        string           `json:"type" validate:"required,oneof=MANUAL RULE"`
+	ProductIDs  []string         `json:"productIds"`
+	Rules       *CollectionRules `json:"rules"`
+	SortBy      string           `json:"sortBy" validate:"omitempty,oneof=createdAt price title rating"`
+	SortOrder   string           `json:"sortOrder" validate:"omitempty,oneof=asc desc"`
+	Visible     *bool            `json:"visible"`
+}
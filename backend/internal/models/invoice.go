@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invoice is the tax invoice issued for an order. Once issued it is never
+// regenerated; PDF holds the exact document given to the customer.
+type Invoice struct {
+	ID            uuid.UUID `json:"id"`
+	OrderID       uuid.UUID `json:"orderId"`
+	Number        string    `json:"number"`
+	FinancialYear string    `json:"financialYear"`
+	Sequence      int       `json:"sequence"`
+	Subtotal      Money     `json:"subtotal"`
+	Discount      Money     `json:"discount"`
+	Tax           Money     `json:"tax"`
+	Total         Money     `json:"total"`
+	PDF           []byte    `json:"-"`
+	IssuedAt      time.Time `json:"issuedAt"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job is a long-running admin task such as a bulk export. Progress counts
+// processed rows out of Total; FilePath points at any file the job produced.
+type Job struct {
+	ID          uuid.UUID              `json:"id"`
+	Type        string                 `json:"type"`
+	Status      string                 `json:"status"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	Progress    int                    `json:"progress"`
+	Total       int                    `json:"total"`
+	Result      map[string]interface{} `json:"result,omitempty"`
+	FilePath    string                 `json:"-"`
+	DownloadURL string                 `json:"downloadUrl,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	CreatedBy   string                 `json:"createdBy"`
+	CreatedAt   time.Time              `json:"createdAt"`
+	UpdatedAt   time.Time              `json:"updatedAt"`
+	CompletedAt *time.Time             `json:"completedAt,omitempty"`
+}
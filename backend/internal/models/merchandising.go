@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProductVelocity is how fast a product sold over the best seller window.
+type ProductVelocity struct {
+	ProductID string  `json:"productId"`
+	Quantity  int64   `json:"quantity"`
+	Revenue   Money   `json:"revenue"`
+	PerDay    float64 `json:"perDay"`
+}
+
+// BestSeller is a materialized best seller ranking, keyed by product.
+type BestSeller struct {
+	ProductID  primitive.ObjectID `bson:"_id" json:"productId"`
+	Rank       int                `bson:"rank" json:"rank"`
+	Quantity   int64              `bson:"quantity" json:"quantity"`
+	PerDay     float64            `bson:"perDay" json:"perDay"`
+	ComputedAt time.Time          `bson:"computedAt" json:"computedAt"`
+}
+
+// CoPurchase counts the orders in which two products were bought together.
+type CoPurchase struct {
+	ProductID string `json:"productId"`
+	OtherID   string `json:"otherId"`
+	Orders    int64  `json:"orders"`
+}
+
+// BoughtTogether is the materialized list of products most often bought
+// with ProductID, strongest first.
+type BoughtTogether struct {
+	ProductID  primitive.ObjectID    `bson:"_id" json:"productId"`
+	Products   []BoughtTogetherEntry `bson:"products" json:"products"`
+	ComputedAt time.Time             `bson:"computedAt" json:"computedAt"`
+}
+
+type BoughtTogetherEntry struct {
+	ProductID primitive.ObjectID `bson:"productId" json:"productId"`
+	Orders    int64              `bson:"orders" json:"orders"`
+}
+
+// FeaturedProduct places a product on the featured list, ordered by
+// Position, between StartsAt and EndsAt. A nil EndsAt never expires.
+type FeaturedProduct struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	ProductID primitive.ObjectID `bson:"productId" json:"productId"`
+	Position  int                `bson:"position" json:"position"`
+	StartsAt  time.Time          `bson:"startsAt" json:"startsAt"`
+	EndsAt    *time.Time         `bson:"endsAt,omitempty" json:"endsAt,omitempty"`
+	CreatedBy string             `bson:"createdBy" json:"createdBy"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	Product   *Product           `bson:"product,omitempty" json:"product,omitempty"`
+}
+
+type FeaturedProductRequest struct {
+	ProductID string     `json:"productId"`
+	Position  int        `json:"position" validate:"gte=0"`
+	StartsAt  *time.Time `json:"startsAt"`
+	EndsAt    *time.Time `json:"endsAt"`
+}
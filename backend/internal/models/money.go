@@ -0,0 +1,264 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// DefaultCurrency is the currency the store sells in. Amounts given or
+// stored without a currency are in it.
+const DefaultCurrency = "INR"
+
+// minorPerMajor is the number of minor units in a major unit. Every
+// currency the store takes has two decimal places.
+const minorPerMajor = 100
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Money is an amount of a currency in minor units, such as paise or cents.
+// Whole minor units keep sums and comparisons exact. Operations that can
+// land between two minor units, such as tax, discounts and pro-rated
+// refunds, round half to even (banker's rounding), so rounding errors do
+// not build up in one direction across many lines.
+type Money struct {
+	Amount   int64  `bson:"amount" json:"amount"`
+	Currency string `bson:"currency" json:"currency"`
+}
+
+// NewMoney returns amount minor units of currency, or of DefaultCurrency
+// when currency is empty.
+func NewMoney(amount int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// MoneyFromMajor converts an amount in major units, such as rupees, to the
+// nearest minor unit. The amount is taken as the decimal it prints as, so
+// 10.005 is a tie and rounds to 10.00.
+func MoneyFromMajor(major float64, currency string) Money {
+	r, ok := decimalRat(major)
+	if !ok {
+		return NewMoney(0, currency)
+	}
+	return NewMoney(roundHalfEven(r.Mul(r, big.NewRat(minorPerMajor, 1))), currency)
+}
+
+// ParseMoney parses a decimal amount in major units, such as "1299.50".
+func ParseMoney(s, currency string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s, "/eE") {
+		return Money{}, ErrInvalidAmount
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Money{}, ErrInvalidAmount
+	}
+	return NewMoney(roundHalfEven(r.Mul(r, big.NewRat(minorPerMajor, 1))), currency), nil
+}
+
+// Add returns m + o. Amounts in different currencies cannot be combined;
+// the zero Money takes on the currency of the amount added to it.
+func (m Money) Add(o Money) Money {
+	m.Currency = m.commonCurrency(o)
+	m.Amount += o.Amount
+	return m
+}
+
+// Sub returns m - o.
+func (m Money) Sub(o Money) Money {
+	m.Currency = m.commonCurrency(o)
+	m.Amount -= o.Amount
+	return m
+}
+
+// Mul returns m times a whole quantity.
+func (m Money) Mul(quantity int) Money {
+	m.Amount *= int64(quantity)
+	return m
+}
+
+// MulFrac returns m * num / den rounded half to even, as when splitting an
+// amount in proportion to num out of den. It returns zero when den is zero.
+func (m Money) MulFrac(num, den int64) Money {
+	if den == 0 {
+		return Money{Currency: m.Currency}
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(m.Amount), big.NewInt(den))
+	m.Amount = roundHalfEven(r.Mul(r, new(big.Rat).SetInt64(num)))
+	return m
+}
+
+// MulRate returns m times rate rounded half to even, such as the tax at an
+// 18% rate with a rate of 0.18. The rate is taken as the decimal it prints
+// as.
+func (m Money) MulRate(rate float64) Money {
+	r, ok := decimalRat(rate)
+	if !ok {
+		return Money{Currency: m.Currency}
+	}
+	m.Amount = roundHalfEven(r.Mul(r, new(big.Rat).SetInt64(m.Amount)))
+	return m
+}
+
+// IncludedTax returns the tax contained in m when m is a tax-inclusive
+// amount taxed at rate, such as 0.18 for 18%, rounded half to even.
+func (m Money) IncludedTax(rate float64) Money {
+	r, ok := decimalRat(rate)
+	if !ok || r.Sign() < 0 {
+		return Money{Currency: m.Currency}
+	}
+	share := new(big.Rat).Quo(r, new(big.Rat).Add(r, big.NewRat(1, 1)))
+	m.Amount = roundHalfEven(share.Mul(share, new(big.Rat).SetInt64(m.Amount)))
+	return m
+}
+
+// Percent returns percent per cent of m rounded half to even, such as the
+// discount at 12.5% off.
+func (m Money) Percent(percent float64) Money {
+	r, ok := decimalRat(percent)
+	if !ok {
+		return Money{Currency: m.Currency}
+	}
+	r.Mul(r, big.NewRat(m.Amount, 100))
+	m.Amount = roundHalfEven(r)
+	return m
+}
+
+// Div returns m split n ways, rounded half to even.
+func (m Money) Div(n int64) Money {
+	return m.MulFrac(1, n)
+}
+
+// Cmp compares m and o, returning -1, 0 or +1.
+func (m Money) Cmp(o Money) int {
+	m.commonCurrency(o)
+	switch {
+	case m.Amount < o.Amount:
+		return -1
+	case m.Amount > o.Amount:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) IsZero() bool     { return m.Amount == 0 }
+func (m Money) IsPositive() bool { return m.Amount > 0 }
+
+// Major returns the amount in major units. It is for ratios and display;
+// sums and comparisons should stay in Money.
+func (m Money) Major() float64 {
+	return float64(m.Amount) / minorPerMajor
+}
+
+// String formats the amount in major units with two decimal places, such as
+// "1299.50", without the currency.
+func (m Money) String() string {
+	amount, sign := m.Amount, ""
+	if amount < 0 {
+		amount, sign = -amount, "-"
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/minorPerMajor, amount%minorPerMajor)
+}
+
+// UnmarshalJSON reads {"amount": 129950, "currency": "INR"}. A plain number
+// is read as an amount in major units of DefaultCurrency, as amounts were
+// sent before Money existed.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var major float64
+	if err := json.Unmarshal(data, &major); err == nil {
+		*m = MoneyFromMajor(major, DefaultCurrency)
+		return nil
+	}
+
+	type plain Money
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*m = NewMoney(p.Amount, p.Currency)
+	return nil
+}
+
+// UnmarshalBSONValue reads a stored Money document, or a plain number in
+// major units of DefaultCurrency as prices were stored before Money existed.
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	switch t {
+	case bsontype.Null, bsontype.Undefined:
+		*m = Money{}
+	case bsontype.Double:
+		v, _, ok := bsoncore.ReadDouble(data)
+		if !ok {
+			return ErrInvalidAmount
+		}
+		*m = MoneyFromMajor(v, DefaultCurrency)
+	case bsontype.Int32:
+		v, _, ok := bsoncore.ReadInt32(data)
+		if !ok {
+			return ErrInvalidAmount
+		}
+		*m = NewMoney(int64(v)*minorPerMajor, DefaultCurrency)
+	case bsontype.Int64:
+		v, _, ok := bsoncore.ReadInt64(data)
+		if !ok {
+			return ErrInvalidAmount
+		}
+		*m = NewMoney(v*minorPerMajor, DefaultCurrency)
+	case bsontype.EmbeddedDocument:
+		type plain Money
+		var p plain
+		if err := bson.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		*m = NewMoney(p.Amount, p.Currency)
+	default:
+		return fmt.Errorf("cannot decode %s as money", t)
+	}
+	return nil
+}
+
+// commonCurrency returns the currency m and o share. Mixing currencies is a
+// programming error, so it panics rather than return a meaningless sum.
+func (m Money) commonCurrency(o Money) string {
+	switch {
+	case m.Currency == "":
+		return o.Currency
+	case o.Currency == "" || o.Currency == m.Currency:
+		return m.Currency
+	}
+	panic(fmt.Sprintf("models: cannot combine %s and %s amounts", m.Currency, o.Currency))
+}
+
+// decimalRat returns f exactly as the shortest decimal that prints as f, so
+// 0.1 is one tenth rather than the nearest binary fraction.
+func decimalRat(f float64) (*big.Rat, bool) {
+	return new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// roundHalfEven rounds r to the nearest integer, and ties to the even one.
+func roundHalfEven(r *big.Rat) int64 {
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	if c := twice.Cmp(r.Denom()); c > 0 || (c == 0 && q.Bit(0) == 1) {
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q.Int64()
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMoneyRoundsHalfToEven(t *testing.T) {
+	cases := map[string]int64{
+		"10.005":  1000,
+		"10.015":  1002,
+		"10.025":  1002,
+		"10.0251": 1003,
+		"-10.005": -1000,
+		"-10.015": -1002,
+		"1299.5":  129950,
+	}
+	for input, want := range cases {
+		m, err := ParseMoney(input, "")
+		assert.NoError(t, err, input)
+		assert.Equal(t, want, m.Amount, input)
+		assert.Equal(t, DefaultCurrency, m.Currency, input)
+	}
+
+	_, err := ParseMoney("1e3", "")
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	assert.Equal(t, int64(1000), MoneyFromMajor(10.005, "").Amount)
+	assert.Equal(t, int64(30), MoneyFromMajor(0.1+0.2, "").Amount)
+}
+
+func TestMoneyTaxAndDiscounts(t *testing.T) {
+	// 18% tax included in 100.00 is 15.2542..., and in 1.18 exactly 0.18.
+	assert.Equal(t, int64(1525), NewMoney(10000, "").IncludedTax(0.18).Amount)
+	assert.Equal(t, int64(18), NewMoney(118, "").IncludedTax(0.18).Amount)
+
+	// 10% off 0.25 is a tie at 0.025, which rounds down to the even 0.02;
+	// off 0.35 it rounds up to 0.04.
+	assert.Equal(t, int64(2), NewMoney(25, "").Percent(10).Amount)
+	assert.Equal(t, int64(4), NewMoney(35, "").Percent(10).Amount)
+	assert.Equal(t, int64(1), NewMoney(1, "").Percent(50.5).Amount)
+
+	// Splitting 1.00 in thirds.
+	assert.Equal(t, int64(33), NewMoney(100, "").MulFrac(1, 3).Amount)
+	assert.Equal(t, int64(67), NewMoney(100, "").MulFrac(2, 3).Amount)
+	assert.Equal(t, int64(2), NewMoney(5, "").Div(2).Amount)
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	price := NewMoney(49950, "inr")
+	assert.Equal(t, "INR", price.Currency)
+	assert.Equal(t, NewMoney(149850, ""), price.Mul(3))
+	assert.Equal(t, price, Money{}.Add(price))
+	assert.Equal(t, "-1.05", NewMoney(-105, "").String())
+	assert.Equal(t, "499.50", price.String())
+	assert.Equal(t, -1, price.Cmp(NewMoney(50000, "")))
+	assert.Panics(t, func() { price.Add(NewMoney(100, "USD")) })
+}
+
+func TestMoneyDecodesLegacyAmounts(t *testing.T) {
+	var cart struct {
+		Price    Money  `json:"price"`
+		Subtotal Money  `json:"subtotal"`
+		Fee      *Money `json:"fee"`
+	}
+	err := json.Unmarshal([]byte(`{"price": 499.5, "subtotal": {"amount": 99900}, "fee": null}`), &cart)
+	assert.NoError(t, err)
+	assert.Equal(t, NewMoney(49950, ""), cart.Price)
+	assert.Equal(t, NewMoney(99900, ""), cart.Subtotal)
+	assert.Nil(t, cart.Fee)
+
+	var product struct {
+		Price Money `bson:"price"`
+	}
+	data, err := bson.Marshal(bson.M{"price": 1299.99})
+	assert.NoError(t, err)
+	assert.NoError(t, bson.Unmarshal(data, &product))
+	assert.Equal(t, NewMoney(129999, ""), product.Price)
+
+	data, err = bson.Marshal(bson.M{"price": NewMoney(129999, "")})
+	assert.NoError(t, err)
+	product.Price = Money{}
+	assert.NoError(t, bson.Unmarshal(data, &product))
+	assert.Equal(t, NewMoney(129999, ""), product.Price)
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Notification is a message for a single user, delivered by whichever
+// Notifier the server is configured with.
+type Notification struct {
+	UserID    string            `json:"userId"`
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Link      string            `json:"link,omitempty"`
+	Data      map[string]string `json:"data,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
@@ -7,11 +7,18 @@ import (
 )
 
 type Order struct {
-	ID        uuid.UUID   `json:"id"`
-	UserID    string      `json:"userId"`
-	Total     float64     `json:"total"`
-	Status    string      `json:"status"`
-	Items     []OrderItem `json:"items"`
-	CreatedAt time.Time   `json:"createdAt"`
-	UpdatedAt time.Time   `json:"updatedAt"`
+	ID              uuid.UUID   `json:"id"`
+	OrderNumber     string      `json:"orderNumber"`
+	UserID          string      `json:"userId"`
+	CustomerEmail   string      `json:"customerEmail,omitempty"`
+	Total           Money       `json:"total"`
+	Status          string      `json:"status"`
+	PaymentMethod   string      `json:"paymentMethod"`
+	CODFee          Money       `json:"codFee"`
+	ShippingAddress *Address    `json:"shippingAddress,omitempty"`
+	BillingAddress  *Address    `json:"billingAddress,omitempty"`
+	Items           []OrderItem `json:"items"`
+	DeliveredAt     *time.Time  `json:"deliveredAt,omitempty"`
+	CreatedAt       time.Time   `json:"createdAt"`
+	UpdatedAt       time.Time   `json:"updatedAt"`
 }
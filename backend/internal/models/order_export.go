@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderExportRow is one order line as written to a finance export.
+type OrderExportRow struct {
+	OrderNumber   string
+	OrderID       uuid.UUID
+	CreatedAt     time.Time
+	OrderStatus   string
+	PaymentMethod string
+	CustomerEmail string
+	UserID        string
+	OrderTotal    Money
+	CODFee        Money
+	ProductID     string
+	ItemName      string
+	Size          string
+	Quantity      int
+	Price         Money
+	Subtotal      Money
+	ItemStatus    string
+	ShipCity      string
+	ShipState     string
+	ShipPincode   string
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Payment struct {
+	ID           uuid.UUID  `json:"id"`
+	OrderID      uuid.UUID  `json:"orderId"`
+	Method       string     `json:"method"`
+	Amount       Money      `json:"amount"`
+	Status       string     `json:"status"`
+	Reference    string     `json:"reference,omitempty"`
+	CollectedAt  *time.Time `json:"collectedAt,omitempty"`
+	ReconciledAt *time.Time `json:"reconciledAt,omitempty"`
+	ReconciledBy string     `json:"reconciledBy,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
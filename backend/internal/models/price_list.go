@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PriceList gives the customers in one group, such as "wholesale" or "vip",
+// their own prices: a percentage off every product, overridden by fixed
+// prices for particular products. Customers always pay the lowest price
+// available to them.
+type PriceList struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Name            string             `bson:"name" json:"name"`
+	Group           string             `bson:"group" json:"group"`
+	DiscountPercent float64            `bson:"discountPercent,omitempty" json:"discountPercent,omitempty"`
+	Prices          []PriceListPrice   `bson:"prices,omitempty" json:"prices,omitempty"`
+	Active          bool               `bson:"active" json:"active"`
+	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+type PriceListPrice struct {
+	ProductID primitive.ObjectID `bson:"productId" json:"productId"`
+	Price     Money              `bson:"price" json:"price"`
+}
+
+// PriceListRequest creates or replaces a price list. Active defaults to
+// true on create.
+type PriceListRequest struct {
+	Name            string                  `json:"name" validate:"required,min=2,max=80"`
+	Group           string                  `json:"group" validate:"required,min=2,max=40"`
+	DiscountPercent float64                 `json:"discountPercent" validate:"gte=0,lt=100"`
+	Prices          []PriceListPriceRequest `json:"prices" validate:"dive"`
+	Active          *bool                   `json:"active"`
+}
+
+type PriceListPriceRequest struct {
+	ProductID string `json:"productId" validate:"required"`
+	Price     Money  `json:"price" validate:"gt=0"`
+}
+
+// CustomerGroupRequest puts a customer in a pricing group, or takes them out
+// of it with an empty group.
+type CustomerGroupRequest struct {
+	Group string `json:"group" validate:"max=40"`
+}
@@ -7,14 +7,76 @@ import (
 )
 
 type Product struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
-	Title       string             `bson:"title" json:"title" validate:"required,min=3"`
-	Description string             `bson:"description" json:"description" validate:"required,min=10"`
-	Images      []string           `bson:"images" json:"images" validate:"required,min=1"`
-	Price       float64            `bson:"price" json:"price" validate:"required,gt=0"`
-	Category    string             `bson:"category" json:"category" validate:"required"`
-	Sizes       []string           `bson:"sizes" json:"sizes,omitempty"`
-	InStock     int                `bson:"inStock" json:"inStock" validate:"required,gte=0"`
-	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID    primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Title string             `bson:"title" json:"title" validate:"required,min=3"`
+	Slug  string             `bson:"slug,omitempty" json:"slug"`
+	SKU   string             `bson:"sku,omitempty" json:"sku,omitempty"`
+	// PreviousSlugs are slugs the product used to have, kept so old links
+	// can redirect to the current one.
+	PreviousSlugs []string `bson:"previousSlugs,omitempty" json:"-"`
+	Description   string   `bson:"description" json:"description" validate:"required,min=10"`
+	Images        []string `bson:"images" json:"images" validate:"required,min=1"`
+	// Uploads are the images uploaded through the media pipeline. The large
+	// rendition of each is also listed in Images.
+	Uploads []ProductImage `bson:"uploads,omitempty" json:"uploads,omitempty"`
+	Price   Money          `bson:"price" json:"price" validate:"required,gt=0"`
+	// CompareAtPrice is the list price (MSRP) shown struck through next to a
+	// lower selling price.
+	CompareAtPrice *Money `bson:"compareAtPrice,omitempty" json:"compareAtPrice,omitempty" validate:"omitempty,gt=0"`
+	// Sales are scheduled sale prices. While one is running the product
+	// sells at its price if that is lower.
+	Sales []ProductSale `bson:"sales,omitempty" json:"sales,omitempty" validate:"dive"`
+	// Pricing is the price resolved for the current customer and time. It is
+	// filled in by the PricingService and never stored.
+	Pricing *ProductPricing `bson:"-" json:"pricing,omitempty"`
+	// Category is the name of the category CategoryID points at, kept on the
+	// product for order snapshots and reports.
+	Category   string              `bson:"category" json:"category" validate:"required"`
+	CategoryID *primitive.ObjectID `bson:"categoryId,omitempty" json:"categoryId,omitempty"`
+	Sizes      []string            `bson:"sizes" json:"sizes,omitempty"`
+	Tags       []string            `bson:"tags,omitempty" json:"tags,omitempty"`
+	InStock    int                 `bson:"inStock" json:"inStock" validate:"required,gte=0"`
+	// Status is one of the constants.ProductStatus values. Only active
+	// products are shown publicly. A scheduled product goes live at
+	// PublishAt, and an active one is archived at UnpublishAt.
+	Status      string     `bson:"status" json:"status"`
+	PublishAt   *time.Time `bson:"publishAt,omitempty" json:"publishAt,omitempty"`
+	UnpublishAt *time.Time `bson:"unpublishAt,omitempty" json:"unpublishAt,omitempty"`
+	// DeletedAt is set when an admin deletes the product. Deleted products
+	// are hidden everywhere but stay referable until they are purged.
+	DeletedAt *time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	// RatingAverage and RatingCount summarize the approved reviews.
+	RatingAverage float64   `bson:"ratingAverage" json:"ratingAverage"`
+	RatingCount   int       `bson:"ratingCount" json:"ratingCount"`
+	CreatedAt     time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ProductSale is a sale price that applies from StartsAt until EndsAt.
+type ProductSale struct {
+	Price    Money     `bson:"price" json:"price" validate:"gt=0"`
+	StartsAt time.Time `bson:"startsAt" json:"startsAt" validate:"required"`
+	EndsAt   time.Time `bson:"endsAt" json:"endsAt" validate:"required,gtfield=StartsAt"`
+}
+
+// ProductPricing is what a particular customer pays for a product right now.
+// CompareAtPrice is the higher price to show it against, if any.
+type ProductPricing struct {
+	Price          Money      `json:"price"`
+	CompareAtPrice *Money     `json:"compareAtPrice,omitempty"`
+	OnSale         bool       `json:"onSale"`
+	SaleEndsAt     *time.Time `json:"saleEndsAt,omitempty"`
+	PriceList      string     `json:"priceList,omitempty"`
+}
+
+// ProductImage is an uploaded product image and the URLs of its resized
+// renditions.
+type ProductImage struct {
+	ID         string    `bson:"id" json:"id"`
+	Thumbnail  string    `bson:"thumbnail" json:"thumbnail"`
+	Medium     string    `bson:"medium" json:"medium"`
+	Large      string    `bson:"large" json:"large"`
+	Width      int       `bson:"width" json:"width"`
+	Height     int       `bson:"height" json:"height"`
+	UploadedAt time.Time `bson:"uploadedAt" json:"uploadedAt"`
 }
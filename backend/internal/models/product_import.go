@@ -0,0 +1,21 @@
+package models
+
+// ProductImportReport summarizes a CSV product import. On a dry run Created
+// and Updated count what the import would do; nothing is written.
+type ProductImportReport struct {
+	DryRun  bool                 `json:"dryRun"`
+	Rows    int                  `json:"rows"`
+	Valid   int                  `json:"valid"`
+	Invalid int                  `json:"invalid"`
+	Created int                  `json:"created"`
+	Updated int                  `json:"updated"`
+	Errors  []ProductImportError `json:"errors"`
+}
+
+// ProductImportError is a problem with one field of one CSV row. Row counts
+// from 1 at the first line after the header.
+type ProductImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
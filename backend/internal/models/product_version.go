@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProductVersion is one entry in a product's change history: the fields a
+// single create, update, rollback or import changed, and who made it.
+type ProductVersion struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	ProductID primitive.ObjectID `bson:"productId" json:"productId"`
+	// Version counts up from 1 for each product.
+	Version int    `bson:"version" json:"version"`
+	Action  string `bson:"action" json:"action"`
+	AdminID string `bson:"adminId,omitempty" json:"adminId,omitempty"`
+	// RolledBackTo is the version a rollback restored.
+	RolledBackTo int                  `bson:"rolledBackTo,omitempty" json:"rolledBackTo,omitempty"`
+	Changes      []ProductFieldChange `bson:"changes" json:"changes"`
+	CreatedAt    time.Time            `bson:"createdAt" json:"createdAt"`
+}
+
+// ProductFieldChange is a field's value before and after a change. From is
+// nil for fields set when the product was created.
+type ProductFieldChange struct {
+	Field string      `bson:"field" json:"field"`
+	From  interface{} `bson:"from" json:"from"`
+	To    interface{} `bson:"to" json:"to"`
+}
+
+// PricePoint is a price a product was sold at, from the moment it took
+// effect.
+type PricePoint struct {
+	Price Money     `json:"price"`
+	Since time.Time `json:"since"`
+}
+
+type RollbackRequest struct {
+	Version int `json:"version"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Question is a shopper's question about a product. AnswerCount counts only
+// approved answers.
+type Question struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	ProductID   primitive.ObjectID `bson:"productId" json:"productId"`
+	UserID      string             `bson:"userId" json:"userId"`
+	UserName    string             `bson:"userName" json:"userName"`
+	Body        string             `bson:"body" json:"body"`
+	AnswerCount int                `bson:"answerCount" json:"answerCount"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	TopAnswer   *Answer            `bson:"-" json:"topAnswer,omitempty"`
+}
+
+// Answer replies to a question. Answers from verified buyers wait for
+// moderation; answers from the store are published straight away.
+type Answer struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	QuestionID    primitive.ObjectID `bson:"questionId" json:"questionId"`
+	ProductID     primitive.ObjectID `bson:"productId" json:"productId"`
+	UserID        string             `bson:"userId" json:"userId"`
+	UserName      string             `bson:"userName" json:"userName"`
+	Body          string             `bson:"body" json:"body"`
+	FromStore     bool               `bson:"fromStore" json:"fromStore"`
+	VerifiedBuyer bool               `bson:"verifiedBuyer" json:"verifiedBuyer"`
+	Status        string             `bson:"status" json:"status"`
+	Upvotes       int                `bson:"upvotes" json:"upvotes"`
+	ModeratedBy   string             `bson:"moderatedBy,omitempty" json:"moderatedBy,omitempty"`
+	ModeratedAt   *time.Time         `bson:"moderatedAt,omitempty" json:"moderatedAt,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+type QuestionRequest struct {
+	Body string `json:"body" validate:"required,min=10,max=1000"`
+}
+
+type AnswerRequest struct {
+	Body string `json:"body" validate:"required,min=2,max=2000"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Refund struct {
+	ID        uuid.UUID  `json:"id"`
+	OrderID   uuid.UUID  `json:"orderId"`
+	Amount    Money      `json:"amount"`
+	Reason    string     `json:"reason"`
+	Reference string     `json:"reference,omitempty"`
+	Status    string     `json:"status"`
+	SettledAt *time.Time `json:"settledAt,omitempty"`
+	SettledBy string     `json:"settledBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReturnRequest struct {
+	ID           uuid.UUID     `json:"id"`
+	OrderID      uuid.UUID     `json:"orderId"`
+	OrderItemID  uuid.UUID     `json:"orderItemId"`
+	UserID       string        `json:"userId"`
+	Quantity     int           `json:"quantity"`
+	Reason       string        `json:"reason"`
+	Status       string        `json:"status"`
+	RefundAmount Money         `json:"refundAmount"`
+	AdminNote    string        `json:"adminNote,omitempty"`
+	History      []ReturnEvent `json:"history,omitempty"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+	// RestockedAt is set once the returned units are back in stock. A
+	// received return without it is waiting for its restock to be retried.
+	RestockedAt *time.Time `json:"restockedAt,omitempty"`
+}
+
+type ReturnEvent struct {
+	ID        uuid.UUID `json:"id"`
+	ReturnID  uuid.UUID `json:"returnId"`
+	Status    string    `json:"status"`
+	Note      string    `json:"note,omitempty"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Review is a verified purchaser's rating of a product. Only approved
+// reviews are shown publicly or counted in the product's rating.
+type Review struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	ProductID      primitive.ObjectID `bson:"productId" json:"productId"`
+	UserID         string             `bson:"userId" json:"userId"`
+	UserName       string             `bson:"userName" json:"userName"`
+	OrderID        string             `bson:"orderId" json:"orderId"`
+	Rating         int                `bson:"rating" json:"rating"`
+	Title          string             `bson:"title,omitempty" json:"title,omitempty"`
+	Body           string             `bson:"body" json:"body"`
+	Photos         []string           `bson:"photos,omitempty" json:"photos,omitempty"`
+	Status         string             `bson:"status" json:"status"`
+	HelpfulCount   int                `bson:"helpfulCount" json:"helpfulCount"`
+	ModeratedBy    string             `bson:"moderatedBy,omitempty" json:"moderatedBy,omitempty"`
+	ModerationNote string             `bson:"moderationNote,omitempty" json:"moderationNote,omitempty"`
+	ModeratedAt    *time.Time         `bson:"moderatedAt,omitempty" json:"moderatedAt,omitempty"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+type CreateReviewRequest struct {
+	Rating int      `json:"rating" validate:"required,min=1,max=5"`
+	Title  string   `json:"title" validate:"max=120"`
+	Body   string   `json:"body" validate:"required,min=10,max=5000"`
+	Photos []string `json:"photos" validate:"max=5,dive,url"`
+}
+
+// RatingSummary is the denormalized rating kept on a product.
+type RatingSummary struct {
+	Average float64 `bson:"average" json:"average"`
+	Count   int     `bson:"count" json:"count"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shipment is a parcel handed to a carrier. An order may be split across
+// several shipments, each carrying some quantity of its lines.
+type Shipment struct {
+	ID                uuid.UUID       `json:"id"`
+	OrderID           uuid.UUID       `json:"orderId"`
+	Carrier           string          `json:"carrier"`
+	TrackingNumber    string          `json:"trackingNumber"`
+	Status            string          `json:"status"`
+	Items             []ShipmentItem  `json:"items"`
+	Events            []TrackingEvent `json:"events,omitempty"`
+	EstimatedDelivery *time.Time      `json:"estimatedDelivery,omitempty"`
+	ShippedAt         time.Time       `json:"shippedAt"`
+	DeliveredAt       *time.Time      `json:"deliveredAt,omitempty"`
+	CreatedAt         time.Time       `json:"createdAt"`
+	UpdatedAt         time.Time       `json:"updatedAt"`
+}
+
+type ShipmentItem struct {
+	OrderItemID uuid.UUID `json:"orderItemId"`
+	Quantity    int       `json:"quantity"`
+}
+
+// TrackingEvent is one step of a shipment's journey, either reported by the
+// carrier or entered by an admin.
+type TrackingEvent struct {
+	ID          uuid.UUID `json:"id"`
+	ShipmentID  uuid.UUID `json:"shipmentId"`
+	Status      string    `json:"status"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
+
+type CreateShipmentRequest struct {
+	Carrier           string         `json:"carrier"`
+	TrackingNumber    string         `json:"trackingNumber"`
+	EstimatedDelivery *time.Time     `json:"estimatedDelivery"`
+	Items             []ShipmentItem `json:"items"`
+}
@@ -7,12 +7,21 @@ import (
 )
 
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name      string             `bson:"name" json:"name" validate:"required"`
-	Email     string             `bson:"email" json:"email" validate:"required,email"`
-	Password  string             `bson:"password" json:"password" validate:"required,min=6"`
-	Role      string             `bson:"role" json:"role"`
-	Phone     string             `bson:"phone" json:"phone" validate:"omitempty,min=12,max=13"`
-	Address   string             `bson:"address" json:"address" validate:"omitempty,min=10"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name          string             `bson:"name" json:"name" validate:"required"`
+	Email         string             `bson:"email" json:"email" validate:"required,email"`
+	Password      string             `bson:"password" json:"password" validate:"required,min=6"`
+	Role          string             `bson:"role" json:"role"`
+	CustomerGroup string             `bson:"customerGroup,omitempty" json:"customerGroup,omitempty"`
+	Phone         string             `bson:"phone" json:"phone" validate:"omitempty,min=12,max=13"`
+	PhoneVerified bool               `bson:"phoneVerified" json:"phoneVerified"`
+	Address       string             `bson:"address" json:"address" validate:"omitempty,min=10"`
+	Addresses     []Address          `bson:"addresses,omitempty" json:"addresses,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// PhoneVerificationRequest confirms the phone number an admin has checked
+// with the customer. It must match the number on the account.
+type PhoneVerificationRequest struct {
+	Phone string `json:"phone" validate:"required"`
 }
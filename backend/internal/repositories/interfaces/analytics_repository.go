@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// AnalyticsRepository reads sales figures from the daily rollup tables. Day
+// arguments are calendar dates in the store's time zone, both inclusive.
+type AnalyticsRepository interface {
+	RefreshSalesRollups(ctx context.Context, since time.Time, timezone string) error
+	// NeedsSalesBackfill reports whether placed orders exist from before the
+	// earliest rolled-up day, which is always the case while the rollups are
+	// empty.
+	NeedsSalesBackfill(ctx context.Context, timezone string) (bool, error)
+	GetSalesSummary(ctx context.Context, from, to time.Time) (models.SalesSummary, error)
+	GetSalesSeries(ctx context.Context, from, to time.Time, interval string) ([]models.SalesPoint, error)
+	GetTopProducts(ctx context.Context, from, to time.Time, limit int) ([]models.TopProduct, error)
+	GetTopCategories(ctx context.Context, from, to time.Time, limit int) ([]models.TopCategory, error)
+	GetStatusCounts(ctx context.Context, from, to time.Time, timezone string) ([]models.StatusCount, error)
+	GetSalesVelocity(ctx context.Context, since time.Time, limit int) ([]models.ProductVelocity, error)
+	GetCoPurchases(ctx context.Context, since time.Time, perProduct int) ([]models.CoPurchase, error)
+}
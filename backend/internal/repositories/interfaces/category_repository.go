@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// ErrCategorySlugTaken is returned when two categories would share a slug.
+var ErrCategorySlugTaken = errors.New("a category with this slug already exists")
+
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, category *models.Category) error
+	GetCategoryByID(ctx context.Context, id string) (*models.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error)
+	ListCategories(ctx context.Context, visibleOnly bool) ([]*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id primitive.ObjectID) error
+	CountChildren(ctx context.Context, id primitive.ObjectID) (int64, error)
+	// GetDescendantIDs returns id followed by the IDs of every category below
+	// it. With visibleOnly, hidden categories and their subtrees are skipped.
+	GetDescendantIDs(ctx context.Context, id primitive.ObjectID, visibleOnly bool) ([]primitive.ObjectID, error)
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// ErrCollectionSlugTaken is returned when two collections would share a slug.
+var ErrCollectionSlugTaken = errors.New("a collection with this slug already exists")
+
+type CollectionRepository interface {
+	CreateCollection(ctx context.Context, collection *models.Collection) error
+	GetCollectionByID(ctx context.Context, id string) (*models.Collection, error)
+	GetCollectionBySlug(ctx context.Context, slug string) (*models.Collection, error)
+	ListCollections(ctx context.Context, visibleOnly bool) ([]*models.Collection, error)
+	UpdateCollection(ctx context.Context, collection *models.Collection) error
+	DeleteCollection(ctx context.Context, id string) error
+	// ConvertLegacyPrices converts rule price bounds stored as numbers of
+	// major units to amounts in minor units of currency.
+	ConvertLegacyPrices(ctx context.Context, currency string) (int64, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// InvoiceRenderer produces the document for an invoice once its number has
+// been assigned.
+type InvoiceRenderer func(invoice *models.Invoice) ([]byte, error)
+
+type InvoiceRepository interface {
+	GetInvoiceByOrderID(ctx context.Context, orderID string) (*models.Invoice, error)
+	CreateInvoice(ctx context.Context, invoice *models.Invoice, prefix string, render InvoiceRenderer) (*models.Invoice, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+type JobRepository interface {
+	CreateJob(ctx context.Context, job *models.Job) error
+	GetJobByID(ctx context.Context, id string) (*models.Job, error)
+	UpdateJob(ctx context.Context, job *models.Job) error
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// MerchandisingRepository stores the best seller rankings and the curated
+// featured list that the product listings read from.
+type MerchandisingRepository interface {
+	ReplaceBestSellers(ctx context.Context, rankings []models.BestSeller) error
+	ReplaceBoughtTogether(ctx context.Context, lists []models.BoughtTogether) error
+	ListFeatured(ctx context.Context) ([]*models.FeaturedProduct, error)
+	GetFeatured(ctx context.Context, id string) (*models.FeaturedProduct, error)
+	AddFeatured(ctx context.Context, featured *models.FeaturedProduct) error
+	UpdateFeatured(ctx context.Context, featured *models.FeaturedProduct) error
+	RemoveFeatured(ctx context.Context, id string) error
+}
@@ -2,15 +2,46 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 
 	"github.com/Shrey-Yash/Masked11/internal/models"
 )
 
+var (
+	// ErrOrderStatusChanged is returned when an order is no longer in the
+	// status a change was checked against, because another request moved it.
+	ErrOrderStatusChanged = errors.New("order status has changed")
+	// ErrOrderItemsChanged is returned when order lines are no longer in the
+	// state a change was checked against.
+	ErrOrderItemsChanged = errors.New("order items have changed")
+	// ErrOrderInvoiced is returned when an order cannot be deleted because
+	// an invoice has been issued for it.
+	ErrOrderInvoiced = errors.New("order has an invoice")
+)
+
 type OrderRepository interface {
 	CreateOrder(order *models.Order, items []models.OrderItem) error
 	GetOrdersByUserID(ctx context.Context, userID string) ([]models.Order, error)
 	GetOrderByID(ctx context.Context, orderID string) (*models.Order, error)
+	GetOrderByNumber(ctx context.Context, orderNumber string) (*models.Order, error)
+	ListOrders(ctx context.Context, filters map[string]interface{}) ([]models.Order, int64, error)
+	CountOrders(ctx context.Context, filters map[string]interface{}) (int64, error)
+	StreamOrderExport(ctx context.Context, filters map[string]interface{}, fn func(row models.OrderExportRow) error) error
 	UpdateOrderStatus(orderID string, status string) error
+	// DeliverCODOrder marks a COD order still in status from as DELIVERED and
+	// records payment in the same transaction. It returns
+	// ErrOrderStatusChanged when the order has moved on.
+	DeliverCODOrder(ctx context.Context, orderID, from string, payment *models.Payment) error
 	DeleteOrder(orderID string) error
 	CancelOrder(orderID string) error
+	// CancelOrderItems cancels active lines of an order still in status from
+	// and, when refund is not nil, records it in the same transaction as
+	// PaymentRepository.CreateRefund does.
+	CancelOrderItems(ctx context.Context, orderID, from string, itemIDs []string, refund *models.Refund, unrecorded models.Money) error
+	GetDeliveredOrderID(ctx context.Context, userID, productID string) (string, error)
+	// GetUserIDsWithoutEmail returns the customers who have orders with no
+	// customer email recorded.
+	GetUserIDsWithoutEmail(ctx context.Context) ([]string, error)
+	// SetCustomerEmail records email on a customer's orders that have none.
+	SetCustomerEmail(ctx context.Context, userID, email string) (int64, error)
 }
\ No newline at end of file
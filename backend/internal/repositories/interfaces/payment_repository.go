@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// ErrRefundExceedsPaid is returned when a refund would take back more than
+// is left of what was paid for the order.
+var ErrRefundExceedsPaid = errors.New("refund exceeds the amount paid")
+
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, payment *models.Payment) error
+	GetPaymentsByOrderID(ctx context.Context, orderID string) ([]models.Payment, error)
+	GetUnreconciledCODPayments(ctx context.Context) ([]models.Payment, error)
+	ReconcileCODPayments(ctx context.Context, orderIDs []string, adminID string) (int64, error)
+	// CreateRefund records a PENDING refund if what is left of the amount
+	// paid for the order covers it. unrecorded is taken as the amount paid
+	// when the order has no payment rows.
+	CreateRefund(ctx context.Context, refund *models.Refund, unrecorded models.Money) error
+	UpdateRefundStatus(ctx context.Context, refund *models.Refund) error
+	GetPendingRefunds(ctx context.Context, before time.Time) ([]models.Refund, error)
+	GetUnsettledRefunds(ctx context.Context) ([]models.Refund, error)
+	SettleRefunds(ctx context.Context, refundIDs []string, reference, adminID string) (int64, error)
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// ErrPriceListGroupTaken is returned when two price lists would serve the
+// same customer group.
+var ErrPriceListGroupTaken = errors.New("a price list for this customer group already exists")
+
+type PriceListRepository interface {
+	CreatePriceList(ctx context.Context, list *models.PriceList) error
+	GetPriceListByID(ctx context.Context, id string) (*models.PriceList, error)
+	// GetActivePriceList returns the active price list for a customer group.
+	GetActivePriceList(ctx context.Context, group string) (*models.PriceList, error)
+	ListPriceLists(ctx context.Context) ([]*models.PriceList, error)
+	UpdatePriceList(ctx context.Context, list *models.PriceList) error
+	DeletePriceList(ctx context.Context, id string) error
+	// ConvertLegacyPrices converts fixed prices stored as numbers of major
+	// units to amounts in minor units of currency.
+	ConvertLegacyPrices(ctx context.Context, currency string) (int64, error)
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+type ProductHistoryRepository interface {
+	// AddVersion stores version as the product's next version number.
+	AddVersion(ctx context.Context, version *models.ProductVersion) error
+	GetVersion(ctx context.Context, productID primitive.ObjectID, version int) (*models.ProductVersion, error)
+	// ListVersions pages through a product's history, newest first.
+	ListVersions(ctx context.Context, productID primitive.ObjectID, filters map[string]interface{}) ([]*models.ProductVersion, int64, error)
+	// GetVersionsAfter returns the versions newer than version, newest first.
+	GetVersionsAfter(ctx context.Context, productID primitive.ObjectID, version int) ([]*models.ProductVersion, error)
+	// GetPriceChanges returns the versions that changed the price, oldest
+	// first.
+	GetPriceChanges(ctx context.Context, productID primitive.ObjectID) ([]*models.ProductVersion, error)
+}
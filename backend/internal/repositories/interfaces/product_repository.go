@@ -2,24 +2,61 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/Shrey-Yash/Masked11/internal/models"
 )
 
+// ErrInsufficientStock is returned by AdjustStock when a decrement would take
+// a product's stock below zero.
+var ErrInsufficientStock = errors.New("insufficient stock")
+
 type ProductRepository interface {
 	CreateProduct(ctx context.Context, product *models.Product) error
 	GetProductByID(ctx context.Context, id string) (*models.Product, error)
 	GetAllProducts(ctx context.Context) ([]*models.Product, error)
+	GetProductsByIDs(ctx context.Context, ids []string) ([]*models.Product, error)
+	GetProductBySlug(ctx context.Context, slug string) (*models.Product, error)
+	SlugTaken(ctx context.Context, slug, excludeID string) (bool, error)
+	GetProductsWithoutSlug(ctx context.Context, limit int) ([]*models.Product, error)
 	UpdateProduct(ctx context.Context, id string, updates map[string]interface{}) error
+	GetProductsBySKUsOrSlugs(ctx context.Context, skus, slugs []string) ([]*models.Product, error)
+	// BulkUpsertProducts inserts the products without an ID and updates the
+	// rest. Nil Images, Sizes or Tags leave the stored values alone.
+	BulkUpsertProducts(ctx context.Context, products []*models.Product) (inserted, updated int64, err error)
+	StreamProducts(ctx context.Context, fn func(*models.Product) error) error
 	DeleteProduct(ctx context.Context, id string) error
+	RestoreProduct(ctx context.Context, id string) error
+	GetDeletedProducts(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error)
+	GetProductsDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Product, error)
+	PurgeProduct(ctx context.Context, id primitive.ObjectID, before time.Time) (bool, error)
+	AdjustStock(ctx context.Context, id string, delta int) error
+	AddProductImage(ctx context.Context, id string, image models.ProductImage) error
+	RemoveProductImage(ctx context.Context, id string, image models.ProductImage) error
+	// ApplyProductSchedules publishes and archives the products whose
+	// publishAt or unpublishAt has passed.
+	ApplyProductSchedules(ctx context.Context, now time.Time) (published, unpublished int64, err error)
+	ActivateLegacyProducts(ctx context.Context) (int64, error)
+	// ConvertLegacyPrices converts prices stored as numbers of major units
+	// to amounts in minor units of currency.
+	ConvertLegacyPrices(ctx context.Context, currency string) (int64, error)
+	CountSaleBoundaries(ctx context.Context, from, to time.Time) (int64, error)
 	GetAllProductsWithFilters(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error)
 	SearchProducts(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error)
 	GetProductsByCategory(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error)
+	GetProductsByRules(ctx context.Context, rules *models.CollectionRules, filters map[string]interface{}) ([]*models.Product, int64, error)
 	GetFeaturedProducts(ctx context.Context, limit int) ([]*models.Product, error)
-	GetProductCategories(ctx context.Context) ([]string, error)
+	GetUnlinkedCategoryNames(ctx context.Context) ([]string, error)
+	LinkCategory(ctx context.Context, name string, category *models.Category) error
+	RenameCategory(ctx context.Context, category *models.Category) error
+	CountProductsInCategory(ctx context.Context, categoryID primitive.ObjectID) (int64, error)
 	GetProductsByPriceRange(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error)
 	GetProductsInStock(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error)
 	GetNewArrivals(ctx context.Context, limit int) ([]*models.Product, error)
 	GetBestSellers(ctx context.Context, limit int) ([]*models.Product, error)
 	GetRelatedProducts(ctx context.Context, productID string, limit int) ([]*models.Product, error)
+	GetBoughtTogether(ctx context.Context, productID string, limit int) ([]*models.Product, error)
 }
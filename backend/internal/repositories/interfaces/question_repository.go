@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+type QuestionRepository interface {
+	CreateQuestion(ctx context.Context, question *models.Question) error
+	GetQuestionByID(ctx context.Context, id string) (*models.Question, error)
+	ListQuestions(ctx context.Context, filters map[string]interface{}) ([]*models.Question, int64, error)
+	IncrementAnswerCount(ctx context.Context, questionID string, delta int) error
+	CreateAnswer(ctx context.Context, answer *models.Answer) error
+	GetAnswerByID(ctx context.Context, id string) (*models.Answer, error)
+	ListAnswers(ctx context.Context, filters map[string]interface{}) ([]*models.Answer, int64, error)
+	UpdateAnswerStatus(ctx context.Context, answer *models.Answer) error
+	AddAnswerVote(ctx context.Context, answerID, userID string) error
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import "time"
+
+// RecentlyViewedRepository keeps capped, most-recent-first lists of product
+// IDs per user or guest session.
+type RecentlyViewedRepository interface {
+	Record(key, productID string, limit int, ttl time.Duration) error
+	List(key string) ([]string, error)
+	Merge(fromKey, toKey string, limit int, ttl time.Duration) error
+}
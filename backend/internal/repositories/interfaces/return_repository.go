@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+var (
+	// ErrReturnStatusChanged is returned when a return is no longer in the
+	// status a transition starts from, because another request moved it first.
+	ErrReturnStatusChanged = errors.New("return status has changed")
+	// ErrReturnExceedsOrder is returned when a return asks for more units of
+	// an order line than remain unreturned.
+	ErrReturnExceedsOrder = errors.New("return exceeds unreturned quantity")
+)
+
+type ReturnRepository interface {
+	// CreateReturn stores a new return for an active order line. It returns
+	// ErrReturnExceedsOrder when the line has too few unreturned units left
+	// and ErrOrderItemsChanged when the line is no longer active.
+	CreateReturn(ctx context.Context, ret *models.ReturnRequest, actor string) error
+	GetReturnByID(ctx context.Context, id string) (*models.ReturnRequest, error)
+	GetReturnsByUserID(ctx context.Context, userID string) ([]models.ReturnRequest, error)
+	GetReturns(ctx context.Context, status string) ([]models.ReturnRequest, error)
+	GetReturnedQuantity(ctx context.Context, orderItemID string) (int, error)
+	// UpdateReturnStatus moves a return from status from to ret.Status.
+	UpdateReturnStatus(ctx context.Context, ret *models.ReturnRequest, from, note, actor string) error
+	// RefundReturn moves a return from status from to REFUNDED and records
+	// its refund in the same transaction, as PaymentRepository.CreateRefund
+	// does.
+	RefundReturn(ctx context.Context, ret *models.ReturnRequest, from string, refund *models.Refund, unrecorded models.Money, actor string) error
+	// ClaimRestock marks a received return restocked unless it already is,
+	// reporting whether this call made the claim. ReleaseRestock undoes a
+	// claim whose restock failed.
+	ClaimRestock(ctx context.Context, id string, at time.Time) (bool, error)
+	ReleaseRestock(ctx context.Context, id string) error
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+var (
+	// ErrDuplicateReview is returned when a user reviews a product twice.
+	ErrDuplicateReview = errors.New("you have already reviewed this product")
+	// ErrAlreadyVoted is returned when a user marks a review helpful twice.
+	ErrAlreadyVoted = errors.New("you have already voted on this review")
+)
+
+type ReviewRepository interface {
+	CreateReview(ctx context.Context, review *models.Review) error
+	GetReviewByID(ctx context.Context, id string) (*models.Review, error)
+	ListReviews(ctx context.Context, filters map[string]interface{}) ([]*models.Review, int64, error)
+	UpdateReviewStatus(ctx context.Context, review *models.Review) error
+	AddHelpfulVote(ctx context.Context, reviewID, userID string) error
+	GetRatingSummary(ctx context.Context, productID string) (models.RatingSummary, error)
+}
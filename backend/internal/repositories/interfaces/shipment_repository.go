@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// ErrShipmentExceedsOrder is returned when a shipment carries more units of an
+// order line than remain unshipped.
+var ErrShipmentExceedsOrder = errors.New("shipment exceeds unshipped quantity")
+
+type ShipmentRepository interface {
+	// CreateShipment stores a shipment for an order in one of statuses and
+	// moves the order to SHIPPED once every active unit has shipped, or to
+	// PARTIALLY_SHIPPED before then. It returns ErrOrderStatusChanged or
+	// ErrShipmentExceedsOrder when the order no longer allows the shipment.
+	CreateShipment(ctx context.Context, shipment *models.Shipment, statuses []string) error
+	GetShipmentByID(ctx context.Context, id string) (*models.Shipment, error)
+	GetShipmentsByOrderID(ctx context.Context, orderID string) ([]models.Shipment, error)
+	GetShippedQuantities(ctx context.Context, orderID string) (map[string]int, error)
+	AddTrackingEvents(ctx context.Context, shipment *models.Shipment, events []models.TrackingEvent) error
+}
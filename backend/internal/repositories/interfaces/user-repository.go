@@ -12,5 +12,11 @@ type UserRepository interface {
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserByID(id primitive.ObjectID) (*models.User, error)
 	UpdateUser(id primitive.ObjectID, updated bson.M) error
+	// VerifyPhone marks the user's phone verified if it is still phone,
+	// reporting whether it was.
+	VerifyPhone(id primitive.ObjectID, phone string) (bool, error)
 	DeleteUser(id primitive.ObjectID) error
+	AddAddress(id primitive.ObjectID, address models.Address) error
+	RemoveAddress(id primitive.ObjectID, addressID string) error
+	SetDefaultAddress(id primitive.ObjectID, addressID string) error
 }
@@ -0,0 +1,161 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type categoryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCategoryRepository(db *mongo.Database) interfaces.CategoryRepository {
+	return &categoryRepository{collection: db.Collection("categories")}
+}
+
+func (r *categoryRepository) CreateCategory(ctx context.Context, category *models.Category) error {
+	category.ID = primitive.NewObjectID()
+	_, err := r.collection.InsertOne(ctx, category)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrCategorySlugTaken
+	}
+	return err
+}
+
+func (r *categoryRepository) GetCategoryByID(ctx context.Context, id string) (*models.Category, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var category models.Category
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ListCategories returns categories in display order: by sort order, then
+// by name.
+func (r *categoryRepository) ListCategories(ctx context.Context, visibleOnly bool) ([]*models.Category, error) {
+	filter := bson.M{}
+	if visibleOnly {
+		filter["visible"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "sortOrder", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	categories := []*models.Category{}
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) UpdateCategory(ctx context.Context, category *models.Category) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":        category.Name,
+			"slug":        category.Slug,
+			"description": category.Description,
+			"image":       category.Image,
+			"sortOrder":   category.SortOrder,
+			"visible":     category.Visible,
+			"updatedAt":   category.UpdatedAt,
+		},
+	}
+	if category.ParentID != nil {
+		update["$set"].(bson.M)["parentId"] = category.ParentID
+	} else {
+		update["$unset"] = bson.M{"parentId": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": category.ID}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrCategorySlugTaken
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no category found with ID: %s", category.ID.Hex())
+	}
+	return nil
+}
+
+func (r *categoryRepository) DeleteCategory(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("no category found with ID: %s", id.Hex())
+	}
+	return nil
+}
+
+func (r *categoryRepository) CountChildren(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"parentId": id})
+}
+
+func (r *categoryRepository) GetDescendantIDs(ctx context.Context, id primitive.ObjectID, visibleOnly bool) ([]primitive.ObjectID, error) {
+	graphLookup := bson.M{
+		"from":             "categories",
+		"startWith":        "$_id",
+		"connectFromField": "_id",
+		"connectToField":   "parentId",
+		"as":               "descendants",
+	}
+	if visibleOnly {
+		graphLookup["restrictSearchWithMatch"] = bson.M{"visible": true}
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": id}}},
+		{{Key: "$graphLookup", Value: graphLookup}},
+		{{Key: "$project", Value: bson.M{"descendants._id": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Descendants []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		} `bson:"descendants"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	ids := []primitive.ObjectID{id}
+	for _, descendant := range results[0].Descendants {
+		ids = append(ids, descendant.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,123 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type collectionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCollectionRepository(db *mongo.Database) interfaces.CollectionRepository {
+	return &collectionRepository{collection: db.Collection("collections")}
+}
+
+func (r *collectionRepository) CreateCollection(ctx context.Context, collection *models.Collection) error {
+	collection.ID = primitive.NewObjectID()
+	_, err := r.collection.InsertOne(ctx, collection)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrCollectionSlugTaken
+	}
+	return err
+}
+
+func (r *collectionRepository) GetCollectionByID(ctx context.Context, id string) (*models.Collection, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var collection models.Collection
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&collection); err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+func (r *collectionRepository) GetCollectionBySlug(ctx context.Context, slug string) (*models.Collection, error) {
+	var collection models.Collection
+	if err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&collection); err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+func (r *collectionRepository) ListCollections(ctx context.Context, visibleOnly bool) ([]*models.Collection, error) {
+	filter := bson.M{}
+	if visibleOnly {
+		filter["visible"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "title", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	collections := []*models.Collection{}
+	if err := cursor.All(ctx, &collections); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+// UpdateCollection replaces a collection's stored definition, keeping its ID
+// and creation time.
+func (r *collectionRepository) UpdateCollection(ctx context.Context, collection *models.Collection) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": collection.ID}, collection)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrCollectionSlugTaken
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no collection found with ID: %s", collection.ID.Hex())
+	}
+	return nil
+}
+
+func (r *collectionRepository) DeleteCollection(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("no collection found with ID: %s", id)
+	}
+	return nil
+}
+
+// ConvertLegacyPrices rewrites the rule price bounds stored as plain numbers
+// in major units as amounts in minor units of currency.
+func (r *collectionRepository) ConvertLegacyPrices(ctx context.Context, currency string) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"rules.minPrice": bson.M{"$type": "number"}},
+			bson.M{"rules.maxPrice": bson.M{"$type": "number"}},
+		}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"rules.minPrice": legacyMoney("$rules.minPrice", currency),
+			"rules.maxPrice": legacyMoney("$rules.maxPrice", currency),
+		}}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/internal/database"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type merchandisingRepository struct {
+	bestSellers    *mongo.Collection
+	boughtTogether *mongo.Collection
+	featured       *mongo.Collection
+}
+
+func NewMerchandisingRepository(db *mongo.Database) interfaces.MerchandisingRepository {
+	return &merchandisingRepository{
+		bestSellers:    db.Collection("best_sellers"),
+		boughtTogether: db.Collection("bought_together"),
+		featured:       db.Collection("featured_products"),
+	}
+}
+
+// ReplaceBestSellers upserts the new rankings and then drops any product
+// that fell out of them, so readers never see an empty list mid-refresh.
+func (r *merchandisingRepository) ReplaceBestSellers(ctx context.Context, rankings []models.BestSeller) error {
+	computedAt := time.Now()
+	if len(rankings) > 0 {
+		computedAt = rankings[0].ComputedAt
+
+		operations := make([]mongo.WriteModel, 0, len(rankings))
+		for _, ranking := range rankings {
+			operations = append(operations, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": ranking.ProductID}).
+				SetReplacement(ranking).
+				SetUpsert(true))
+		}
+		if _, err := database.BulkWrite(r.bestSellers, operations); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.bestSellers.DeleteMany(ctx, bson.M{"computedAt": bson.M{"$lt": computedAt}})
+	return err
+}
+
+// ReplaceBoughtTogether stores the co-purchase lists the same way as
+// ReplaceBestSellers, dropping products with no partners left.
+func (r *merchandisingRepository) ReplaceBoughtTogether(ctx context.Context, lists []models.BoughtTogether) error {
+	computedAt := time.Now()
+	if len(lists) > 0 {
+		computedAt = lists[0].ComputedAt
+
+		operations := make([]mongo.WriteModel, 0, len(lists))
+		for _, list := range lists {
+			operations = append(operations, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": list.ProductID}).
+				SetReplacement(list).
+				SetUpsert(true))
+		}
+		if _, err := database.BulkWrite(r.boughtTogether, operations); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.boughtTogether.DeleteMany(ctx, bson.M{"computedAt": bson.M{"$lt": computedAt}})
+	return err
+}
+
+func (r *merchandisingRepository) ListFeatured(ctx context.Context) ([]*models.FeaturedProduct, error) {
+	cursor, err := r.featured.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "position", Value: 1}, {Key: "startsAt", Value: 1}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "products",
+			"localField":   "productId",
+			"foreignField": "_id",
+			"as":           "product",
+		}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$product", "preserveNullAndEmptyArrays": true}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	featured := []*models.FeaturedProduct{}
+	if err := cursor.All(ctx, &featured); err != nil {
+		return nil, err
+	}
+	return featured, nil
+}
+
+func (r *merchandisingRepository) GetFeatured(ctx context.Context, id string) (*models.FeaturedProduct, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var featured models.FeaturedProduct
+	if err := r.featured.FindOne(ctx, bson.M{"_id": objectID}).Decode(&featured); err != nil {
+		return nil, err
+	}
+	return &featured, nil
+}
+
+func (r *merchandisingRepository) AddFeatured(ctx context.Context, featured *models.FeaturedProduct) error {
+	featured.ID = primitive.NewObjectID()
+	_, err := r.featured.InsertOne(ctx, featured)
+	return err
+}
+
+func (r *merchandisingRepository) UpdateFeatured(ctx context.Context, featured *models.FeaturedProduct) error {
+	update := bson.M{
+		"$set": bson.M{
+			"position":  featured.Position,
+			"startsAt":  featured.StartsAt,
+			"updatedAt": featured.UpdatedAt,
+		},
+	}
+	if featured.EndsAt != nil {
+		update["$set"].(bson.M)["endsAt"] = featured.EndsAt
+	} else {
+		update["$unset"] = bson.M{"endsAt": ""}
+	}
+
+	result, err := r.featured.UpdateOne(ctx, bson.M{"_id": featured.ID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *merchandisingRepository) RemoveFeatured(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.featured.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
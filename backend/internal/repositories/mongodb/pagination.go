@@ -0,0 +1,36 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// findPage decodes one page of the documents matching filter into results and
+// returns the total number of matches. The page and limit come from filters.
+func findPage(ctx context.Context, coll *mongo.Collection, filter bson.M, sort bson.D, filters map[string]interface{}, results interface{}) (int64, error) {
+	page, _ := filters["page"].(int)
+	limit, _ := filters["limit"].(int)
+	if page < 1 {
+		page = 1
+	}
+
+	total, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	return total, cursor.All(ctx, results)
+}
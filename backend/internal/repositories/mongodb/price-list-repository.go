@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type priceListRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPriceListRepository(db *mongo.Database) interfaces.PriceListRepository {
+	return &priceListRepository{collection: db.Collection("price_lists")}
+}
+
+func (r *priceListRepository) CreatePriceList(ctx context.Context, list *models.PriceList) error {
+	list.ID = primitive.NewObjectID()
+	_, err := r.collection.InsertOne(ctx, list)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrPriceListGroupTaken
+	}
+	return err
+}
+
+func (r *priceListRepository) GetPriceListByID(ctx context.Context, id string) (*models.PriceList, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var list models.PriceList
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *priceListRepository) GetActivePriceList(ctx context.Context, group string) (*models.PriceList, error) {
+	var list models.PriceList
+	if err := r.collection.FindOne(ctx, bson.M{"group": group, "active": true}).Decode(&list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *priceListRepository) ListPriceLists(ctx context.Context) ([]*models.PriceList, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	lists := []*models.PriceList{}
+	if err := cursor.All(ctx, &lists); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// UpdatePriceList replaces a price list's stored definition, keeping its ID
+// and creation time.
+func (r *priceListRepository) UpdatePriceList(ctx context.Context, list *models.PriceList) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": list.ID}, list)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrPriceListGroupTaken
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no price list found with ID: %s", list.ID.Hex())
+	}
+	return nil
+}
+
+func (r *priceListRepository) DeletePriceList(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("no price list found with ID: %s", id)
+	}
+	return nil
+}
+
+// ConvertLegacyPrices rewrites the fixed prices stored as plain numbers in
+// major units as amounts in minor units of currency.
+func (r *priceListRepository) ConvertLegacyPrices(ctx context.Context, currency string) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"prices.price": bson.M{"$type": "number"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"prices": bson.M{"$map": bson.M{
+				"input": "$prices",
+				"as":    "entry",
+				"in": bson.M{"$mergeObjects": bson.A{
+					"$$entry",
+					bson.M{"price": legacyMoney("$$entry.price", currency)},
+				}},
+			}},
+		}}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,96 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+// maxVersionAttempts bounds how often AddVersion retries when a concurrent
+// change claims the same version number.
+const maxVersionAttempts = 5
+
+type productHistoryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewProductHistoryRepository(db *mongo.Database) interfaces.ProductHistoryRepository {
+	return &productHistoryRepository{collection: db.Collection("product_versions")}
+}
+
+// AddVersion numbers version after the product's latest one. The unique
+// index on productId and version turns a race into a duplicate key error,
+// which is retried with the next number.
+func (r *productHistoryRepository) AddVersion(ctx context.Context, version *models.ProductVersion) error {
+	var err error
+	for attempt := 0; attempt < maxVersionAttempts; attempt++ {
+		var latest models.ProductVersion
+		err = r.collection.FindOne(ctx,
+			bson.M{"productId": version.ProductID},
+			options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}).SetProjection(bson.M{"version": 1}),
+		).Decode(&latest)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+
+		version.ID = primitive.NewObjectID()
+		version.Version = latest.Version + 1
+		_, err = r.collection.InsertOne(ctx, version)
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (r *productHistoryRepository) GetVersion(ctx context.Context, productID primitive.ObjectID, version int) (*models.ProductVersion, error) {
+	var v models.ProductVersion
+	if err := r.collection.FindOne(ctx, bson.M{"productId": productID, "version": version}).Decode(&v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *productHistoryRepository) ListVersions(ctx context.Context, productID primitive.ObjectID, filters map[string]interface{}) ([]*models.ProductVersion, int64, error) {
+	versions := []*models.ProductVersion{}
+	total, err := findPage(ctx, r.collection,
+		bson.M{"productId": productID},
+		bson.D{{Key: "version", Value: -1}},
+		filters, &versions,
+	)
+	return versions, total, err
+}
+
+func (r *productHistoryRepository) GetVersionsAfter(ctx context.Context, productID primitive.ObjectID, version int) ([]*models.ProductVersion, error) {
+	return r.find(ctx,
+		bson.M{"productId": productID, "version": bson.M{"$gt": version}},
+		bson.D{{Key: "version", Value: -1}},
+	)
+}
+
+func (r *productHistoryRepository) GetPriceChanges(ctx context.Context, productID primitive.ObjectID) ([]*models.ProductVersion, error) {
+	return r.find(ctx,
+		bson.M{"productId": productID, "changes.field": "price"},
+		bson.D{{Key: "version", Value: 1}},
+	)
+}
+
+func (r *productHistoryRepository) find(ctx context.Context, filter bson.M, sort bson.D) ([]*models.ProductVersion, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(sort))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	versions := []*models.ProductVersion{}
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
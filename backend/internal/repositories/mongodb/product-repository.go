@@ -3,11 +3,15 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/database"
 	"github.com/Shrey-Yash/Masked11/internal/models"
 	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
 )
@@ -60,6 +64,73 @@ func (r *productRepository) GetAllProducts(ctx context.Context) ([]*models.Produ
 	return products, nil
 }
 
+// GetProductsByIDs returns the products that exist among ids, in no
+// particular order. Malformed IDs are skipped.
+func (r *productRepository) GetProductsByIDs(ctx context.Context, ids []string) ([]*models.Product, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	if len(objectIDs) == 0 {
+		return []*models.Product{}, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	products := []*models.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// GetProductBySlug finds a product by its current slug or, failing that, by
+// one it used to have. Callers compare the returned product's Slug to tell
+// the two apart.
+func (r *productRepository) GetProductBySlug(ctx context.Context, slug string) (*models.Product, error) {
+	var product models.Product
+	err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&product)
+	if err == mongo.ErrNoDocuments {
+		err = r.collection.FindOne(ctx, bson.M{"previousSlugs": slug}).Decode(&product)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// SlugTaken reports whether any product other than excludeID uses slug now
+// or used it before.
+func (r *productRepository) SlugTaken(ctx context.Context, slug, excludeID string) (bool, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"slug": slug},
+		bson.M{"previousSlugs": slug},
+	}}
+	if objectID, err := primitive.ObjectIDFromHex(excludeID); err == nil {
+		filter["_id"] = bson.M{"$ne": objectID}
+	}
+
+	count, err := r.collection.CountDocuments(ctx, filter)
+	return count > 0, err
+}
+
+func (r *productRepository) GetProductsWithoutSlug(ctx context.Context, limit int) ([]*models.Product, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"slug": bson.M{"$exists": false}},
+		bson.M{"slug": ""},
+	}}
+
+	products := []*models.Product{}
+	_, err := findPage(ctx, r.collection, filter, bson.D{{Key: "_id", Value: 1}}, map[string]interface{}{"limit": limit}, &products)
+	return products, err
+}
+
 func (r *productRepository) UpdateProduct(ctx context.Context, id string, updates map[string]interface{}) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -82,42 +153,520 @@ func (r *productRepository) UpdateProduct(ctx context.Context, id string, update
 	return nil
 }
 
+// DeleteProduct soft deletes a product by stamping its deletedAt, so orders
+// and reviews can still refer to it until it is purged.
 func (r *productRepository) DeleteProduct(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "deletedAt": nil},
+		bson.M{"$set": bson.M{"deletedAt": time.Now()}},
+	)
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// RestoreProduct undoes a soft delete.
+func (r *productRepository) RestoreProduct(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "deletedAt": bson.M{"$ne": nil}},
+		bson.M{"$unset": bson.M{"deletedAt": ""}, "$set": bson.M{"updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// GetDeletedProducts pages through the soft deleted products, most recently
+// deleted first.
+func (r *productRepository) GetDeletedProducts(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error) {
+	filter := bson.M{"deletedAt": bson.M{"$ne": nil}}
+	sort := bson.D{{Key: "deletedAt", Value: -1}, {Key: "_id", Value: -1}}
+
+	products := []*models.Product{}
+	total, err := findPage(ctx, r.collection, filter, sort, filters, &products)
+	return products, total, err
+}
+
+func (r *productRepository) GetProductsDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Product, error) {
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"deletedAt": bson.M{"$lte": before}},
+		options.Find().SetSort(bson.D{{Key: "deletedAt", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	products := []*models.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
 
-	if result.DeletedCount == 0 {
+// PurgeProduct permanently removes a product that was soft deleted before
+// the given time. A product restored in the meantime is left alone.
+func (r *productRepository) PurgeProduct(ctx context.Context, id primitive.ObjectID, before time.Time) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "deletedAt": bson.M{"$lte": before}})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// AdjustStock atomically adds delta (which may be negative) to a product's
+// stock. Decrements that would leave the stock negative are refused.
+func (r *productRepository) AdjustStock(ctx context.Context, id string, delta int) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": objectID}
+	if delta < 0 {
+		filter["inStock"] = bson.M{"$gte": -delta}
+	}
+
+	update := bson.M{
+		"$inc": bson.M{"inStock": delta},
+		"$set": bson.M{"updatedAt": time.Now()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		if delta < 0 {
+			if count, err := r.collection.CountDocuments(ctx, bson.M{"_id": objectID}); err == nil && count > 0 {
+				return interfaces.ErrInsufficientStock
+			}
+		}
 		return fmt.Errorf("no product found with ID: %s", id)
 	}
 
 	return nil
 }
 
+// GetProductsBySKUsOrSlugs returns the products whose SKU or current slug is
+// in the given lists.
+func (r *productRepository) GetProductsBySKUsOrSlugs(ctx context.Context, skus, slugs []string) ([]*models.Product, error) {
+	if len(skus) == 0 && len(slugs) == 0 {
+		return []*models.Product{}, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"sku": bson.M{"$in": skus}},
+		bson.M{"slug": bson.M{"$in": slugs}},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	products := []*models.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productRepository) BulkUpsertProducts(ctx context.Context, products []*models.Product) (int64, int64, error) {
+	if len(products) == 0 {
+		return 0, 0, nil
+	}
+
+	operations := make([]mongo.WriteModel, 0, len(products))
+	for _, p := range products {
+		if p.ID.IsZero() {
+			p.ID = primitive.NewObjectID()
+			operations = append(operations, mongo.NewInsertOneModel().SetDocument(p))
+			continue
+		}
+
+		set := bson.M{
+			"title":       p.Title,
+			"description": p.Description,
+			"price":       p.Price,
+			"category":    p.Category,
+			"categoryId":  p.CategoryID,
+			"inStock":     p.InStock,
+			"updatedAt":   p.UpdatedAt,
+		}
+		if p.SKU != "" {
+			set["sku"] = p.SKU
+		}
+		if p.Images != nil {
+			set["images"] = p.Images
+		}
+		if p.Sizes != nil {
+			set["sizes"] = p.Sizes
+		}
+		if p.Tags != nil {
+			set["tags"] = p.Tags
+		}
+		if p.Status != "" {
+			set["status"] = p.Status
+		}
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": p.ID}).
+			SetUpdate(bson.M{"$set": set}))
+	}
+
+	result, err := database.BulkWrite(r.collection, operations)
+	if result == nil {
+		return 0, 0, err
+	}
+	return result.InsertedCount, result.MatchedCount, err
+}
+
+// StreamProducts calls fn for every product that is not deleted, in
+// creation order.
+func (r *productRepository) StreamProducts(ctx context.Context, fn func(*models.Product) error) error {
+	cursor, err := r.collection.Find(ctx, bson.M{"deletedAt": nil}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var product models.Product
+		if err := cursor.Decode(&product); err != nil {
+			return err
+		}
+		if err := fn(&product); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// AddProductImage records an uploaded image on a product and lists its large
+// rendition among the product's images.
+func (r *productRepository) AddProductImage(ctx context.Context, id string, image models.ProductImage) error {
+	return r.updateImages(ctx, id, bson.M{
+		"$push": bson.M{"uploads": image, "images": image.Large},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+}
+
+// RemoveProductImage undoes AddProductImage.
+func (r *productRepository) RemoveProductImage(ctx context.Context, id string, image models.ProductImage) error {
+	return r.updateImages(ctx, id, bson.M{
+		"$pull": bson.M{"uploads": bson.M{"id": image.ID}, "images": image.Large},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+}
+
+func (r *productRepository) updateImages(ctx context.Context, id string, update bson.M) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no product found with ID: %s", id)
+	}
+	return nil
+}
+
+// productSortFields maps the listing sortBy values onto product fields.
+var productSortFields = map[string]string{
+	"createdAt": "createdAt",
+	"price":     "price.amount",
+	"title":     "title",
+	"rating":    "ratingAverage",
+}
+
+// ApplyProductSchedules publishes the scheduled products whose publishAt has
+// passed, then archives the active ones whose unpublishAt has passed.
+func (r *productRepository) ApplyProductSchedules(ctx context.Context, now time.Time) (int64, int64, error) {
+	published, err := r.collection.UpdateMany(ctx,
+		bson.M{"status": constants.ProductStatusScheduled, "publishAt": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"status": constants.ProductStatusActive, "updatedAt": now}},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	unpublished, err := r.collection.UpdateMany(ctx,
+		bson.M{"status": constants.ProductStatusActive, "unpublishAt": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"status": constants.ProductStatusArchived, "updatedAt": now}},
+	)
+	if err != nil {
+		return published.ModifiedCount, 0, err
+	}
+	return published.ModifiedCount, unpublished.ModifiedCount, nil
+}
+
+// CountSaleBoundaries counts the live products with a sale that started or
+// ended after from and no later than to.
+func (r *productRepository) CountSaleBoundaries(ctx context.Context, from, to time.Time) (int64, error) {
+	window := bson.M{"$gt": from, "$lte": to}
+	return r.collection.CountDocuments(ctx, bson.M{
+		"status":    constants.ProductStatusActive,
+		"deletedAt": nil,
+		"$or": bson.A{
+			bson.M{"sales.startsAt": window},
+			bson.M{"sales.endsAt": window},
+		},
+	})
+}
+
+// ActivateLegacyProducts marks the products created before statuses existed,
+// all of which were live, as active.
+func (r *productRepository) ActivateLegacyProducts(ctx context.Context) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"status": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"status": constants.ProductStatusActive}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ConvertLegacyPrices rewrites the prices stored as plain numbers in major
+// units, from before prices carried a currency, as amounts in minor units of
+// currency.
+func (r *productRepository) ConvertLegacyPrices(ctx context.Context, currency string) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"price": bson.M{"$type": "number"}},
+			bson.M{"compareAtPrice": bson.M{"$type": "number"}},
+			bson.M{"sales.price": bson.M{"$type": "number"}},
+		}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"price":          legacyMoney("$price", currency),
+			"compareAtPrice": legacyMoney("$compareAtPrice", currency),
+			"sales": bson.M{"$cond": bson.A{
+				bson.M{"$isArray": "$sales"},
+				bson.M{"$map": bson.M{
+					"input": "$sales",
+					"as":    "sale",
+					"in": bson.M{"$mergeObjects": bson.A{
+						"$$sale",
+						bson.M{"price": legacyMoney("$$sale.price", currency)},
+					}},
+				}},
+				"$sales",
+			}},
+		}}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// legacyMoney is an aggregation expression converting field, when it holds a
+// plain number of major units, to a money document in minor units. $round
+// rounds half to even, as models.Money does. Other values are left as they
+// are.
+func legacyMoney(field, currency string) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$isNumber": field},
+		bson.M{
+			"amount":   bson.M{"$toLong": bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{field, 100}}, 0}}},
+			"currency": currency,
+		},
+		field,
+	}}
+}
+
+// GetAllProductsWithFilters pages through products matching the listing
+// filters: status, search, category, minPrice, maxPrice, sortBy and
+// sortOrder. An empty status matches products in any status. Deleted
+// products are never listed.
 func (r *productRepository) GetAllProductsWithFilters(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error) {
-	return []*models.Product{}, 0, nil
+	filter := bson.M{"deletedAt": nil}
+	if status, _ := filters["status"].(string); status != "" {
+		filter["status"] = status
+	}
+	if search, _ := filters["search"].(string); search != "" {
+		filter["$text"] = bson.M{"$search": search}
+	}
+	if category, _ := filters["category"].(string); category != "" {
+		filter["category"] = category
+	}
+	price := bson.M{}
+	if minPrice, _ := filters["minPrice"].(int64); minPrice > 0 {
+		price["$gte"] = minPrice
+	}
+	if maxPrice, _ := filters["maxPrice"].(int64); maxPrice > 0 {
+		price["$lte"] = maxPrice
+	}
+	if len(price) > 0 {
+		filter["price.amount"] = price
+	}
+
+	products := []*models.Product{}
+	total, err := findPage(ctx, r.collection, filter, productSort(filters), filters, &products)
+	return products, total, err
+}
+
+// GetProductsByRules pages through the active products matching a rule
+// collection's rules, sorted by the sortBy and sortOrder filters. The rule's
+// category must already be expanded into the categoryIds filter.
+func (r *productRepository) GetProductsByRules(ctx context.Context, rules *models.CollectionRules, filters map[string]interface{}) ([]*models.Product, int64, error) {
+	filter := bson.M{"status": constants.ProductStatusActive, "deletedAt": nil}
+	price := bson.M{}
+	if rules.MinPrice != nil {
+		price["$gte"] = rules.MinPrice.Amount
+	}
+	if rules.MaxPrice != nil {
+		price["$lte"] = rules.MaxPrice.Amount
+	}
+	if len(price) > 0 {
+		filter["price.amount"] = price
+	}
+	if len(rules.Tags) > 0 {
+		filter["tags"] = bson.M{"$in": rules.Tags}
+	}
+	if categoryIDs, ok := filters["categoryIds"].([]primitive.ObjectID); ok {
+		filter["categoryId"] = bson.M{"$in": categoryIDs}
+	}
+	if rules.InStockOnly {
+		filter["inStock"] = bson.M{"$gt": 0}
+	}
+	createdAt := bson.M{}
+	if rules.CreatedAfter != nil {
+		createdAt["$gte"] = *rules.CreatedAfter
+	}
+	if rules.NewWithinDays > 0 {
+		since := time.Now().AddDate(0, 0, -rules.NewWithinDays)
+		if rules.CreatedAfter == nil || since.After(*rules.CreatedAfter) {
+			createdAt["$gte"] = since
+		}
+	}
+	if len(createdAt) > 0 {
+		filter["createdAt"] = createdAt
+	}
+
+	products := []*models.Product{}
+	total, err := findPage(ctx, r.collection, filter, productSort(filters), filters, &products)
+	return products, total, err
+}
+
+// productSort builds the listing sort from the sortBy and sortOrder filters,
+// newest first by default.
+func productSort(filters map[string]interface{}) bson.D {
+	field, ok := productSortFields[fmt.Sprint(filters["sortBy"])]
+	if !ok {
+		field = "createdAt"
+	}
+	direction := -1
+	if filters["sortOrder"] == "asc" {
+		direction = 1
+	}
+	sort := bson.D{{Key: field, Value: direction}}
+	if field == "ratingAverage" {
+		// Among equal ratings, the better reviewed product ranks first.
+		sort = append(sort, bson.E{Key: "ratingCount", Value: direction})
+	}
+	return append(sort, bson.E{Key: "_id", Value: direction})
 }
 
 func (r *productRepository) SearchProducts(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error) {
 	return []*models.Product{}, 0, nil
 }
 
+// GetProductsByCategory pages through the active products in any of the
+// categoryIds, newest first.
 func (r *productRepository) GetProductsByCategory(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error) {
-	return []*models.Product{}, 0, nil
+	categoryIDs, _ := filters["categoryIds"].([]primitive.ObjectID)
+	filter := bson.M{
+		"categoryId": bson.M{"$in": categoryIDs},
+		"status":     constants.ProductStatusActive,
+		"deletedAt":  nil,
+	}
+	sort := bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+
+	products := []*models.Product{}
+	total, err := findPage(ctx, r.collection, filter, sort, filters, &products)
+	return products, total, err
 }
 
+// GetFeaturedProducts returns the products on the featured list that are live
+// right now, in their curated order.
 func (r *productRepository) GetFeaturedProducts(ctx context.Context, limit int) ([]*models.Product, error) {
-	return []*models.Product{}, nil
+	now := time.Now()
+	return r.lookupProducts(ctx, r.collection.Database().Collection("featured_products"), mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"startsAt": bson.M{"$lte": now},
+			"$or": bson.A{
+				bson.M{"endsAt": bson.M{"$exists": false}},
+				bson.M{"endsAt": nil},
+				bson.M{"endsAt": bson.M{"$gt": now}},
+			},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "position", Value: 1}, {Key: "startsAt", Value: 1}}}},
+		// The same product may be scheduled more than once; keep its first slot.
+		{{Key: "$group", Value: bson.M{"_id": "$productId", "position": bson.M{"$first": "$position"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "position", Value: 1}}}},
+		{{Key: "$project", Value: bson.M{"productId": "$_id"}}},
+	}, limit)
 }
 
-func (r *productRepository) GetProductCategories(ctx context.Context) ([]string, error) {
-	return []string{}, nil
+// GetUnlinkedCategoryNames returns the distinct free-text categories of
+// products that are not yet linked to the categories collection.
+func (r *productRepository) GetUnlinkedCategoryNames(ctx context.Context) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "category", bson.M{"categoryId": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		if name, ok := value.(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// LinkCategory points every unlinked product filed under name at category.
+func (r *productRepository) LinkCategory(ctx context.Context, name string, category *models.Category) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"category": name, "categoryId": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"categoryId": category.ID, "category": category.Name}},
+	)
+	return err
+}
+
+// RenameCategory copies a category's new name onto its products.
+func (r *productRepository) RenameCategory(ctx context.Context, category *models.Category) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"categoryId": category.ID, "category": bson.M{"$ne": category.Name}},
+		bson.M{"$set": bson.M{"category": category.Name}},
+	)
+	return err
+}
+
+func (r *productRepository) CountProductsInCategory(ctx context.Context, categoryID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"categoryId": categoryID})
 }
 
 func (r *productRepository) GetProductsByPriceRange(ctx context.Context, filters map[string]interface{}) ([]*models.Product, int64, error) {
@@ -132,10 +681,122 @@ func (r *productRepository) GetNewArrivals(ctx context.Context, limit int) ([]*m
 	return []*models.Product{}, nil
 }
 
+// GetBestSellers returns products in the order of the last materialized
+// best seller ranking.
 func (r *productRepository) GetBestSellers(ctx context.Context, limit int) ([]*models.Product, error) {
-	return []*models.Product{}, nil
+	return r.lookupProducts(ctx, r.collection.Database().Collection("best_sellers"), mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "rank", Value: 1}}}},
+		{{Key: "$project", Value: bson.M{"productId": "$_id"}}},
+	}, limit)
+}
+
+// lookupProducts runs pipeline over an ordered collection whose documents
+// carry a productId and returns the matching active products in that order,
+// skipping any that no longer exist or are not live.
+func (r *productRepository) lookupProducts(ctx context.Context, coll *mongo.Collection, pipeline mongo.Pipeline, limit int) ([]*models.Product, error) {
+	pipeline = append(pipeline,
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         r.collection.Name(),
+			"localField":   "productId",
+			"foreignField": "_id",
+			"as":           "product",
+		}}},
+		bson.D{{Key: "$unwind", Value: "$product"}},
+		bson.D{{Key: "$match", Value: bson.M{
+			"product.status":    constants.ProductStatusActive,
+			"product.deletedAt": nil,
+		}}},
+		bson.D{{Key: "$limit", Value: limit}},
+		bson.D{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$product"}}},
+	)
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	products := []*models.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
 }
 
+// relatedPriceBand is how far either side of a product's price another
+// product may be priced and still count as a similar price.
+const relatedPriceBand = 0.3
+
+// GetRelatedProducts scores other active products by a shared category,
+// shared tags and a similar price, and returns the best matches. Products
+// sharing none of these are left out.
 func (r *productRepository) GetRelatedProducts(ctx context.Context, productID string, limit int) ([]*models.Product, error) {
-	return []*models.Product{}, nil
+	product, err := r.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := product.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	minPrice := product.Price.MulRate(1 - relatedPriceBand).Amount
+	maxPrice := product.Price.MulRate(1 + relatedPriceBand).Amount
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"_id":       bson.M{"$ne": product.ID},
+			"status":    constants.ProductStatusActive,
+			"deletedAt": nil,
+			"$or": bson.A{
+				bson.M{"category": product.Category},
+				bson.M{"tags": bson.M{"$in": tags}},
+				bson.M{"price.amount": bson.M{"$gte": minPrice, "$lte": maxPrice}},
+			},
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"relatedScore": bson.M{"$add": bson.A{
+				bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$category", product.Category}}, 3, 0}},
+				bson.M{"$size": bson.M{"$setIntersection": bson.A{bson.M{"$ifNull": bson.A{"$tags", bson.A{}}}, tags}}},
+				bson.M{"$cond": bson.A{bson.M{"$and": bson.A{
+					bson.M{"$gte": bson.A{"$price.amount", minPrice}},
+					bson.M{"$lte": bson.A{"$price.amount", maxPrice}},
+				}}, 1, 0}},
+			}},
+			"priceDistance": bson.M{"$abs": bson.M{"$subtract": bson.A{"$price.amount", product.Price.Amount}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "relatedScore", Value: -1},
+			{Key: "priceDistance", Value: 1},
+			{Key: "_id", Value: 1},
+		}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.M{"relatedScore": 0, "priceDistance": 0}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	products := []*models.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// GetBoughtTogether returns the products most often bought with productID,
+// from the last materialized co-purchase lists.
+func (r *productRepository) GetBoughtTogether(ctx context.Context, productID string, limit int) ([]*models.Product, error) {
+	objectID, err := primitive.ObjectIDFromHex(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.lookupProducts(ctx, r.collection.Database().Collection("bought_together"), mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": objectID}}},
+		{{Key: "$unwind", Value: "$products"}},
+		{{Key: "$project", Value: bson.M{"productId": "$products.productId", "orders": "$products.orders"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "orders", Value: -1}}}},
+	}, limit)
 }
@@ -0,0 +1,168 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type questionRepository struct {
+	questions *mongo.Collection
+	answers   *mongo.Collection
+	votes     *mongo.Collection
+}
+
+func NewQuestionRepository(db *mongo.Database) interfaces.QuestionRepository {
+	return &questionRepository{
+		questions: db.Collection("questions"),
+		answers:   db.Collection("answers"),
+		votes:     db.Collection("answer_votes"),
+	}
+}
+
+func (r *questionRepository) CreateQuestion(ctx context.Context, question *models.Question) error {
+	question.ID = primitive.NewObjectID()
+	_, err := r.questions.InsertOne(ctx, question)
+	return err
+}
+
+func (r *questionRepository) GetQuestionByID(ctx context.Context, id string) (*models.Question, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var question models.Question
+	if err := r.questions.FindOne(ctx, bson.M{"_id": objectID}).Decode(&question); err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// ListQuestions pages through a product's questions. sortBy "answered" puts
+// the most answered first; answeredOnly hides unanswered questions.
+func (r *questionRepository) ListQuestions(ctx context.Context, filters map[string]interface{}) ([]*models.Question, int64, error) {
+	productID, err := primitive.ObjectIDFromHex(fmt.Sprint(filters["productId"]))
+	if err != nil {
+		return nil, 0, err
+	}
+	filter := bson.M{"productId": productID}
+	if answeredOnly, _ := filters["answeredOnly"].(bool); answeredOnly {
+		filter["answerCount"] = bson.M{"$gt": 0}
+	}
+
+	sort := bson.D{{Key: "createdAt", Value: -1}}
+	if filters["sortBy"] == "answered" {
+		sort = bson.D{{Key: "answerCount", Value: -1}, {Key: "createdAt", Value: -1}}
+	}
+
+	questions := []*models.Question{}
+	total, err := findPage(ctx, r.questions, filter, sort, filters, &questions)
+	return questions, total, err
+}
+
+func (r *questionRepository) IncrementAnswerCount(ctx context.Context, questionID string, delta int) error {
+	objectID, err := primitive.ObjectIDFromHex(questionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.questions.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{
+		"$inc": bson.M{"answerCount": delta},
+		"$set": bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+func (r *questionRepository) CreateAnswer(ctx context.Context, answer *models.Answer) error {
+	answer.ID = primitive.NewObjectID()
+	_, err := r.answers.InsertOne(ctx, answer)
+	return err
+}
+
+func (r *questionRepository) GetAnswerByID(ctx context.Context, id string) (*models.Answer, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var answer models.Answer
+	if err := r.answers.FindOne(ctx, bson.M{"_id": objectID}).Decode(&answer); err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+// ListAnswers pages through answers filtered by questionId and status, most
+// upvoted first, or oldest first for the moderation queue.
+func (r *questionRepository) ListAnswers(ctx context.Context, filters map[string]interface{}) ([]*models.Answer, int64, error) {
+	filter := bson.M{}
+	if questionID, _ := filters["questionId"].(string); questionID != "" {
+		objectID, err := primitive.ObjectIDFromHex(questionID)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter["questionId"] = objectID
+	}
+	if status, _ := filters["status"].(string); status != "" {
+		filter["status"] = status
+	}
+
+	sort := bson.D{{Key: "upvotes", Value: -1}, {Key: "createdAt", Value: 1}}
+	if filters["sortBy"] == "oldest" {
+		sort = bson.D{{Key: "createdAt", Value: 1}}
+	}
+
+	answers := []*models.Answer{}
+	total, err := findPage(ctx, r.answers, filter, sort, filters, &answers)
+	return answers, total, err
+}
+
+func (r *questionRepository) UpdateAnswerStatus(ctx context.Context, answer *models.Answer) error {
+	result, err := r.answers.UpdateOne(ctx, bson.M{"_id": answer.ID}, bson.M{
+		"$set": bson.M{
+			"status":      answer.Status,
+			"moderatedBy": answer.ModeratedBy,
+			"moderatedAt": answer.ModeratedAt,
+			"updatedAt":   answer.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no answer found with ID: %s", answer.ID.Hex())
+	}
+	return nil
+}
+
+// AddAnswerVote records one upvote per user per answer, as AddHelpfulVote
+// does for reviews.
+func (r *questionRepository) AddAnswerVote(ctx context.Context, answerID, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(answerID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.votes.InsertOne(ctx, bson.M{
+		"answerId":  objectID,
+		"userId":    userID,
+		"createdAt": time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrAlreadyVoted
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = r.answers.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$inc": bson.M{"upvotes": 1}})
+	return err
+}
@@ -0,0 +1,154 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type reviewRepository struct {
+	collection *mongo.Collection
+	votes      *mongo.Collection
+}
+
+func NewReviewRepository(db *mongo.Database) interfaces.ReviewRepository {
+	return &reviewRepository{
+		collection: db.Collection("reviews"),
+		votes:      db.Collection("review_votes"),
+	}
+}
+
+func (r *reviewRepository) CreateReview(ctx context.Context, review *models.Review) error {
+	review.ID = primitive.NewObjectID()
+	_, err := r.collection.InsertOne(ctx, review)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrDuplicateReview
+	}
+	return err
+}
+
+func (r *reviewRepository) GetReviewByID(ctx context.Context, id string) (*models.Review, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var review models.Review
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ListReviews pages through reviews filtered by productId and status.
+// sortBy is one of recent, helpful, highest or lowest.
+func (r *reviewRepository) ListReviews(ctx context.Context, filters map[string]interface{}) ([]*models.Review, int64, error) {
+	filter := bson.M{}
+	if productID, _ := filters["productId"].(string); productID != "" {
+		objectID, err := primitive.ObjectIDFromHex(productID)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter["productId"] = objectID
+	}
+	if status, _ := filters["status"].(string); status != "" {
+		filter["status"] = status
+	}
+
+	sort := bson.D{{Key: "createdAt", Value: -1}}
+	switch filters["sortBy"] {
+	case "helpful":
+		sort = bson.D{{Key: "helpfulCount", Value: -1}, {Key: "createdAt", Value: -1}}
+	case "highest":
+		sort = bson.D{{Key: "rating", Value: -1}, {Key: "createdAt", Value: -1}}
+	case "lowest":
+		sort = bson.D{{Key: "rating", Value: 1}, {Key: "createdAt", Value: -1}}
+	}
+
+	reviews := []*models.Review{}
+	total, err := findPage(ctx, r.collection, filter, sort, filters, &reviews)
+	return reviews, total, err
+}
+
+func (r *reviewRepository) UpdateReviewStatus(ctx context.Context, review *models.Review) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": review.ID}, bson.M{
+		"$set": bson.M{
+			"status":         review.Status,
+			"moderatedBy":    review.ModeratedBy,
+			"moderationNote": review.ModerationNote,
+			"moderatedAt":    review.ModeratedAt,
+			"updatedAt":      review.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no review found with ID: %s", review.ID.Hex())
+	}
+	return nil
+}
+
+// AddHelpfulVote records one helpful vote per user per review. The vote is
+// stored before the counter is bumped so a repeat vote is caught by the
+// unique index.
+func (r *reviewRepository) AddHelpfulVote(ctx context.Context, reviewID, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(reviewID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.votes.InsertOne(ctx, bson.M{
+		"reviewId":  objectID,
+		"userId":    userID,
+		"createdAt": time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrAlreadyVoted
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$inc": bson.M{"helpfulCount": 1}})
+	return err
+}
+
+// GetRatingSummary averages the approved reviews of a product.
+func (r *reviewRepository) GetRatingSummary(ctx context.Context, productID string) (models.RatingSummary, error) {
+	objectID, err := primitive.ObjectIDFromHex(productID)
+	if err != nil {
+		return models.RatingSummary{}, err
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"productId": objectID, "status": constants.ReviewStatusApproved}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"average": bson.M{"$avg": "$rating"},
+			"count":   bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return models.RatingSummary{}, err
+	}
+	defer cursor.Close(ctx)
+
+	var summary models.RatingSummary
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&summary); err != nil {
+			return models.RatingSummary{}, err
+		}
+	}
+	summary.Average = math.Round(summary.Average*10) / 10
+	return summary, cursor.Err()
+}
@@ -2,11 +2,13 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/Shrey-Yash/Masked11/internal/models"
 	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
@@ -57,7 +59,64 @@ func (r *userRepository) UpdateUser(id primitive.ObjectID, updated bson.M) error
 	return err
 }
 
+func (r *userRepository) VerifyPhone(id primitive.ObjectID, phone string) (bool, error) {
+	// Matching on the number means a change made since the admin checked it
+	// is not marked verified.
+	result, err := r.collection.UpdateOne(context.TODO(), bson.M{"_id": id, "phone": phone}, bson.M{
+		"$set": bson.M{"phoneVerified": true, "updatedAt": time.Now()},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
 func (r *userRepository) DeleteUser(id primitive.ObjectID) error {
 	_, err := r.collection.DeleteOne(context.TODO(), bson.M{"_id": id})
 	return err
-}
\ No newline at end of file
+}
+
+func (r *userRepository) AddAddress(id primitive.ObjectID, address models.Address) error {
+	_, err := r.collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{
+		"$push": bson.M{"addresses": address},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+func (r *userRepository) RemoveAddress(id primitive.ObjectID, addressID string) error {
+	result, err := r.collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{
+		"$pull": bson.M{"addresses": bson.M{"id": addressID}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("address not found")
+	}
+	return nil
+}
+
+// SetDefaultAddress makes one saved address the default and clears the flag
+// on the others in a single update.
+func (r *userRepository) SetDefaultAddress(id primitive.ObjectID, addressID string) error {
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"other.id": bson.M{"$ne": addressID}},
+		bson.M{"chosen.id": addressID},
+	}})
+	result, err := r.collection.UpdateOne(context.TODO(), bson.M{"_id": id, "addresses.id": addressID}, bson.M{
+		"$set": bson.M{
+			"addresses.$[other].default":  false,
+			"addresses.$[chosen].default": true,
+			"updatedAt":                   time.Now(),
+		},
+	}, opts)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("address not found")
+	}
+	return nil
+}
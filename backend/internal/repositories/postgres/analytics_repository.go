@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+// unplacedStatuses are order statuses that do not count as sales.
+var unplacedStatuses = []string{
+	constants.OrderStatusPending,
+	constants.OrderStatusCancelled,
+	constants.OrderStatusFailed,
+}
+
+type analyticsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAnalyticsRepository(db *pgxpool.Pool) interfaces.AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// RefreshSalesRollups rebuilds the daily rollups from since onwards. Days are
+// cut at midnight in timezone. Revenue and refunds are summed in minor units
+// of the store's currency.
+func (r *analyticsRepository) RefreshSalesRollups(ctx context.Context, since time.Time, timezone string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	day := since.Format("2006-01-02")
+	if _, err := tx.Exec(ctx, `DELETE FROM sales_daily WHERE day >= $1::date`, day); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM product_sales_daily WHERE day >= $1::date`, day); err != nil {
+		return err
+	}
+
+	// Each customer's first placed order ever marks them as new, so the
+	// ranking runs over all orders before the date filter is applied.
+	_, err = tx.Exec(ctx, `WITH placed AS (
+			SELECT total, created_at, (created_at AT TIME ZONE $2)::date AS day,
+				ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at, id) AS nth
+			FROM orders WHERE status <> ALL($3)
+		)
+		INSERT INTO sales_daily (day, orders, revenue, new_customer_orders, returning_customer_orders, refreshed_at)
+		SELECT day, COUNT(*), SUM(total), COUNT(*) FILTER (WHERE nth = 1), COUNT(*) FILTER (WHERE nth > 1), NOW()
+		FROM placed WHERE created_at >= ($1::date AT TIME ZONE $2)
+		GROUP BY day`, day, timezone, unplacedStatuses)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO sales_daily (day, refunds, refunded_orders, refreshed_at)
+		SELECT (created_at AT TIME ZONE $2)::date, SUM(amount), COUNT(DISTINCT order_id), NOW()
+		FROM refunds WHERE created_at >= ($1::date AT TIME ZONE $2)
+		GROUP BY 1
+		ON CONFLICT (day) DO UPDATE SET refunds = EXCLUDED.refunds, refunded_orders = EXCLUDED.refunded_orders`, day, timezone)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO product_sales_daily (day, product_id, name, category, quantity, revenue)
+		SELECT (o.created_at AT TIME ZONE $2)::date, i.product_id, MAX(i.name), COALESCE(MAX(i.category), 'Uncategorized'),
+			SUM(i.quantity), SUM(i.subtotal)
+		FROM order_items i JOIN orders o ON o.id = i.order_id
+		WHERE o.created_at >= ($1::date AT TIME ZONE $2) AND o.status <> ALL($3) AND i.status = $4
+		GROUP BY 1, i.product_id`, day, timezone, unplacedStatuses, constants.OrderItemStatusActive)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *analyticsRepository) NeedsSalesBackfill(ctx context.Context, timezone string) (bool, error) {
+	var needed bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (
+			SELECT 1 FROM orders
+			WHERE status <> ALL($2)
+				AND (created_at AT TIME ZONE $1)::date < COALESCE((SELECT MIN(day) FROM sales_daily), 'infinity'::date)
+		)`, timezone, unplacedStatuses).Scan(&needed)
+	return needed, err
+}
+
+func (r *analyticsRepository) GetSalesSummary(ctx context.Context, from, to time.Time) (models.SalesSummary, error) {
+	var s models.SalesSummary
+	err := r.db.QueryRow(ctx, `SELECT COALESCE(SUM(orders), 0), COALESCE(SUM(revenue), 0)::bigint, COALESCE(SUM(new_customer_orders), 0),
+		COALESCE(SUM(returning_customer_orders), 0), COALESCE(SUM(refunds), 0)::bigint, COALESCE(SUM(refunded_orders), 0)
+		FROM sales_daily WHERE day BETWEEN $1::date AND $2::date`, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Scan(&s.Orders, &s.Revenue.Amount, &s.NewCustomers, &s.ReturningCustomerOrders, &s.Refunds.Amount, &s.RefundedOrders)
+	s.Revenue.Currency, s.Refunds.Currency = models.DefaultCurrency, models.DefaultCurrency
+	return s, err
+}
+
+// GetSalesSeries buckets sales by day, week or month. interval must be one of
+// those three; callers validate it.
+func (r *analyticsRepository) GetSalesSeries(ctx context.Context, from, to time.Time, interval string) ([]models.SalesPoint, error) {
+	rows, err := r.db.Query(ctx, `SELECT date_trunc($3, day)::date AS period, SUM(orders), SUM(revenue)::bigint
+		FROM sales_daily WHERE day BETWEEN $1::date AND $2::date
+		GROUP BY period ORDER BY period`, from.Format("2006-01-02"), to.Format("2006-01-02"), interval)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	series := []models.SalesPoint{}
+	for rows.Next() {
+		var p models.SalesPoint
+		if err := rows.Scan(&p.Period, &p.Orders, &p.Revenue.Amount); err != nil {
+			return nil, err
+		}
+		p.Revenue.Currency = models.DefaultCurrency
+		series = append(series, p)
+	}
+	return series, rows.Err()
+}
+
+func (r *analyticsRepository) GetTopProducts(ctx context.Context, from, to time.Time, limit int) ([]models.TopProduct, error) {
+	rows, err := r.db.Query(ctx, `SELECT product_id, MAX(name), MAX(category), SUM(quantity), SUM(revenue)::bigint
+		FROM product_sales_daily WHERE day BETWEEN $1::date AND $2::date
+		GROUP BY product_id ORDER BY SUM(revenue) DESC, product_id LIMIT $3`,
+		from.Format("2006-01-02"), to.Format("2006-01-02"), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []models.TopProduct{}
+	for rows.Next() {
+		var p models.TopProduct
+		if err := rows.Scan(&p.ProductID, &p.Name, &p.Category, &p.Quantity, &p.Revenue.Amount); err != nil {
+			return nil, err
+		}
+		p.Revenue.Currency = models.DefaultCurrency
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
+func (r *analyticsRepository) GetTopCategories(ctx context.Context, from, to time.Time, limit int) ([]models.TopCategory, error) {
+	rows, err := r.db.Query(ctx, `SELECT category, SUM(quantity), SUM(revenue)::bigint
+		FROM product_sales_daily WHERE day BETWEEN $1::date AND $2::date
+		GROUP BY category ORDER BY SUM(revenue) DESC, category LIMIT $3`,
+		from.Format("2006-01-02"), to.Format("2006-01-02"), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.TopCategory{}
+	for rows.Next() {
+		var c models.TopCategory
+		if err := rows.Scan(&c.Category, &c.Quantity, &c.Revenue.Amount); err != nil {
+			return nil, err
+		}
+		c.Revenue.Currency = models.DefaultCurrency
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+// GetStatusCounts counts orders placed in the range by their current status.
+// It reads orders directly since statuses keep changing after the day closes.
+func (r *analyticsRepository) GetStatusCounts(ctx context.Context, from, to time.Time, timezone string) ([]models.StatusCount, error) {
+	rows, err := r.db.Query(ctx, `SELECT status, COUNT(*) FROM orders
+		WHERE created_at >= ($1::date AT TIME ZONE $3) AND created_at < (($2::date + 1) AT TIME ZONE $3)
+		GROUP BY status`, from.Format("2006-01-02"), to.Format("2006-01-02"), timezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []models.StatusCount{}
+	for rows.Next() {
+		var c models.StatusCount
+		if err := rows.Scan(&c.Status, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// GetSalesVelocity ranks products by units sold on placed orders since the
+// given time. It reads order_items directly so the ranking is not held back
+// by the rollup refresh.
+func (r *analyticsRepository) GetSalesVelocity(ctx context.Context, since time.Time, limit int) ([]models.ProductVelocity, error) {
+	days := time.Since(since).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT i.product_id, SUM(i.quantity), SUM(i.subtotal)::bigint
+		FROM order_items i JOIN orders o ON o.id = i.order_id
+		WHERE o.created_at >= $1 AND o.status <> ALL($2) AND i.status = $3
+		GROUP BY i.product_id
+		ORDER BY SUM(i.quantity) DESC, SUM(i.subtotal) DESC, i.product_id
+		LIMIT $4`, since, unplacedStatuses, constants.OrderItemStatusActive, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	velocities := []models.ProductVelocity{}
+	for rows.Next() {
+		var v models.ProductVelocity
+		if err := rows.Scan(&v.ProductID, &v.Quantity, &v.Revenue.Amount); err != nil {
+			return nil, err
+		}
+		v.Revenue.Currency = models.DefaultCurrency
+		v.PerDay = float64(v.Quantity) / days
+		velocities = append(velocities, v)
+	}
+	return velocities, rows.Err()
+}
+
+// GetCoPurchases mines pairs of distinct products bought in the same placed
+// order since the given time, keeping the perProduct strongest partners of
+// each product.
+func (r *analyticsRepository) GetCoPurchases(ctx context.Context, since time.Time, perProduct int) ([]models.CoPurchase, error) {
+	rows, err := r.db.Query(ctx, `WITH items AS (
+			SELECT DISTINCT i.order_id, i.product_id
+			FROM order_items i JOIN orders o ON o.id = i.order_id
+			WHERE o.created_at >= $1 AND o.status <> ALL($2) AND i.status = $3
+		), pairs AS (
+			SELECT a.product_id, b.product_id AS other_id, COUNT(*) AS orders
+			FROM items a JOIN items b ON a.order_id = b.order_id AND a.product_id <> b.product_id
+			GROUP BY a.product_id, b.product_id
+		), ranked AS (
+			SELECT product_id, other_id, orders,
+				ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY orders DESC, other_id) AS nth
+			FROM pairs
+		)
+		SELECT product_id, other_id, orders FROM ranked
+		WHERE nth <= $4
+		ORDER BY product_id, nth`, since, unplacedStatuses, constants.OrderItemStatusActive, perProduct)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pairs := []models.CoPurchase{}
+	for rows.Next() {
+		var p models.CoPurchase
+		if err := rows.Scan(&p.ProductID, &p.OtherID, &p.Orders); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, p)
+	}
+	return pairs, rows.Err()
+}
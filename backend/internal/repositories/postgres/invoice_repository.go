@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type invoiceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewInvoiceRepository(db *pgxpool.Pool) interfaces.InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+// GetInvoiceByOrderID returns the stored invoice for an order, or nil if none
+// has been issued yet.
+func (r *invoiceRepository) GetInvoiceByOrderID(ctx context.Context, orderID string) (*models.Invoice, error) {
+	var inv models.Invoice
+	var currency string
+	err := r.db.QueryRow(ctx, `SELECT id, order_id, invoice_number, financial_year, sequence, subtotal, discount, tax, total, currency, pdf, issued_at
+		FROM invoices WHERE order_id = $1`, orderID).
+		Scan(&inv.ID, &inv.OrderID, &inv.Number, &inv.FinancialYear, &inv.Sequence, &inv.Subtotal.Amount, &inv.Discount.Amount, &inv.Tax.Amount, &inv.Total.Amount, &currency, &inv.PDF, &inv.IssuedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	inv.Subtotal.Currency, inv.Discount.Currency = currency, currency
+	inv.Tax.Currency, inv.Total.Currency = currency, currency
+	return &inv, nil
+}
+
+// CreateInvoice assigns the next number of the invoice's financial year
+// (PREFIX-FY-NNNNNN), renders the document and stores both in one
+// transaction. The counter row stays locked until commit and a failure rolls
+// the increment back, so numbers are gap-free. If another request issued the
+// order's invoice first, that invoice is returned instead.
+func (r *invoiceRepository) CreateInvoice(ctx context.Context, invoice *models.Invoice, prefix string, render interfaces.InvoiceRenderer) (*models.Invoice, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `INSERT INTO invoice_counters (financial_year, last_number) VALUES ($1, 1)
+		ON CONFLICT (financial_year) DO UPDATE SET last_number = invoice_counters.last_number + 1
+		RETURNING last_number`, invoice.FinancialYear).Scan(&invoice.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	invoice.Number = fmt.Sprintf("%s-%s-%06d", prefix, invoice.FinancialYear, invoice.Sequence)
+
+	pdf, err := render(invoice)
+	if err != nil {
+		return nil, err
+	}
+	invoice.PDF = pdf
+
+	cmd, err := tx.Exec(ctx, `INSERT INTO invoices (id, order_id, invoice_number, financial_year, sequence, subtotal, discount, tax, total, currency, pdf, issued_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (order_id) DO NOTHING`,
+		invoice.ID, invoice.OrderID, invoice.Number, invoice.FinancialYear, invoice.Sequence,
+		invoice.Subtotal.Amount, invoice.Discount.Amount, invoice.Tax.Amount, invoice.Total.Amount, invoice.Total.Currency, invoice.PDF, invoice.IssuedAt)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.RowsAffected() == 0 {
+		tx.Rollback(ctx)
+		return r.GetInvoiceByOrderID(ctx, invoice.OrderID.String())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type jobRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewJobRepository(db *pgxpool.Pool) interfaces.JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) CreateJob(ctx context.Context, job *models.Job) error {
+	now := time.Now()
+	job.CreatedAt, job.UpdatedAt = now, now
+	_, err := r.db.Exec(ctx, `INSERT INTO jobs (id, type, status, params, progress, total, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		job.ID, job.Type, job.Status, job.Params, job.Progress, job.Total, job.CreatedBy, job.CreatedAt, job.UpdatedAt)
+	return err
+}
+
+func (r *jobRepository) GetJobByID(ctx context.Context, id string) (*models.Job, error) {
+	var job models.Job
+	err := r.db.QueryRow(ctx, `SELECT id, type, status, params, progress, total, result, COALESCE(file_path, ''), COALESCE(error, ''),
+		created_by, created_at, updated_at, completed_at FROM jobs WHERE id = $1`, id).
+		Scan(&job.ID, &job.Type, &job.Status, &job.Params, &job.Progress, &job.Total, &job.Result, &job.FilePath, &job.Error,
+			&job.CreatedBy, &job.CreatedAt, &job.UpdatedAt, &job.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UpdateJob saves a job's status, progress and outcome.
+func (r *jobRepository) UpdateJob(ctx context.Context, job *models.Job) error {
+	job.UpdatedAt = time.Now()
+	cmd, err := r.db.Exec(ctx, `UPDATE jobs SET status = $1, progress = $2, total = $3, result = $4, file_path = NULLIF($5, ''),
+		error = NULLIF($6, ''), updated_at = $7, completed_at = $8 WHERE id = $9`,
+		job.Status, job.Progress, job.Total, job.Result, job.FilePath, job.Error, job.UpdatedAt, job.CompletedAt, job.ID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return errors.New("no rows updated")
+	}
+	return nil
+}
@@ -3,9 +3,12 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/Shrey-Yash/Masked11/internal/models"
@@ -13,6 +16,10 @@ import (
 	"github.com/Shrey-Yash/Masked11/internal/constants"
 )
 
+const orderColumns = `id, order_number, user_id, COALESCE(customer_email, ''), total, status, payment_method, cod_fee, delivered_at, created_at, updated_at, currency`
+
+const orderItemColumns = `id, order_id, product_id, name, COALESCE(category, ''), price, quantity, COALESCE(image, ''), COALESCE(size, ''), subtotal, status, cancelled_at, currency`
+
 type orderRepository struct {
 	db *pgxpool.Pool
 }
@@ -29,15 +36,38 @@ func (r *orderRepository) CreateOrder(order *models.Order, items []models.OrderI
 	}
 	defer tx.Rollback(ctx)
 
-	query := `INSERT INTO orders (id, user_id, total, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err = tx.Exec(ctx, query, order.ID, order.UserID, order.Total, order.Status, time.Now(), time.Now())
+	// Order numbers come from a sequence so support staff have something
+	// short to read out; the UUID stays the primary key.
+	var seq int64
+	if err := tx.QueryRow(ctx, `SELECT nextval('order_number_seq')`).Scan(&seq); err != nil {
+		return err
+	}
+	order.OrderNumber = fmt.Sprintf("M11-%d-%06d", order.CreatedAt.Year(), seq)
+
+	query := `INSERT INTO orders (id, order_number, user_id, customer_email, total, status, payment_method, cod_fee, currency, created_at, updated_at) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, $11)`
+	_, err = tx.Exec(ctx, query, order.ID, order.OrderNumber, order.UserID, order.CustomerEmail, order.Total.Amount, order.Status, order.PaymentMethod, order.CODFee.Amount, order.Total.Currency, time.Now(), time.Now())
 	if err != nil {
 		return err
 	}
 
-	itemQuery := `INSERT INTO order_items (id, order_id, product_id, name, price, quantity, image, size, subtotal) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	itemQuery := `INSERT INTO order_items (id, order_id, product_id, name, category, price, quantity, image, size, subtotal, status, currency) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12)`
 	for _, item := range items {
-		_, err := tx.Exec(ctx, itemQuery, item.ID, order.ID, item.ProductID, item.Name, item.Price, item.Quantity, item.Image, item.Size, item.Subtotal)
+		_, err := tx.Exec(ctx, itemQuery, item.ID, order.ID, item.ProductID, item.Name, item.Category, item.Price.Amount, item.Quantity, item.Image, item.Size, item.Subtotal.Amount, constants.OrderItemStatusActive, item.Price.Currency)
+		if err != nil {
+			return err
+		}
+	}
+
+	addressQuery := `INSERT INTO order_addresses (order_id, type, name, phone, line1, line2, city, state, pincode, country) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	addresses := map[string]*models.Address{
+		constants.AddressTypeShipping: order.ShippingAddress,
+		constants.AddressTypeBilling:  order.BillingAddress,
+	}
+	for addressType, a := range addresses {
+		if a == nil {
+			continue
+		}
+		_, err := tx.Exec(ctx, addressQuery, order.ID, addressType, a.Name, a.Phone, a.Line1, a.Line2, a.City, a.State, a.Pincode, a.Country)
 		if err != nil {
 			return err
 		}
@@ -48,7 +78,7 @@ func (r *orderRepository) CreateOrder(order *models.Order, items []models.OrderI
 func (r *orderRepository) GetOrdersByUserID(ctx context.Context, userID string) ([]models.Order, error) {
 	orders := []models.Order{}
 
-	rows, err := r.db.Query(ctx, `SELECT id, user_id, total, status, created_at, updated_at FROM orders WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+	rows, err := r.db.Query(ctx, `SELECT `+orderColumns+` FROM orders WHERE user_id = $1 ORDER BY created_at DESC`, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -56,15 +86,13 @@ func (r *orderRepository) GetOrdersByUserID(ctx context.Context, userID string)
 
 	for rows.Next() {
 		var order models.Order
-		if err := rows.Scan(&order.ID, &order.UserID, &order.Total, &order.Status, &order.CreatedAt, &order.UpdatedAt); err != nil {
+		if err := scanOrder(rows, &order); err != nil {
 			return nil, err
 		}
 		
-		items, err := r.getOrderItems(ctx, order.ID.String())
-		if err != nil {
+		if err := r.loadOrderDetails(ctx, &order); err != nil {
 			return nil, err
 		}
-		order.Items = items
 		orders = append(orders, order)
 	}
 
@@ -73,22 +101,219 @@ func (r *orderRepository) GetOrdersByUserID(ctx context.Context, userID string)
 
 func (r *orderRepository) GetOrderByID(ctx context.Context, orderID string) (*models.Order, error) {
 	var order models.Order
-	err := r.db.QueryRow(ctx, `SELECT id, user_id, total, status, created_at, updated_at FROM orders WHERE id = $1`, orderID).Scan(
-		&order.ID, &order.UserID, &order.Total, &order.Status, &order.CreatedAt, &order.UpdatedAt,
-	)
+	err := scanOrder(r.db.QueryRow(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = $1`, orderID), &order)
 	if err != nil {
 		return nil, err
 	}
 
-	items, err := r.getOrderItems(ctx, orderID)
+	if err := r.loadOrderDetails(ctx, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (r *orderRepository) GetOrderByNumber(ctx context.Context, orderNumber string) (*models.Order, error) {
+	var order models.Order
+	err := scanOrder(r.db.QueryRow(ctx, `SELECT `+orderColumns+` FROM orders WHERE order_number = $1`, orderNumber), &order)
 	if err != nil {
 		return nil, err
 	}
-	order.Items = items
+
+	if err := r.loadOrderDetails(ctx, &order); err != nil {
+		return nil, err
+	}
 
 	return &order, nil
 }
 
+// orderSortColumns maps the sort keys accepted by ListOrders to columns.
+var orderSortColumns = map[string]string{
+	"createdAt": "created_at",
+	"total":     "total",
+}
+
+// ListOrders returns one page of orders matching the filters together with
+// the number of matching orders. Pages are keyset-paginated on the sort
+// column and id: "afterValue" and "afterID" identify the last row of the
+// previous page. Line items are not loaded.
+func (r *orderRepository) ListOrders(ctx context.Context, filters map[string]interface{}) ([]models.Order, int64, error) {
+	var args queryArgs
+	conditions := orderFilterConditions(filters, "", &args)
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	total, err := r.CountOrders(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sortBy, _ := filters["sortBy"].(string)
+	column, ok := orderSortColumns[sortBy]
+	if !ok {
+		column = "created_at"
+	}
+	direction, comparison := "DESC", "<"
+	if sortOrder, _ := filters["sortOrder"].(string); strings.EqualFold(sortOrder, "asc") {
+		direction, comparison = "ASC", ">"
+	}
+
+	if afterID, ok := filters["afterID"].(string); ok && afterID != "" {
+		keyset := fmt.Sprintf("(%s, id) %s (%s, %s::uuid)", column, comparison, args.add(filters["afterValue"]), args.add(afterID))
+		if where == "" {
+			where = " WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+	}
+
+	limit, _ := filters["limit"].(int)
+	query := fmt.Sprintf(`SELECT %s FROM orders%s ORDER BY %s %s, id %s LIMIT %s`,
+		orderColumns, where, column, direction, direction, args.add(limit))
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	orders := []models.Order{}
+	for rows.Next() {
+		var order models.Order
+		if err := scanOrder(rows, &order); err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, total, rows.Err()
+}
+
+func (r *orderRepository) CountOrders(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	var args queryArgs
+	query := `SELECT COUNT(*) FROM orders`
+	if conditions := orderFilterConditions(filters, "", &args); len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	err := r.db.QueryRow(ctx, query, args...).Scan(&total)
+	return total, err
+}
+
+// exportFetchSize is how many rows StreamOrderExport fetches from its cursor
+// at a time.
+const exportFetchSize = 1000
+
+// StreamOrderExport calls fn for every line of the orders matching the
+// filters, oldest order first. Rows are read through a server-side cursor so
+// memory use does not grow with the size of the export.
+func (r *orderRepository) StreamOrderExport(ctx context.Context, filters map[string]interface{}, fn func(row models.OrderExportRow) error) error {
+	var args queryArgs
+	where := ""
+	if conditions := orderFilterConditions(filters, "o.", &args); len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DECLARE order_export NO SCROLL CURSOR FOR
+		SELECT o.order_number, o.id, o.created_at, o.status, o.payment_method, COALESCE(o.customer_email, ''), o.user_id, o.total, o.cod_fee, o.currency,
+			i.product_id, i.name, COALESCE(i.size, ''), i.quantity, i.price, i.subtotal, i.status,
+			COALESCE(a.city, ''), COALESCE(a.state, ''), COALESCE(a.pincode, '')
+		FROM orders o
+		JOIN order_items i ON i.order_id = o.id
+		LEFT JOIN order_addresses a ON a.order_id = o.id AND a.type = '`+constants.AddressTypeShipping+`'`+where+`
+		ORDER BY o.created_at, o.id, i.id`, args...)
+	if err != nil {
+		return err
+	}
+
+	for {
+		rows, err := tx.Query(ctx, fmt.Sprintf("FETCH %d FROM order_export", exportFetchSize))
+		if err != nil {
+			return err
+		}
+
+		fetched := 0
+		for rows.Next() {
+			var row models.OrderExportRow
+			var currency string
+			if err := rows.Scan(&row.OrderNumber, &row.OrderID, &row.CreatedAt, &row.OrderStatus, &row.PaymentMethod, &row.CustomerEmail,
+				&row.UserID, &row.OrderTotal.Amount, &row.CODFee.Amount, &currency, &row.ProductID, &row.ItemName, &row.Size, &row.Quantity,
+				&row.Price.Amount, &row.Subtotal.Amount, &row.ItemStatus, &row.ShipCity, &row.ShipState, &row.ShipPincode); err != nil {
+				rows.Close()
+				return err
+			}
+			row.OrderTotal.Currency, row.CODFee.Currency = currency, currency
+			row.Price.Currency, row.Subtotal.Currency = currency, currency
+			fetched++
+			if err := fn(row); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if fetched < exportFetchSize {
+			return nil
+		}
+	}
+}
+
+// queryArgs collects the values of a dynamically built query.
+type queryArgs []interface{}
+
+// add appends a value and returns its placeholder.
+func (a *queryArgs) add(v interface{}) string {
+	*a = append(*a, v)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// orderFilterConditions turns order list filters into SQL conditions on the
+// orders table, whose columns are prefixed with alias.
+func orderFilterConditions(filters map[string]interface{}, alias string, args *queryArgs) []string {
+	var conditions []string
+	if status, ok := filters["status"].(string); ok && status != "" {
+		conditions = append(conditions, alias+"status = "+args.add(status))
+	}
+	if userID, ok := filters["userId"].(string); ok && userID != "" {
+		conditions = append(conditions, alias+"user_id = "+args.add(userID))
+	}
+	if method, ok := filters["paymentMethod"].(string); ok && method != "" {
+		conditions = append(conditions, alias+"payment_method = "+args.add(method))
+	}
+	if from, ok := filters["from"].(time.Time); ok && !from.IsZero() {
+		conditions = append(conditions, alias+"created_at >= "+args.add(from))
+	}
+	if to, ok := filters["to"].(time.Time); ok && !to.IsZero() {
+		conditions = append(conditions, alias+"created_at < "+args.add(to))
+	}
+	if minTotal, ok := filters["minTotal"].(int64); ok && minTotal > 0 {
+		conditions = append(conditions, alias+"total >= "+args.add(minTotal))
+	}
+	if maxTotal, ok := filters["maxTotal"].(int64); ok && maxTotal > 0 {
+		conditions = append(conditions, alias+"total <= "+args.add(maxTotal))
+	}
+	if search, ok := filters["search"].(string); ok && search != "" {
+		pattern := args.add(escapeLike(search) + "%")
+		conditions = append(conditions, fmt.Sprintf("(%sorder_number LIKE UPPER(%s) OR LOWER(%scustomer_email) LIKE LOWER(%s))", alias, pattern, alias, pattern))
+	}
+	return conditions
+}
+
+// escapeLike escapes the LIKE wildcards in user input.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func (r *orderRepository) UpdateOrderStatus(orderID string, status string) error {
 
 	normalized := strings.ToLower(status)
@@ -97,17 +322,26 @@ func (r *orderRepository) UpdateOrderStatus(orderID string, status string) error
 	switch normalized {
 	case strings.ToLower(constants.OrderStatusPending):
 		validStatus = constants.OrderStatusPending
+	case strings.ToLower(constants.OrderStatusPaid):
+		validStatus = constants.OrderStatusPaid
 	case strings.ToLower(constants.OrderStatusShipped):
 		validStatus = constants.OrderStatusShipped
+	case strings.ToLower(constants.OrderStatusPartiallyShipped):
+		validStatus = constants.OrderStatusPartiallyShipped
 	case strings.ToLower(constants.OrderStatusDelivered):
 		validStatus = constants.OrderStatusDelivered
 	case strings.ToLower(constants.OrderStatusCancelled):
 		validStatus = constants.OrderStatusCancelled
+	case strings.ToLower(constants.OrderStatusRefunder):
+		validStatus = constants.OrderStatusRefunder
 	default:
 		return errors.New("invalid order status")
 	}
 
-	cmd, err := r.db.Exec(context.Background(), `UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3`, validStatus, time.Now(), orderID)
+	query := `UPDATE orders SET status = $1, updated_at = $2,
+		delivered_at = CASE WHEN $1 = 'DELIVERED' THEN $2 ELSE delivered_at END
+		WHERE id = $3`
+	cmd, err := r.db.Exec(context.Background(), query, validStatus, time.Now(), orderID)
 	if err != nil {
 		return err
 	}
@@ -117,6 +351,32 @@ func (r *orderRepository) UpdateOrderStatus(orderID string, status string) error
 	return nil
 }
 
+// DeliverCODOrder marks a cash-on-delivery order still in status from as
+// DELIVERED and records the payment the courier collected in the same
+// transaction, so a delivered COD order never lacks its payment.
+func (r *orderRepository) DeliverCODOrder(ctx context.Context, orderID, from string, payment *models.Payment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	cmd, err := tx.Exec(ctx, `UPDATE orders SET status = $1, updated_at = $2, delivered_at = $2 WHERE id = $3 AND status = $4`,
+		constants.OrderStatusDelivered, now, orderID, from)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return interfaces.ErrOrderStatusChanged
+	}
+
+	if err := insertPayment(ctx, tx, payment); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
 func (r *orderRepository) DeleteOrder(orderID string) error {
 	ctx := context.Background()
 	tx, err := r.db.Begin(ctx)
@@ -131,6 +391,12 @@ func (r *orderRepository) DeleteOrder(orderID string) error {
 	}
 	_, err = tx.Exec(ctx, `DELETE FROM orders WHERE id = $1`, orderID)
 	if err != nil {
+		// Invoices are kept for tax records, so their foreign key restricts
+		// deleting the order.
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.TableName == "invoices" {
+			return interfaces.ErrOrderInvoiced
+		}
 		return err
 	}
 
@@ -148,21 +414,154 @@ func (r *orderRepository) CancelOrder(orderID string) error {
 	return nil
 }
 
-func (r *orderRepository) getOrderItems(ctx context.Context, orderID string) ([]models.OrderItem, error) {
-	items := []models.OrderItem{}
+// CancelOrderItems cancels the given active lines of an order and recomputes
+// the order total from the lines that remain. When no active lines are left
+// the whole order is cancelled. The order row is locked for the whole change,
+// and every line must still be active, so the refund worked out for them is
+// recorded for exactly the lines cancelled.
+func (r *orderRepository) CancelOrderItems(ctx context.Context, orderID, from string, itemIDs []string, refund *models.Refund, unrecorded models.Money) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	if err := tx.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&status); err != nil {
+		return err
+	}
+	if status != from {
+		return interfaces.ErrOrderStatusChanged
+	}
+
+	now := time.Now()
+	cmd, err := tx.Exec(ctx, `UPDATE order_items SET status = $1, cancelled_at = $2
+		WHERE order_id = $3 AND status = $4 AND id = ANY($5::uuid[])`,
+		constants.OrderItemStatusCancelled, now, orderID, constants.OrderItemStatusActive, itemIDs)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() != int64(len(itemIDs)) {
+		return interfaces.ErrOrderItemsChanged
+	}
 
-	rows, err := r.db.Query(ctx, `SELECT id, order_id, product_id, name, price, quantity, image, size, subtotal FROM order_items WHERE order_id = $1`, orderID)
+	_, err = tx.Exec(ctx, `UPDATE orders SET
+		total = cod_fee + (SELECT COALESCE(SUM(subtotal), 0) FROM order_items WHERE order_id = $1 AND status = $2),
+		status = CASE WHEN EXISTS (SELECT 1 FROM order_items WHERE order_id = $1 AND status = $2) THEN status ELSE $3 END,
+		updated_at = $4
+		WHERE id = $1`,
+		orderID, constants.OrderItemStatusActive, constants.OrderStatusCancelled, now)
+	if err != nil {
+		return err
+	}
+
+	if refund != nil {
+		if err := reserveRefund(ctx, tx, refund, unrecorded); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// scanOrder reads a row of orderColumns. Amounts are stored in minor units
+// beside the order's currency.
+func scanOrder(row pgx.Row, order *models.Order) error {
+	var currency string
+	if err := row.Scan(&order.ID, &order.OrderNumber, &order.UserID, &order.CustomerEmail, &order.Total.Amount, &order.Status, &order.PaymentMethod, &order.CODFee.Amount, &order.DeliveredAt, &order.CreatedAt, &order.UpdatedAt, &currency); err != nil {
+		return err
+	}
+	order.Total.Currency, order.CODFee.Currency = currency, currency
+	return nil
+}
+
+// loadOrderDetails fills in the line items and address snapshots of an order.
+func (r *orderRepository) loadOrderDetails(ctx context.Context, order *models.Order) error {
+	items, err := r.getOrderItems(ctx, order.ID.String())
+	if err != nil {
+		return err
+	}
+	order.Items = items
+
+	rows, err := r.db.Query(ctx, `SELECT type, name, phone, line1, COALESCE(line2, ''), city, state, pincode, country FROM order_addresses WHERE order_id = $1`, order.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var addressType string
+		var a models.Address
+		if err := rows.Scan(&addressType, &a.Name, &a.Phone, &a.Line1, &a.Line2, &a.City, &a.State, &a.Pincode, &a.Country); err != nil {
+			return err
+		}
+		switch addressType {
+		case constants.AddressTypeShipping:
+			order.ShippingAddress = &a
+		case constants.AddressTypeBilling:
+			order.BillingAddress = &a
+		}
+	}
+	return rows.Err()
+}
+
+func (r *orderRepository) getOrderItems(ctx context.Context, orderID string) ([]models.OrderItem, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+orderItemColumns+` FROM order_items WHERE order_id = $1`, orderID)
 	if err != nil {
 		return nil, err
 	}
+	return scanOrderItems(rows)
+}
+
+func scanOrderItems(rows pgx.Rows) ([]models.OrderItem, error) {
 	defer rows.Close()
 
+	items := []models.OrderItem{}
 	for rows.Next() {
 		var item models.OrderItem
-		if err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.Name, &item.Price, &item.Quantity, &item.Image, &item.Size, &item.Subtotal); err != nil {
+		var currency string
+		if err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.Name, &item.Category, &item.Price.Amount, &item.Quantity, &item.Image, &item.Size, &item.Subtotal.Amount, &item.Status, &item.CancelledAt, &currency); err != nil {
 			return nil, err
 		}
+		item.Price.Currency, item.Subtotal.Currency = currency, currency
 		items = append(items, item)
 	}
-	return items, nil
+	return items, rows.Err()
+}
+
+// GetDeliveredOrderID returns the most recent delivered order in which the
+// user received productID, or pgx.ErrNoRows if there is none.
+func (r *orderRepository) GetDeliveredOrderID(ctx context.Context, userID, productID string) (string, error) {
+	var orderID string
+	err := r.db.QueryRow(ctx, `SELECT o.id FROM orders o
+		JOIN order_items i ON i.order_id = o.id
+		WHERE o.user_id = $1 AND i.product_id = $2 AND o.status = $3 AND i.status = $4
+		ORDER BY o.created_at DESC LIMIT 1`,
+		userID, productID, constants.OrderStatusDelivered, constants.OrderItemStatusActive).Scan(&orderID)
+	return orderID, err
+}
+
+func (r *orderRepository) GetUserIDsWithoutEmail(ctx context.Context) ([]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT user_id FROM orders WHERE customer_email IS NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+func (r *orderRepository) SetCustomerEmail(ctx context.Context, userID, email string) (int64, error) {
+	cmd, err := r.db.Exec(ctx, `UPDATE orders SET customer_email = $1 WHERE user_id = $2 AND customer_email IS NULL`, email, userID)
+	if err != nil {
+		return 0, err
+	}
+	return cmd.RowsAffected(), nil
 }
@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+const refundColumns = `id, order_id, amount, currency, reason, COALESCE(reference, ''), status, settled_at, COALESCE(settled_by, ''), created_at`
+
+// paidPaymentStatuses are the payment statuses in which the money has been
+// received.
+var paidPaymentStatuses = []string{constants.PaymentStatusCaptured, constants.PaymentStatusCollected, constants.PaymentStatusReconciled}
+
+const paymentColumns = `id, order_id, method, amount, status, COALESCE(reference, ''), collected_at, reconciled_at, COALESCE(reconciled_by, ''), created_at, updated_at, currency`
+
+type paymentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPaymentRepository(db *pgxpool.Pool) interfaces.PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+// CreatePayment inserts a payment row. Recording the same method twice for an
+// order is a no-op so that retried status updates stay idempotent.
+func (r *paymentRepository) CreatePayment(ctx context.Context, payment *models.Payment) error {
+	return insertPayment(ctx, r.db, payment)
+}
+
+// execer runs a statement on the pool or within a transaction.
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+func insertPayment(ctx context.Context, db execer, payment *models.Payment) error {
+	now := time.Now()
+	query := `INSERT INTO payments (id, order_id, method, amount, currency, status, reference, collected_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10)
+		ON CONFLICT (order_id, method) DO NOTHING`
+	_, err := db.Exec(ctx, query, payment.ID, payment.OrderID, payment.Method, payment.Amount.Amount, payment.Amount.Currency, payment.Status, payment.Reference, payment.CollectedAt, now, now)
+	return err
+}
+
+func (r *paymentRepository) GetPaymentsByOrderID(ctx context.Context, orderID string) ([]models.Payment, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+paymentColumns+` FROM payments WHERE order_id = $1 ORDER BY created_at`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return scanPayments(rows)
+}
+
+func (r *paymentRepository) GetUnreconciledCODPayments(ctx context.Context) ([]models.Payment, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+paymentColumns+` FROM payments WHERE method = $1 AND status = $2 ORDER BY collected_at`,
+		constants.PaymentMethodCOD, constants.PaymentStatusCollected)
+	if err != nil {
+		return nil, err
+	}
+	return scanPayments(rows)
+}
+
+// ReconcileCODPayments marks collected COD payments for the given orders as
+// received by the business and returns how many rows were updated.
+func (r *paymentRepository) ReconcileCODPayments(ctx context.Context, orderIDs []string, adminID string) (int64, error) {
+	now := time.Now()
+	cmd, err := r.db.Exec(ctx, `UPDATE payments SET status = $1, reconciled_at = $2, reconciled_by = $3, updated_at = $2
+		WHERE method = $4 AND status = $5 AND order_id = ANY($6::uuid[])`,
+		constants.PaymentStatusReconciled, now, adminID, constants.PaymentMethodCOD, constants.PaymentStatusCollected, orderIDs)
+	if err != nil {
+		return 0, err
+	}
+	return cmd.RowsAffected(), nil
+}
+
+// CreateRefund records a PENDING refund if what is left of the amount paid
+// for the order covers it.
+func (r *paymentRepository) CreateRefund(ctx context.Context, refund *models.Refund, unrecorded models.Money) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := reserveRefund(ctx, tx, refund, unrecorded); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// UpdateRefundStatus records the gateway's answer for a PENDING refund.
+func (r *paymentRepository) UpdateRefundStatus(ctx context.Context, refund *models.Refund) error {
+	cmd, err := r.db.Exec(ctx, `UPDATE refunds SET status = $1, reference = NULLIF($2, ''), updated_at = $3 WHERE id = $4 AND status = $5`,
+		refund.Status, refund.Reference, time.Now(), refund.ID, constants.RefundStatusPending)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return errors.New("no rows updated")
+	}
+	return nil
+}
+
+// GetPendingRefunds returns refunds recorded before the given time that the
+// gateway has not taken yet.
+func (r *paymentRepository) GetPendingRefunds(ctx context.Context, before time.Time) ([]models.Refund, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+refundColumns+` FROM refunds WHERE status = $1 AND created_at < $2 ORDER BY created_at`,
+		constants.RefundStatusPending, before)
+	if err != nil {
+		return nil, err
+	}
+	return scanRefunds(rows)
+}
+
+func (r *paymentRepository) GetUnsettledRefunds(ctx context.Context) ([]models.Refund, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+refundColumns+` FROM refunds WHERE status = $1 ORDER BY created_at`,
+		constants.RefundStatusSettlementPending)
+	if err != nil {
+		return nil, err
+	}
+	return scanRefunds(rows)
+}
+
+// SettleRefunds marks refunds waiting for manual settlement as paid and
+// returns how many rows were updated.
+func (r *paymentRepository) SettleRefunds(ctx context.Context, refundIDs []string, reference, adminID string) (int64, error) {
+	now := time.Now()
+	cmd, err := r.db.Exec(ctx, `UPDATE refunds SET status = $1, reference = COALESCE(NULLIF($2, ''), reference), settled_at = $3, settled_by = $4, updated_at = $3
+		WHERE status = $5 AND id = ANY($6::uuid[])`,
+		constants.RefundStatusCompleted, reference, now, adminID, constants.RefundStatusSettlementPending, refundIDs)
+	if err != nil {
+		return 0, err
+	}
+	return cmd.RowsAffected(), nil
+}
+
+// reserveRefund inserts refund as PENDING within tx. The order row is locked
+// first, so refunds for the same order from returns and cancellations are
+// checked one at a time and cannot together exceed what was paid. Locking the
+// order rather than its payments also covers orders with no payment rows,
+// which are taken to have paid unrecorded. An order refunded in full is
+// marked REFUNDED.
+func reserveRefund(ctx context.Context, tx pgx.Tx, refund *models.Refund, unrecorded models.Money) error {
+	var currency string
+	if err := tx.QueryRow(ctx, `SELECT currency FROM orders WHERE id = $1 FOR UPDATE`, refund.OrderID).Scan(&currency); err != nil {
+		return err
+	}
+	if refund.Amount.Currency != currency {
+		return interfaces.ErrRefundExceedsPaid
+	}
+
+	var paid, refunded int64
+	var payments int
+	err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(amount) FILTER (WHERE status = ANY($2)), 0)::bigint, COUNT(*) FROM payments WHERE order_id = $1`,
+		refund.OrderID, paidPaymentStatuses).Scan(&paid, &payments)
+	if err != nil {
+		return err
+	}
+	if payments == 0 {
+		paid = unrecorded.Amount
+	}
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(amount), 0)::bigint FROM refunds WHERE order_id = $1`, refund.OrderID).Scan(&refunded); err != nil {
+		return err
+	}
+	remaining := paid - refunded - refund.Amount.Amount
+	if remaining < 0 {
+		return interfaces.ErrRefundExceedsPaid
+	}
+
+	refund.Status = constants.RefundStatusPending
+	_, err = tx.Exec(ctx, `INSERT INTO refunds (id, order_id, amount, currency, reason, reference, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $8)`,
+		refund.ID, refund.OrderID, refund.Amount.Amount, refund.Amount.Currency, refund.Reason, refund.Reference, refund.Status, refund.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if remaining == 0 {
+		_, err = tx.Exec(ctx, `UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3`,
+			constants.OrderStatusRefunder, time.Now(), refund.OrderID)
+	}
+	return err
+}
+
+func scanPayments(rows pgx.Rows) ([]models.Payment, error) {
+	defer rows.Close()
+
+	payments := []models.Payment{}
+	for rows.Next() {
+		var p models.Payment
+		if err := rows.Scan(&p.ID, &p.OrderID, &p.Method, &p.Amount.Amount, &p.Status, &p.Reference, &p.CollectedAt, &p.ReconciledAt, &p.ReconciledBy, &p.CreatedAt, &p.UpdatedAt, &p.Amount.Currency); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+func scanRefunds(rows pgx.Rows) ([]models.Refund, error) {
+	defer rows.Close()
+
+	refunds := []models.Refund{}
+	for rows.Next() {
+		var r models.Refund
+		if err := rows.Scan(&r.ID, &r.OrderID, &r.Amount.Amount, &r.Amount.Currency, &r.Reason, &r.Reference, &r.Status, &r.SettledAt, &r.SettledBy, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, r)
+	}
+	return refunds, rows.Err()
+}
@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+const returnColumns = `id, order_id, order_item_id, user_id, quantity, reason, status, refund_amount, COALESCE(admin_note, ''), created_at, updated_at, currency, restocked_at`
+
+type returnRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReturnRepository(db *pgxpool.Pool) interfaces.ReturnRepository {
+	return &returnRepository{db: db}
+}
+
+// CreateReturn stores a new return. The order line is locked while the units
+// already being returned are counted, so concurrent requests cannot together
+// return more units than were bought.
+func (r *returnRepository) CreateReturn(ctx context.Context, ret *models.ReturnRequest, actor string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var bought int
+	var status string
+	if err := tx.QueryRow(ctx, `SELECT quantity, status FROM order_items WHERE id = $1 FOR UPDATE`, ret.OrderItemID).Scan(&bought, &status); err != nil {
+		return err
+	}
+	if status != constants.OrderItemStatusActive {
+		return interfaces.ErrOrderItemsChanged
+	}
+	var returned int
+	err = tx.QueryRow(ctx, `SELECT COALESCE(SUM(quantity), 0) FROM returns WHERE order_item_id = $1 AND status <> $2`,
+		ret.OrderItemID, constants.ReturnStatusRejected).Scan(&returned)
+	if err != nil {
+		return err
+	}
+	if ret.Quantity > bought-returned {
+		return interfaces.ErrReturnExceedsOrder
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO returns (id, order_id, order_item_id, user_id, quantity, reason, status, refund_amount, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		ret.ID, ret.OrderID, ret.OrderItemID, ret.UserID, ret.Quantity, ret.Reason, ret.Status, ret.RefundAmount.Amount, ret.RefundAmount.Currency, ret.CreatedAt, ret.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertReturnEvent(ctx, tx, ret.ID, ret.Status, ret.Reason, actor); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *returnRepository) GetReturnByID(ctx context.Context, id string) (*models.ReturnRequest, error) {
+	var ret models.ReturnRequest
+	if err := scanReturn(r.db.QueryRow(ctx, `SELECT `+returnColumns+` FROM returns WHERE id = $1`, id), &ret); err != nil {
+		return nil, err
+	}
+
+	history, err := r.getReturnEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ret.History = history
+	return &ret, nil
+}
+
+func (r *returnRepository) GetReturnsByUserID(ctx context.Context, userID string) ([]models.ReturnRequest, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+returnColumns+` FROM returns WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanReturns(rows)
+}
+
+func (r *returnRepository) GetReturns(ctx context.Context, status string) ([]models.ReturnRequest, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+returnColumns+` FROM returns WHERE ($1 = '' OR status = $1) ORDER BY created_at`, status)
+	if err != nil {
+		return nil, err
+	}
+	return scanReturns(rows)
+}
+
+// GetReturnedQuantity returns how many units of an order item are already
+// covered by return requests that have not been rejected.
+func (r *returnRepository) GetReturnedQuantity(ctx context.Context, orderItemID string) (int, error) {
+	var qty int
+	err := r.db.QueryRow(ctx, `SELECT COALESCE(SUM(quantity), 0) FROM returns WHERE order_item_id = $1 AND status <> $2`,
+		orderItemID, constants.ReturnStatusRejected).Scan(&qty)
+	return qty, err
+}
+
+func (r *returnRepository) UpdateReturnStatus(ctx context.Context, ret *models.ReturnRequest, from, note, actor string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := updateReturnStatus(ctx, tx, ret, from, note, actor); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *returnRepository) RefundReturn(ctx context.Context, ret *models.ReturnRequest, from string, refund *models.Refund, unrecorded models.Money, actor string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	ret.Status = constants.ReturnStatusRefunded
+	if err := updateReturnStatus(ctx, tx, ret, from, "", actor); err != nil {
+		return err
+	}
+	if err := reserveRefund(ctx, tx, refund, unrecorded); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ClaimRestock marks a received return restocked at at, unless it already
+// is. It reports whether this call made the claim.
+func (r *returnRepository) ClaimRestock(ctx context.Context, id string, at time.Time) (bool, error) {
+	cmd, err := r.db.Exec(ctx, `UPDATE returns SET restocked_at = $1 WHERE id = $2 AND restocked_at IS NULL AND status IN ($3, $4)`,
+		at, id, constants.ReturnStatusReceived, constants.ReturnStatusRefunded)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// ReleaseRestock clears a claim whose restock failed, so it can be retried.
+func (r *returnRepository) ReleaseRestock(ctx context.Context, id string) error {
+	_, err := r.db.Exec(ctx, `UPDATE returns SET restocked_at = NULL WHERE id = $1`, id)
+	return err
+}
+
+// updateReturnStatus moves a return to ret.Status only if it is still in
+// status from, so two requests racing through the same transition cannot
+// both act on it.
+func updateReturnStatus(ctx context.Context, tx pgx.Tx, ret *models.ReturnRequest, from, note, actor string) error {
+	ret.UpdatedAt = time.Now()
+	cmd, err := tx.Exec(ctx, `UPDATE returns SET status = $1, refund_amount = $2, admin_note = NULLIF($3, ''), updated_at = $4 WHERE id = $5 AND status = $6`,
+		ret.Status, ret.RefundAmount.Amount, ret.AdminNote, ret.UpdatedAt, ret.ID, from)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return interfaces.ErrReturnStatusChanged
+	}
+	return insertReturnEvent(ctx, tx, ret.ID, ret.Status, note, actor)
+}
+
+func (r *returnRepository) getReturnEvents(ctx context.Context, returnID string) ([]models.ReturnEvent, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, return_id, status, COALESCE(note, ''), actor, created_at FROM return_events WHERE return_id = $1 ORDER BY created_at`, returnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.ReturnEvent{}
+	for rows.Next() {
+		var e models.ReturnEvent
+		if err := rows.Scan(&e.ID, &e.ReturnID, &e.Status, &e.Note, &e.Actor, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func insertReturnEvent(ctx context.Context, tx pgx.Tx, returnID uuid.UUID, status, note, actor string) error {
+	_, err := tx.Exec(ctx, `INSERT INTO return_events (id, return_id, status, note, actor, created_at) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)`,
+		uuid.New(), returnID, status, note, actor, time.Now())
+	return err
+}
+
+func scanReturn(row pgx.Row, ret *models.ReturnRequest) error {
+	return row.Scan(&ret.ID, &ret.OrderID, &ret.OrderItemID, &ret.UserID, &ret.Quantity, &ret.Reason, &ret.Status, &ret.RefundAmount.Amount, &ret.AdminNote, &ret.CreatedAt, &ret.UpdatedAt, &ret.RefundAmount.Currency, &ret.RestockedAt)
+}
+
+func scanReturns(rows pgx.Rows) ([]models.ReturnRequest, error) {
+	defer rows.Close()
+
+	returns := []models.ReturnRequest{}
+	for rows.Next() {
+		var ret models.ReturnRequest
+		if err := scanReturn(rows, &ret); err != nil {
+			return nil, err
+		}
+		returns = append(returns, ret)
+	}
+	return returns, rows.Err()
+}
@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+const shipmentColumns = `id, order_id, carrier, tracking_number, status, estimated_delivery, shipped_at, delivered_at, created_at, updated_at`
+
+type shipmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewShipmentRepository(db *pgxpool.Pool) interfaces.ShipmentRepository {
+	return &shipmentRepository{db: db}
+}
+
+// CreateShipment stores a shipment, the order lines it carries and its
+// initial tracking events, and moves the order to SHIPPED or
+// PARTIALLY_SHIPPED, in one transaction. The order row is locked while its
+// status and unshipped quantities are checked, so two shipments created at
+// once cannot ship the same units.
+func (r *shipmentRepository) CreateShipment(ctx context.Context, shipment *models.Shipment, statuses []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	if err := tx.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1 FOR UPDATE`, shipment.OrderID).Scan(&status); err != nil {
+		return err
+	}
+	if !slices.Contains(statuses, status) {
+		return interfaces.ErrOrderStatusChanged
+	}
+
+	remaining, err := unshippedQuantities(ctx, tx, shipment.OrderID)
+	if err != nil {
+		return err
+	}
+	for _, item := range shipment.Items {
+		id := item.OrderItemID.String()
+		if item.Quantity > remaining[id] {
+			return interfaces.ErrShipmentExceedsOrder
+		}
+		remaining[id] -= item.Quantity
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO shipments (id, order_id, carrier, tracking_number, status, estimated_delivery, shipped_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		shipment.ID, shipment.OrderID, shipment.Carrier, shipment.TrackingNumber, shipment.Status, shipment.EstimatedDelivery,
+		shipment.ShippedAt, shipment.CreatedAt, shipment.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range shipment.Items {
+		_, err := tx.Exec(ctx, `INSERT INTO shipment_items (shipment_id, order_item_id, quantity) VALUES ($1, $2, $3)`,
+			shipment.ID, item.OrderItemID, item.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := insertTrackingEvents(ctx, tx, shipment.Events); err != nil {
+		return err
+	}
+
+	next := constants.OrderStatusShipped
+	for _, left := range remaining {
+		if left > 0 {
+			next = constants.OrderStatusPartiallyShipped
+			break
+		}
+	}
+	if next != status {
+		_, err = tx.Exec(ctx, `UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3`, next, time.Now(), shipment.OrderID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *shipmentRepository) GetShipmentByID(ctx context.Context, id string) (*models.Shipment, error) {
+	var s models.Shipment
+	if err := scanShipment(r.db.QueryRow(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE id = $1`, id), &s); err != nil {
+		return nil, err
+	}
+	if err := r.loadShipmentDetails(ctx, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *shipmentRepository) GetShipmentsByOrderID(ctx context.Context, orderID string) ([]models.Shipment, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE order_id = $1 ORDER BY shipped_at`, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipments := []models.Shipment{}
+	for rows.Next() {
+		var s models.Shipment
+		if err := scanShipment(rows, &s); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		shipments = append(shipments, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range shipments {
+		if err := r.loadShipmentDetails(ctx, &shipments[i]); err != nil {
+			return nil, err
+		}
+	}
+	return shipments, nil
+}
+
+// GetShippedQuantities returns the number of units already shipped for each
+// line of an order, keyed by order item ID.
+func (r *shipmentRepository) GetShippedQuantities(ctx context.Context, orderID string) (map[string]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT si.order_item_id, SUM(si.quantity) FROM shipment_items si
+		JOIN shipments s ON s.id = si.shipment_id
+		WHERE s.order_id = $1 GROUP BY si.order_item_id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shipped := map[string]int{}
+	for rows.Next() {
+		var itemID uuid.UUID
+		var qty int
+		if err := rows.Scan(&itemID, &qty); err != nil {
+			return nil, err
+		}
+		shipped[itemID.String()] = qty
+	}
+	return shipped, rows.Err()
+}
+
+// unshippedQuantities returns the units of each active line of an order not
+// yet covered by a shipment, keyed by order item ID.
+func unshippedQuantities(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) (map[string]int, error) {
+	rows, err := tx.Query(ctx, `SELECT oi.id, oi.quantity - COALESCE((SELECT SUM(si.quantity) FROM shipment_items si WHERE si.order_item_id = oi.id), 0)
+		FROM order_items oi WHERE oi.order_id = $1 AND oi.status = $2`, orderID, constants.OrderItemStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	remaining := map[string]int{}
+	for rows.Next() {
+		var itemID uuid.UUID
+		var qty int
+		if err := rows.Scan(&itemID, &qty); err != nil {
+			return nil, err
+		}
+		remaining[itemID.String()] = qty
+	}
+	return remaining, rows.Err()
+}
+
+// AddTrackingEvents appends events to a shipment, skipping any already
+// recorded, and saves the shipment's current status.
+func (r *shipmentRepository) AddTrackingEvents(ctx context.Context, shipment *models.Shipment, events []models.TrackingEvent) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertTrackingEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
+	shipment.UpdatedAt = time.Now()
+	cmd, err := tx.Exec(ctx, `UPDATE shipments SET status = $1, delivered_at = $2, updated_at = $3 WHERE id = $4`,
+		shipment.Status, shipment.DeliveredAt, shipment.UpdatedAt, shipment.ID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return errors.New("no rows updated")
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *shipmentRepository) loadShipmentDetails(ctx context.Context, s *models.Shipment) error {
+	rows, err := r.db.Query(ctx, `SELECT order_item_id, quantity FROM shipment_items WHERE shipment_id = $1`, s.ID)
+	if err != nil {
+		return err
+	}
+	s.Items = []models.ShipmentItem{}
+	for rows.Next() {
+		var item models.ShipmentItem
+		if err := rows.Scan(&item.OrderItemID, &item.Quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		s.Items = append(s.Items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = r.db.Query(ctx, `SELECT id, shipment_id, status, COALESCE(description, ''), COALESCE(location, ''), occurred_at
+		FROM shipment_events WHERE shipment_id = $1 ORDER BY occurred_at`, s.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	s.Events = []models.TrackingEvent{}
+	for rows.Next() {
+		var e models.TrackingEvent
+		if err := rows.Scan(&e.ID, &e.ShipmentID, &e.Status, &e.Description, &e.Location, &e.OccurredAt); err != nil {
+			return err
+		}
+		s.Events = append(s.Events, e)
+	}
+	return rows.Err()
+}
+
+func insertTrackingEvents(ctx context.Context, tx pgx.Tx, events []models.TrackingEvent) error {
+	for _, e := range events {
+		_, err := tx.Exec(ctx, `INSERT INTO shipment_events (id, shipment_id, status, description, location, occurred_at, created_at)
+			VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7)
+			ON CONFLICT (shipment_id, status, occurred_at) DO NOTHING`,
+			e.ID, e.ShipmentID, e.Status, e.Description, e.Location, e.OccurredAt, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanShipment(row pgx.Row, s *models.Shipment) error {
+	return row.Scan(&s.ID, &s.OrderID, &s.Carrier, &s.TrackingNumber, &s.Status, &s.EstimatedDelivery, &s.ShippedAt, &s.DeliveredAt, &s.CreatedAt, &s.UpdatedAt)
+}
@@ -0,0 +1,69 @@
+package redisrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type recentlyViewedRepository struct {
+	rdb *redis.Client
+	ctx context.Context
+}
+
+func NewRecentlyViewedRepository(rdb *redis.Client, ctx context.Context) interfaces.RecentlyViewedRepository {
+	return &recentlyViewedRepository{rdb: rdb, ctx: ctx}
+}
+
+// Record moves productID to the front of the list, trimming it to limit.
+func (r *recentlyViewedRepository) Record(key, productID string, limit int, ttl time.Duration) error {
+	_, err := r.rdb.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(r.ctx, key, 0, productID)
+		pipe.LPush(r.ctx, key, productID)
+		pipe.LTrim(r.ctx, key, 0, int64(limit-1))
+		pipe.Expire(r.ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+func (r *recentlyViewedRepository) List(key string) ([]string, error) {
+	return r.rdb.LRange(r.ctx, key, 0, -1).Result()
+}
+
+// Merge puts the views in fromKey ahead of those already in toKey, drops
+// duplicates and deletes fromKey.
+func (r *recentlyViewedRepository) Merge(fromKey, toKey string, limit int, ttl time.Duration) error {
+	from, err := r.List(fromKey)
+	if err != nil || len(from) == 0 {
+		return err
+	}
+	to, err := r.List(toKey)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(from)+len(to))
+	merged := make([]interface{}, 0, limit)
+	for _, id := range append(from, to...) {
+		if len(merged) == limit {
+			break
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		merged = append(merged, id)
+	}
+
+	_, err = r.rdb.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(r.ctx, toKey, fromKey)
+		pipe.RPush(r.ctx, toKey, merged...)
+		pipe.Expire(r.ctx, toKey, ttl)
+		return nil
+	})
+	return err
+}
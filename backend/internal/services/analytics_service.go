@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+var (
+	ErrInvalidDateRange = errors.New("invalid date range")
+	ErrInvalidInterval  = errors.New("interval must be day, week or month")
+	ErrInvalidCompare   = errors.New("compare must be previous or year")
+)
+
+// maxAnalyticsRange caps how many days a single report may cover.
+const maxAnalyticsRange = 3 * 366
+
+// funnelStatuses is the order in which statuses are reported in the funnel.
+var funnelStatuses = []string{
+	constants.OrderStatusPending,
+	constants.OrderStatusCODPending,
+	constants.OrderStatusPaid,
+	constants.OrderStatusPartiallyShipped,
+	constants.OrderStatusShipped,
+	constants.OrderStatusDelivered,
+	constants.OrderStatusCancelled,
+	constants.OrderStatusRefunder,
+	constants.OrderStatusFailed,
+}
+
+type AnalyticsService struct {
+	AnalyticsRepo interfaces.AnalyticsRepository
+	Config        config.AnalyticsConfig
+	location      *time.Location
+}
+
+func NewAnalyticsService(analyticsRepo interfaces.AnalyticsRepository, cfg config.AnalyticsConfig) *AnalyticsService {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		location = time.UTC
+		cfg.Timezone = "UTC"
+	}
+	return &AnalyticsService{
+		AnalyticsRepo: analyticsRepo,
+		Config:        cfg,
+		location:      location,
+	}
+}
+
+// StartRefresher keeps the sales rollups up to date in the background.
+func (s *AnalyticsService) StartRefresher() {
+	runEvery(s.Config.RefreshInterval, "sales rollup refresh", s.Refresh)
+}
+
+// Refresh rebuilds the rollups for the lookback window, which covers orders
+// whose status or refunds changed since they were placed. Orders older than
+// the rollups, as on the first refresh, are backfilled first by rebuilding
+// everything.
+func (s *AnalyticsService) Refresh(ctx context.Context) error {
+	since := time.Now().In(s.location).Add(-s.Config.Lookback)
+	backfill, err := s.AnalyticsRepo.NeedsSalesBackfill(ctx, s.Config.Timezone)
+	if err != nil {
+		return err
+	}
+	if backfill {
+		log.Printf("Backfilling sales rollups from the first order")
+		since = time.Time{}
+	}
+	return s.AnalyticsRepo.RefreshSalesRollups(ctx, since, s.Config.Timezone)
+}
+
+// ParseRange reads an inclusive date range, defaulting to the last 30 days.
+func (s *AnalyticsService) ParseRange(fromValue, toValue string) (time.Time, time.Time, error) {
+	today := time.Now().In(s.location)
+	to := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, s.location)
+	from := to.AddDate(0, 0, -29)
+
+	var err error
+	if toValue != "" {
+		if to, err = time.ParseInLocation("2006-01-02", toValue, s.location); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateRange
+		}
+		if fromValue == "" {
+			from = to.AddDate(0, 0, -29)
+		}
+	}
+	if fromValue != "" {
+		if from, err = time.ParseInLocation("2006-01-02", fromValue, s.location); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateRange
+		}
+	}
+	if to.Before(from) || daysBetween(from, to) > maxAnalyticsRange {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	return from, to, nil
+}
+
+// GetSalesReport returns revenue, orders and AOV for the range, bucketed by
+// interval. compare may be "previous" for the period of equal length just
+// before, or "year" for the same dates a year earlier.
+func (s *AnalyticsService) GetSalesReport(ctx context.Context, from, to time.Time, interval, compare string) (*models.SalesComparison, error) {
+	switch interval {
+	case "day", "week", "month":
+	default:
+		return nil, ErrInvalidInterval
+	}
+
+	current, err := s.salesReport(ctx, from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.SalesComparison{Current: *current}
+
+	var prevFrom, prevTo time.Time
+	switch compare {
+	case "":
+		return result, nil
+	case "previous":
+		days := daysBetween(from, to) + 1
+		prevFrom, prevTo = from.AddDate(0, 0, -days), from.AddDate(0, 0, -1)
+	case "year":
+		prevFrom, prevTo = from.AddDate(-1, 0, 0), to.AddDate(-1, 0, 0)
+	default:
+		return nil, ErrInvalidCompare
+	}
+
+	previous, err := s.salesReport(ctx, prevFrom, prevTo, interval)
+	if err != nil {
+		return nil, err
+	}
+	result.Previous = previous
+	result.Change = map[string]float64{
+		"revenue":    percentChange(previous.Summary.Revenue.Major(), current.Summary.Revenue.Major()),
+		"orders":     percentChange(float64(previous.Summary.Orders), float64(current.Summary.Orders)),
+		"aov":        percentChange(previous.Summary.AOV.Major(), current.Summary.AOV.Major()),
+		"refundRate": percentChange(previous.Summary.RefundRate, current.Summary.RefundRate),
+	}
+	return result, nil
+}
+
+func (s *AnalyticsService) salesReport(ctx context.Context, from, to time.Time, interval string) (*models.SalesReport, error) {
+	summary, err := s.AnalyticsRepo.GetSalesSummary(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	summary.AOV = models.NewMoney(0, summary.Revenue.Currency)
+	if summary.Orders > 0 {
+		summary.AOV = summary.Revenue.Div(summary.Orders)
+	}
+	if summary.Revenue.IsPositive() {
+		summary.RefundRate = roundRate(float64(summary.Refunds.Amount) / float64(summary.Revenue.Amount) * 100)
+	}
+
+	series, err := s.AnalyticsRepo.GetSalesSeries(ctx, from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+	for i := range series {
+		series[i].AOV = models.NewMoney(0, series[i].Revenue.Currency)
+		if series[i].Orders > 0 {
+			series[i].AOV = series[i].Revenue.Div(series[i].Orders)
+		}
+	}
+
+	return &models.SalesReport{
+		From:     from,
+		To:       to,
+		Interval: interval,
+		Summary:  summary,
+		Series:   series,
+	}, nil
+}
+
+func (s *AnalyticsService) GetTopProducts(ctx context.Context, from, to time.Time, limit int) ([]models.TopProduct, error) {
+	return s.AnalyticsRepo.GetTopProducts(ctx, from, to, limit)
+}
+
+func (s *AnalyticsService) GetTopCategories(ctx context.Context, from, to time.Time, limit int) ([]models.TopCategory, error) {
+	return s.AnalyticsRepo.GetTopCategories(ctx, from, to, limit)
+}
+
+// GetStatusFunnel counts the orders placed in the range by current status,
+// listing every status even when none are in it.
+func (s *AnalyticsService) GetStatusFunnel(ctx context.Context, from, to time.Time) ([]models.StatusCount, error) {
+	counts, err := s.AnalyticsRepo.GetStatusCounts(ctx, from, to, s.Config.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	byStatus := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		byStatus[c.Status] = c.Count
+	}
+	funnel := make([]models.StatusCount, 0, len(funnelStatuses))
+	for _, status := range funnelStatuses {
+		funnel = append(funnel, models.StatusCount{Status: status, Count: byStatus[status]})
+	}
+	return funnel, nil
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// percentChange returns the change from before to after as a percentage. It
+// is zero when there is nothing to compare against.
+func percentChange(before, after float64) float64 {
+	if before == 0 {
+		return 0
+	}
+	return roundRate((after - before) / before * 100)
+}
+
+// roundRate rounds a rate, such as a percentage or units sold per day, to two
+// decimal places. Amounts of money are rounded by models.Money instead.
+func roundRate(rate float64) float64 {
+	return math.Round(rate*100) / 100
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+// rollupRepo records the day the rollups were rebuilt from.
+type rollupRepo struct {
+	interfaces.AnalyticsRepository
+	backfill bool
+	since    time.Time
+}
+
+func (r *rollupRepo) NeedsSalesBackfill(ctx context.Context, timezone string) (bool, error) {
+	return r.backfill, nil
+}
+
+func (r *rollupRepo) RefreshSalesRollups(ctx context.Context, since time.Time, timezone string) error {
+	r.since = since
+	return nil
+}
+
+func TestRefreshBackfillsOlderOrders(t *testing.T) {
+	cfg := config.AnalyticsConfig{Timezone: "UTC", Lookback: 720 * time.Hour}
+
+	repo := &rollupRepo{}
+	assert.NoError(t, NewAnalyticsService(repo, cfg).Refresh(context.Background()))
+	assert.WithinDuration(t, time.Now().Add(-cfg.Lookback), repo.since, time.Minute)
+
+	repo = &rollupRepo{backfill: true}
+	assert.NoError(t, NewAnalyticsService(repo, cfg).Refresh(context.Background()))
+	assert.True(t, repo.since.IsZero())
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
 )
 
+var ErrPhoneMismatch = errors.New("phone number does not match the account")
+
 type AuthService struct {
 	UserRepo interfaces.UserRepository
 }
@@ -50,6 +52,7 @@ func (s *AuthService) RegisterUser(user *models.User) error {
 
 	user.ID = primitive.NewObjectID()
 	user.Password = string(hashedPassword)
+	user.PhoneVerified = false
 	user.CreatedAt = time.Now()
 
 	return s.UserRepo.CreateUser(user)
@@ -94,10 +97,49 @@ func (s *AuthService) UpdateUser(id string, updated *models.User) error {
 		"address":   updated.Address,
 		"updatedAt": time.Now(),
 	}
+
+	// A changed phone number has to be verified again before it can be
+	// used for cash on delivery.
+	existing, err := s.UserRepo.GetUserByID(objID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Phone != updated.Phone {
+		updateData["phoneVerified"] = false
+	}
 	return s.UserRepo.UpdateUser(objID, updateData)
 
 }
 
+// VerifyPhone marks a customer's phone number verified once an admin has
+// confirmed it with them, which makes them eligible for cash on delivery.
+func (s *AuthService) VerifyPhone(id string, req models.PhoneVerificationRequest) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	user, err := s.UserRepo.GetUserByID(objID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	phone := strings.TrimSpace(req.Phone)
+	if phone == "" || phone != user.Phone {
+		return ErrPhoneMismatch
+	}
+	verified, err := s.UserRepo.VerifyPhone(objID, phone)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return ErrPhoneMismatch
+	}
+	return nil
+}
+
 func (s *AuthService) DeleteUser(id string) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -106,6 +148,63 @@ func (s *AuthService) DeleteUser(id string) error {
 	return s.UserRepo.DeleteUser(objID)
 }
 
+// GetAddresses returns the user's saved addresses.
+func (s *AuthService) GetAddresses(id string) ([]models.Address, error) {
+	user, err := s.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if user.Addresses == nil {
+		return []models.Address{}, nil
+	}
+	return user.Addresses, nil
+}
+
+// AddAddress validates an address and saves it to the user's address book,
+// as the default if it is marked so.
+func (s *AuthService) AddAddress(id string, address models.Address) (*models.Address, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := snapshotAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	saved.ID = primitive.NewObjectID().Hex()
+	if err := s.UserRepo.AddAddress(objID, *saved); err != nil {
+		return nil, err
+	}
+	if address.Default {
+		if err := s.UserRepo.SetDefaultAddress(objID, saved.ID); err != nil {
+			return nil, err
+		}
+		saved.Default = true
+	}
+	return saved, nil
+}
+
+// SetDefaultAddress makes a saved address the one checkout ships to when the
+// request names none.
+func (s *AuthService) SetDefaultAddress(id, addressID string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	return s.UserRepo.SetDefaultAddress(objID, addressID)
+}
+
+func (s *AuthService) DeleteAddress(id, addressID string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	return s.UserRepo.RemoveAddress(objID, addressID)
+}
+
 func (s *AuthService) BootstrapAdmin() error {
 	adminEmail := os.Getenv("ADMIN_EMAIL")
 	adminPassword := os.Getenv("ADMIN_PASSWORD")
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Shrey-Yash/Masked11/config"
+)
+
+// BlobStore keeps uploaded files under slash-separated keys and returns the
+// public URL each one is served from.
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStore returns the store selected by cfg.Store.
+func NewBlobStore(cfg config.MediaConfig) BlobStore {
+	if cfg.Store == "s3" {
+		return &S3BlobStore{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PublicURL: cfg.S3PublicURL,
+		}
+	}
+	return &LocalBlobStore{Dir: cfg.LocalDir, BaseURL: cfg.BaseURL}
+}
+
+// LocalBlobStore writes files under Dir. The server exposes Dir at BaseURL.
+type LocalBlobStore struct {
+	Dir     string
+	BaseURL string
+}
+
+func (s *LocalBlobStore) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", err
+	}
+
+	// Write to a temporary file first so readers never see a partial image.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return strings.TrimRight(s.BaseURL, "/") + "/" + key, nil
+}
+
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps key onto a file under Dir, refusing keys that would escape it.
+func (s *LocalBlobStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || clean != "/"+key {
+		return "", errors.New("invalid blob key: " + key)
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// s3Request is what the fake S3 server saw of a request, with the signature
+// it expected for it.
+type s3Request struct {
+	method, path, contentType string
+	body                      []byte
+	authorization, expected   string
+}
+
+// verifySigV4 recomputes the Signature Version 4 Authorization header of a
+// request as the server receives it.
+func verifySigV4(r *http.Request, body []byte, accessKey, secretKey, region string) string {
+	auth := r.Header.Get("Authorization")
+	start := strings.Index(auth, "SignedHeaders=") + len("SignedHeaders=")
+	signedHeaders := auth[start : start+strings.Index(auth[start:], ",")]
+
+	var canonicalHeaders strings.Builder
+	for _, name := range strings.Split(signedHeaders, ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	scope := amzDate[:8] + "/" + region + "/s3/aws4_request"
+	canonicalRequest := r.Method + "\n" + r.URL.EscapedPath() + "\n" + r.URL.RawQuery + "\n" +
+		canonicalHeaders.String() + "\n" + signedHeaders + "\n" + sha256Hex(body)
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{amzDate[:8], region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return "AWS4-HMAC-SHA256 Credential=" + accessKey + "/" + scope + ", SignedHeaders=" + signedHeaders +
+		", Signature=" + hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func newFakeS3(t *testing.T, status int) (*httptest.Server, *[]s3Request) {
+	var seen []s3Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, sha256Hex(body), r.Header.Get("X-Amz-Content-Sha256"))
+		seen = append(seen, s3Request{
+			method:        r.Method,
+			path:          r.URL.Path,
+			contentType:   r.Header.Get("Content-Type"),
+			body:          body,
+			authorization: r.Header.Get("Authorization"),
+			expected:      verifySigV4(r, body, "AKIDEXAMPLE", "secret", "ap-south-1"),
+		})
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			io.WriteString(w, "<Error><Code>AccessDenied</Code></Error>\n")
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &seen
+}
+
+func TestS3BlobStoreSignsRequests(t *testing.T) {
+	server, seen := newFakeS3(t, http.StatusOK)
+	s := &S3BlobStore{
+		Endpoint:  server.URL + "/",
+		Region:    "ap-south-1",
+		Bucket:    "masked11",
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "secret",
+		Client:    server.Client(),
+	}
+
+	url, err := s.Put(context.Background(), "products/p1/large.jpg", "image/jpeg", []byte("jpeg"))
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/masked11/products/p1/large.jpg", url)
+	assert.NoError(t, s.Delete(context.Background(), "products/p1/large.jpg"))
+
+	if assert.Len(t, *seen, 2) {
+		put, del := (*seen)[0], (*seen)[1]
+		assert.Equal(t, http.MethodPut, put.method)
+		assert.Equal(t, "/masked11/products/p1/large.jpg", put.path)
+		assert.Equal(t, "image/jpeg", put.contentType)
+		assert.Equal(t, []byte("jpeg"), put.body)
+		assert.Contains(t, put.authorization, "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date,")
+		assert.Equal(t, put.expected, put.authorization)
+
+		assert.Equal(t, http.MethodDelete, del.method)
+		assert.Contains(t, del.authorization, "SignedHeaders=host;x-amz-content-sha256;x-amz-date,")
+		assert.Equal(t, del.expected, del.authorization)
+	}
+
+	s.PublicURL = "https://cdn.example.com/"
+	url, err = s.Put(context.Background(), "products/p1/thumbnail.jpg", "image/jpeg", []byte("jpeg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/products/p1/thumbnail.jpg", url)
+}
+
+func TestS3BlobStoreReportsErrors(t *testing.T) {
+	server, _ := newFakeS3(t, http.StatusForbidden)
+	s := &S3BlobStore{Endpoint: server.URL, Region: "ap-south-1", Bucket: "masked11", AccessKey: "AKIDEXAMPLE", SecretKey: "secret"}
+
+	_, err := s.Put(context.Background(), "products/p1/large.jpg", "image/jpeg", []byte("jpeg"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "403 Forbidden")
+		assert.Contains(t, err.Error(), "AccessDenied</Code></Error>")
+	}
+}
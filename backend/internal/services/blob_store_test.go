@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalBlobStorePut(t *testing.T) {
+	dir := t.TempDir()
+	s := &LocalBlobStore{Dir: filepath.Join(dir, "media"), BaseURL: "https://cdn.example.com/media/"}
+
+	url, err := s.Put(context.Background(), "products/p1/large.jpg", "image/jpeg", []byte("jpeg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/media/products/p1/large.jpg", url)
+	data, err := os.ReadFile(filepath.Join(dir, "media", "products", "p1", "large.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("jpeg"), data)
+
+	assert.NoError(t, s.Delete(context.Background(), "products/p1/large.jpg"))
+	assert.NoError(t, s.Delete(context.Background(), "products/p1/large.jpg"), "deleting a missing blob")
+}
+
+func TestLocalBlobStoreRejectsEscapingKeys(t *testing.T) {
+	dir := t.TempDir()
+	s := &LocalBlobStore{Dir: filepath.Join(dir, "media"), BaseURL: "/media"}
+
+	keys := []string{
+		"",
+		"../outside.jpg",
+		"products/../../outside.jpg",
+		"/etc/outside.jpg",
+		"products//large.jpg",
+		"products/./large.jpg",
+		"products/",
+	}
+	for _, key := range keys {
+		_, err := s.Put(context.Background(), key, "image/jpeg", []byte("jpeg"))
+		assert.Error(t, err, key)
+		assert.Error(t, s.Delete(context.Background(), key), key)
+	}
+	_, err := os.Stat(filepath.Join(dir, "outside.jpg"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// Carrier is the boundary to a courier's tracking API. Implementations turn
+// the courier's own statuses into the ShipmentStatus constants.
+type Carrier interface {
+	Name() string
+	Track(ctx context.Context, trackingNumber string) ([]models.TrackingEvent, error)
+}
+
+// ManualCarrier is used for couriers without an integration. It reports no
+// events of its own; admins record progress by hand instead.
+type ManualCarrier struct{}
+
+func (ManualCarrier) Name() string {
+	return "manual"
+}
+
+func (ManualCarrier) Track(ctx context.Context, trackingNumber string) ([]models.TrackingEvent, error) {
+	return nil, nil
+}
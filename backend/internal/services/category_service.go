@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrInvalidCategory  = errors.New("category needs a name and a parent that exists outside its own subtree")
+	ErrCategoryInUse    = errors.New("category still has subcategories or products")
+)
+
+type CategoryService struct {
+	CategoryRepo interfaces.CategoryRepository
+	ProductRepo  interfaces.ProductRepository
+}
+
+func NewCategoryService(categoryRepo interfaces.CategoryRepository, productRepo interfaces.ProductRepository) *CategoryService {
+	return &CategoryService{
+		CategoryRepo: categoryRepo,
+		ProductRepo:  productRepo,
+	}
+}
+
+// ListCategories returns categories in display order as a flat list. Hidden
+// categories are only included for admins.
+func (s *CategoryService) ListCategories(ctx context.Context, includeHidden bool) ([]*models.Category, error) {
+	return s.CategoryRepo.ListCategories(ctx, !includeHidden)
+}
+
+// GetTree returns the visible categories nested under their parents. A
+// hidden category takes its whole subtree out of the tree.
+func (s *CategoryService) GetTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	categories, err := s.CategoryRepo.ListCategories(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[primitive.ObjectID]*models.CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &models.CategoryNode{Category: *category, Children: []*models.CategoryNode{}}
+	}
+
+	roots := []*models.CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID == nil {
+			roots = append(roots, node)
+		} else if parent, ok := nodes[*category.ParentID]; ok {
+			// Under a hidden parent the node is never attached, and neither is
+			// anything beneath it.
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	return roots, nil
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, req models.CategoryRequest) (*models.Category, error) {
+	now := time.Now()
+	category := &models.Category{
+		Visible:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyCategoryRequest(ctx, category, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.CategoryRepo.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory replaces a category's details. Moving it under a new parent
+// carries its subcategories and products along; renaming it renames the
+// category on its products.
+func (s *CategoryService) UpdateCategory(ctx context.Context, id string, req models.CategoryRequest) (*models.Category, error) {
+	category, err := s.findCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previousName := category.Name
+	if err := s.applyCategoryRequest(ctx, category, req); err != nil {
+		return nil, err
+	}
+	category.UpdatedAt = time.Now()
+
+	if err := s.CategoryRepo.UpdateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	if category.Name != previousName {
+		if err := s.ProductRepo.RenameCategory(ctx, category); err != nil {
+			return nil, err
+		}
+	}
+	return category, nil
+}
+
+// DeleteCategory removes an empty category. Subcategories and products have
+// to be moved elsewhere first.
+func (s *CategoryService) DeleteCategory(ctx context.Context, id string) error {
+	category, err := s.findCategory(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	children, err := s.CategoryRepo.CountChildren(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	products, err := s.ProductRepo.CountProductsInCategory(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	if children > 0 || products > 0 {
+		return ErrCategoryInUse
+	}
+
+	return s.CategoryRepo.DeleteCategory(ctx, category.ID)
+}
+
+// MigrateProductCategories creates a root category for every free-text
+// category still found on products and links those products to it. Names
+// that slugify the same way share one category.
+func (s *CategoryService) MigrateProductCategories(ctx context.Context) error {
+	names, err := s.ProductRepo.GetUnlinkedCategoryNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		slug := utils.Slugify(name)
+		if slug == "" {
+			log.Printf("Skipping category %q: it has no usable slug", name)
+			continue
+		}
+
+		category, err := s.CategoryRepo.GetCategoryBySlug(ctx, slug)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			now := time.Now()
+			category = &models.Category{
+				Name:      strings.TrimSpace(name),
+				Slug:      slug,
+				Visible:   true,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			err = s.CategoryRepo.CreateCategory(ctx, category)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.ProductRepo.LinkCategory(ctx, name, category); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *CategoryService) findCategory(ctx context.Context, id string) (*models.Category, error) {
+	category, err := s.CategoryRepo.GetCategoryByID(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+func (s *CategoryService) applyCategoryRequest(ctx context.Context, category *models.Category, req models.CategoryRequest) error {
+	if err := validate.Struct(req); err != nil {
+		return ErrInvalidCategory
+	}
+
+	slug := req.Slug
+	if slug == "" {
+		slug = req.Name
+	}
+	category.Slug = utils.Slugify(slug)
+	if category.Slug == "" {
+		return ErrInvalidCategory
+	}
+
+	category.ParentID = nil
+	if req.ParentID != "" {
+		parent, err := s.findCategory(ctx, req.ParentID)
+		if errors.Is(err, ErrCategoryNotFound) {
+			return ErrInvalidCategory
+		}
+		if err != nil {
+			return err
+		}
+		if !category.ID.IsZero() {
+			subtree, err := s.CategoryRepo.GetDescendantIDs(ctx, category.ID, false)
+			if err != nil {
+				return err
+			}
+			for _, id := range subtree {
+				if id == parent.ID {
+					return ErrInvalidCategory
+				}
+			}
+		}
+		category.ParentID = &parent.ID
+	}
+
+	category.Name = strings.TrimSpace(req.Name)
+	category.Description = req.Description
+	category.Image = req.Image
+	category.SortOrder = req.SortOrder
+	if req.Visible != nil {
+		category.Visible = *req.Visible
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+// fakeCategoryRepo serves a category tree from memory.
+type fakeCategoryRepo struct {
+	interfaces.CategoryRepository
+	categories map[primitive.ObjectID]*models.Category
+}
+
+func (r *fakeCategoryRepo) GetCategoryByID(ctx context.Context, id string) (*models.Category, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	category, ok := r.categories[objID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	copied := *category
+	return &copied, nil
+}
+
+func (r *fakeCategoryRepo) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	for _, c := range r.categories {
+		if c.Slug == slug {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *fakeCategoryRepo) GetDescendantIDs(ctx context.Context, id primitive.ObjectID, visibleOnly bool) ([]primitive.ObjectID, error) {
+	ids := []primitive.ObjectID{id}
+	for childID, c := range r.categories {
+		if c.ParentID != nil && *c.ParentID == id {
+			below, _ := r.GetDescendantIDs(ctx, childID, visibleOnly)
+			ids = append(ids, below...)
+		}
+	}
+	return ids, nil
+}
+
+func TestApplyCategoryRequestRejectsCycles(t *testing.T) {
+	// men > shirts > tees, and a separate sale root.
+	men, shirts, tees, sale := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	repo := &fakeCategoryRepo{categories: map[primitive.ObjectID]*models.Category{
+		men:    {ID: men, Name: "Men"},
+		shirts: {ID: shirts, Name: "Shirts", ParentID: &men},
+		tees:   {ID: tees, Name: "Tees", ParentID: &shirts},
+		sale:   {ID: sale, Name: "Sale"},
+	}}
+	s := &CategoryService{CategoryRepo: repo}
+
+	cases := map[string]struct {
+		category primitive.ObjectID
+		parent   string
+		err      error
+	}{
+		"under itself":          {shirts, shirts.Hex(), ErrInvalidCategory},
+		"under its child":       {shirts, tees.Hex(), ErrInvalidCategory},
+		"under its grandchild":  {men, tees.Hex(), ErrInvalidCategory},
+		"under another root":    {shirts, sale.Hex(), nil},
+		"up to its grandparent": {tees, men.Hex(), nil},
+		"to the top level":      {tees, "", nil},
+		"under a missing one":   {tees, primitive.NewObjectID().Hex(), ErrInvalidCategory},
+		"under a malformed ID":  {tees, "shirts", ErrInvalidCategory},
+		// A new category has no subtree yet, so any existing parent will do.
+		"new under a leaf": {primitive.NilObjectID, tees.Hex(), nil},
+	}
+	for name, tc := range cases {
+		category := &models.Category{ID: tc.category}
+		err := s.applyCategoryRequest(context.Background(), category, models.CategoryRequest{Name: "Moved", ParentID: tc.parent})
+		assert.Equal(t, tc.err, err, name)
+		if tc.err == nil && tc.parent != "" {
+			assert.Equal(t, tc.parent, category.ParentID.Hex(), name)
+		}
+		if tc.err == nil && tc.parent == "" {
+			assert.Nil(t, category.ParentID, name)
+		}
+	}
+}
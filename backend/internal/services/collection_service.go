@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+var (
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrInvalidCollection  = errors.New("manual collections need existing products and rule collections need valid rules")
+)
+
+type CollectionService struct {
+	CollectionRepo interfaces.CollectionRepository
+	ProductRepo    interfaces.ProductRepository
+	CategoryRepo   interfaces.CategoryRepository
+}
+
+func NewCollectionService(
+	collectionRepo interfaces.CollectionRepository,
+	productRepo interfaces.ProductRepository,
+	categoryRepo interfaces.CategoryRepository,
+) *CollectionService {
+	return &CollectionService{
+		CollectionRepo: collectionRepo,
+		ProductRepo:    productRepo,
+		CategoryRepo:   categoryRepo,
+	}
+}
+
+// ListCollections returns collections by title. Hidden collections are only
+// included for admins.
+func (s *CollectionService) ListCollections(ctx context.Context, includeHidden bool) ([]*models.Collection, error) {
+	return s.CollectionRepo.ListCollections(ctx, !includeHidden)
+}
+
+// GetCollectionProducts returns a visible collection with one page of its
+// products. Manual collections keep their curated order; rule collections
+// are evaluated now, against the current catalog.
+func (s *CollectionService) GetCollectionProducts(ctx context.Context, slug string, page, limit int) (*models.Collection, []*models.Product, int64, error) {
+	collection, err := s.CollectionRepo.GetCollectionBySlug(ctx, strings.ToLower(slug))
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && !collection.Visible) {
+		return nil, nil, 0, ErrCollectionNotFound
+	}
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if collection.Type == constants.CollectionTypeManual {
+		products, total, err := s.manualProducts(ctx, collection, page, limit)
+		return collection, products, total, err
+	}
+
+	filters := map[string]interface{}{
+		"sortBy":    collection.SortBy,
+		"sortOrder": collection.SortOrder,
+		"page":      page,
+		"limit":     limit,
+	}
+	if collection.Rules.CategoryID != nil {
+		categoryIDs, err := s.CategoryRepo.GetDescendantIDs(ctx, *collection.Rules.CategoryID, true)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, 0, err
+		}
+		// A deleted category matches nothing rather than everything.
+		filters["categoryIds"] = append([]primitive.ObjectID{}, categoryIDs...)
+	}
+	products, total, err := s.ProductRepo.GetProductsByRules(ctx, collection.Rules, filters)
+	return collection, products, total, err
+}
+
+func (s *CollectionService) CreateCollection(ctx context.Context, req models.CollectionRequest) (*models.Collection, error) {
+	now := time.Now()
+	collection := &models.Collection{
+		Visible:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyCollectionRequest(ctx, collection, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.CollectionRepo.CreateCollection(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (s *CollectionService) UpdateCollection(ctx context.Context, id string, req models.CollectionRequest) (*models.Collection, error) {
+	collection, err := s.CollectionRepo.GetCollectionByID(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return nil, ErrCollectionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyCollectionRequest(ctx, collection, req); err != nil {
+		return nil, err
+	}
+	collection.UpdatedAt = time.Now()
+
+	if err := s.CollectionRepo.UpdateCollection(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (s *CollectionService) DeleteCollection(ctx context.Context, id string) error {
+	if err := s.CollectionRepo.DeleteCollection(ctx, id); err != nil {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+// manualProducts pages through a manual collection in its curated order,
+// skipping products that have since been deleted or are not live.
+func (s *CollectionService) manualProducts(ctx context.Context, collection *models.Collection, page, limit int) ([]*models.Product, int64, error) {
+	total := int64(len(collection.ProductIDs))
+	start := (page - 1) * limit
+	if start >= len(collection.ProductIDs) {
+		return []*models.Product{}, total, nil
+	}
+	end := start + limit
+	if end > len(collection.ProductIDs) {
+		end = len(collection.ProductIDs)
+	}
+
+	ids := make([]string, 0, end-start)
+	for _, id := range collection.ProductIDs[start:end] {
+		ids = append(ids, id.Hex())
+	}
+	found, err := s.ProductRepo.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[string]*models.Product, len(found))
+	for _, p := range found {
+		byID[p.ID.Hex()] = p
+	}
+
+	products := make([]*models.Product, 0, len(found))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok && isPublished(p) {
+			products = append(products, p)
+		}
+	}
+	return products, total, nil
+}
+
+func (s *CollectionService) applyCollectionRequest(ctx context.Context, collection *models.Collection, req models.CollectionRequest) error {
+	if err := validate.Struct(req); err != nil {
+		return ErrInvalidCollection
+	}
+
+	slug := req.Slug
+	if slug == "" {
+		slug = req.Title
+	}
+	collection.Slug = utils.Slugify(slug)
+	if collection.Slug == "" {
+		return ErrInvalidCollection
+	}
+
+	collection.ProductIDs = nil
+	collection.Rules = nil
+	collection.SortBy = ""
+	collection.SortOrder = ""
+	switch req.Type {
+	case constants.CollectionTypeManual:
+		productIDs, err := s.collectionProductIDs(ctx, req.ProductIDs)
+		if err != nil {
+			return err
+		}
+		collection.ProductIDs = productIDs
+	case constants.CollectionTypeRule:
+		if err := validateCollectionRules(req.Rules); err != nil {
+			return err
+		}
+		collection.Rules = req.Rules
+		collection.SortBy = req.SortBy
+		collection.SortOrder = req.SortOrder
+	}
+
+	collection.Title = strings.TrimSpace(req.Title)
+	collection.Description = req.Description
+	collection.Image = req.Image
+	collection.Type = req.Type
+	if req.Visible != nil {
+		collection.Visible = *req.Visible
+	}
+	return nil
+}
+
+// collectionProductIDs parses a manual collection's product list, dropping
+// repeats, and checks that every product exists.
+func (s *CollectionService) collectionProductIDs(ctx context.Context, ids []string) ([]primitive.ObjectID, error) {
+	if len(ids) == 0 {
+		return nil, ErrInvalidCollection
+	}
+
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	productIDs := make([]primitive.ObjectID, 0, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		productID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, ErrInvalidCollection
+		}
+		if seen[productID] {
+			continue
+		}
+		seen[productID] = true
+		productIDs = append(productIDs, productID)
+		unique = append(unique, id)
+	}
+
+	found, err := s.ProductRepo.GetProductsByIDs(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) != len(productIDs) {
+		return nil, ErrInvalidCollection
+	}
+	return productIDs, nil
+}
+
+// ConvertLegacyPrices converts rule price bounds stored as plain numbers,
+// from before amounts carried a currency, to minor units of the store's
+// currency.
+func (s *CollectionService) ConvertLegacyPrices(ctx context.Context) error {
+	converted, err := s.CollectionRepo.ConvertLegacyPrices(ctx, models.DefaultCurrency)
+	if converted > 0 {
+		log.Printf("Converted the price rules of %d collections to minor units", converted)
+	}
+	return err
+}
+
+// validateCollectionRules rejects missing or empty rules, which would match
+// the whole catalog, and price bands that cannot match anything.
+func validateCollectionRules(rules *models.CollectionRules) error {
+	if rules == nil || validate.Struct(rules) != nil {
+		return ErrInvalidCollection
+	}
+	if rules.MinPrice == nil && rules.MaxPrice == nil && len(rules.Tags) == 0 &&
+		rules.CategoryID == nil && !rules.InStockOnly && rules.CreatedAfter == nil && rules.NewWithinDays == 0 {
+		return ErrInvalidCollection
+	}
+	for _, bound := range []*models.Money{rules.MinPrice, rules.MaxPrice} {
+		if bound != nil && !inStoreCurrency(*bound) {
+			return ErrInvalidCollection
+		}
+	}
+	if rules.MinPrice != nil && rules.MaxPrice != nil && rules.MinPrice.Cmp(*rules.MaxPrice) > 0 {
+		return ErrInvalidCollection
+	}
+	return nil
+}
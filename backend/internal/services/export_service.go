@@ -0,0 +1,193 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+var orderExportHeader = []interface{}{
+	"Order Number", "Order ID", "Order Date", "Order Status", "Payment Method", "Customer Email", "User ID",
+	"Order Total", "COD Fee", "Currency", "Product ID", "Item", "Size", "Quantity", "Unit Price", "Line Subtotal", "Line Status",
+	"Ship City", "Ship State", "Ship Pincode",
+}
+
+type ExportService struct {
+	OrderRepo interfaces.OrderRepository
+	JobRepo   interfaces.JobRepository
+	Config    config.ExportConfig
+}
+
+func NewExportService(orderRepo interfaces.OrderRepository, jobRepo interfaces.JobRepository, cfg config.ExportConfig) *ExportService {
+	return &ExportService{
+		OrderRepo: orderRepo,
+		JobRepo:   jobRepo,
+		Config:    cfg,
+	}
+}
+
+// StartOrderExport decides how an export is delivered. Small exports return
+// a nil job and should be streamed straight to the client with
+// WriteOrderExport; exports over the configured limit run as a background
+// job whose file can be downloaded when it completes.
+func (s *ExportService) StartOrderExport(ctx context.Context, filters map[string]interface{}, format, adminID string) (*models.Job, error) {
+	if format != ExportFormatCSV && format != ExportFormatXLSX {
+		return nil, ErrInvalidExportFormat
+	}
+
+	count, err := s.OrderRepo.CountOrders(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if count <= int64(s.Config.SyncLimit) {
+		return nil, nil
+	}
+
+	params := map[string]interface{}{"format": format}
+	for k, v := range filters {
+		params[k] = v
+	}
+	job := &models.Job{
+		ID:        uuid.New(),
+		Type:      constants.JobTypeOrderExport,
+		Status:    constants.JobStatusPending,
+		Params:    params,
+		Total:     int(count),
+		CreatedBy: adminID,
+	}
+	if err := s.JobRepo.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	go s.runOrderExport(job, filters, format)
+	return job, nil
+}
+
+// WriteOrderExport writes every order line matching the filters to w.
+func (s *ExportService) WriteOrderExport(ctx context.Context, filters map[string]interface{}, format string, w io.Writer) error {
+	_, err := s.writeOrderExport(ctx, filters, format, w, nil)
+	return err
+}
+
+func (s *ExportService) writeOrderExport(ctx context.Context, filters map[string]interface{}, format string, w io.Writer, onOrder func(orders int)) (int, error) {
+	rw, err := newRowWriter(format, w)
+	if err != nil {
+		return 0, err
+	}
+	if err := rw.WriteRow(orderExportHeader); err != nil {
+		return 0, err
+	}
+
+	orders, lines := 0, 0
+	var lastOrder uuid.UUID
+	err = s.OrderRepo.StreamOrderExport(ctx, filters, func(r models.OrderExportRow) error {
+		lines++
+		if r.OrderID != lastOrder {
+			lastOrder = r.OrderID
+			orders++
+			if onOrder != nil {
+				onOrder(orders)
+			}
+		}
+		return rw.WriteRow([]interface{}{
+			r.OrderNumber, r.OrderID.String(), r.CreatedAt.Format(time.RFC3339), r.OrderStatus, r.PaymentMethod, r.CustomerEmail, r.UserID,
+			r.OrderTotal, r.CODFee, r.OrderTotal.Currency, r.ProductID, r.ItemName, r.Size, r.Quantity, r.Price, r.Subtotal, r.ItemStatus,
+			r.ShipCity, r.ShipState, r.ShipPincode,
+		})
+	})
+	if err != nil {
+		return lines, err
+	}
+	return lines, rw.Close()
+}
+
+func (s *ExportService) runOrderExport(job *models.Job, filters map[string]interface{}, format string) {
+	ctx := context.Background()
+	fail := func(err error) {
+		log.Println("Order export job", job.ID, "failed:", err)
+		now := time.Now()
+		job.Status = constants.JobStatusFailed
+		job.Error = err.Error()
+		job.CompletedAt = &now
+		if err := s.JobRepo.UpdateJob(ctx, job); err != nil {
+			log.Println("Order export job update error:", err)
+		}
+	}
+
+	job.Status = constants.JobStatusRunning
+	if err := s.JobRepo.UpdateJob(ctx, job); err != nil {
+		log.Println("Order export job update error:", err)
+	}
+
+	if err := os.MkdirAll(s.Config.Dir, 0o750); err != nil {
+		fail(err)
+		return
+	}
+	path := filepath.Join(s.Config.Dir, fmt.Sprintf("orders-%s.%s", job.ID, format))
+	file, err := os.Create(path)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	buf := bufio.NewWriter(file)
+	lines, err := s.writeOrderExport(ctx, filters, format, buf, func(orders int) {
+		// Record progress every thousand orders rather than on every row.
+		if orders%1000 == 0 {
+			job.Progress = orders
+			if err := s.JobRepo.UpdateJob(ctx, job); err != nil {
+				log.Println("Order export job update error:", err)
+			}
+		}
+	})
+	if err == nil {
+		err = buf.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		fail(err)
+		return
+	}
+
+	now := time.Now()
+	job.Status = constants.JobStatusCompleted
+	job.Progress = job.Total
+	job.FilePath = path
+	job.Result = map[string]interface{}{"rows": lines}
+	job.CompletedAt = &now
+	if err := s.JobRepo.UpdateJob(ctx, job); err != nil {
+		log.Println("Order export job update error:", err)
+	}
+}
+
+// GetJob returns a job with its download link filled in once the file is ready.
+func (s *ExportService) GetJob(ctx context.Context, id string) (*models.Job, error) {
+	job, err := s.JobRepo.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == constants.JobStatusCompleted && job.FilePath != "" {
+		job.DownloadURL = "/api/admin/exports/" + job.ID.String() + "/download"
+	}
+	return job, nil
+}
+
+// ExportFileName returns the file name offered when an export is downloaded.
+func ExportFileName(format string, at time.Time) string {
+	return "orders-" + at.Format("20060102-150405") + "." + format
+}
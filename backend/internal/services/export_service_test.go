@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+// exportOrderRepo streams a fixed set of order lines.
+type exportOrderRepo struct {
+	interfaces.OrderRepository
+	rows []models.OrderExportRow
+}
+
+func (r *exportOrderRepo) StreamOrderExport(ctx context.Context, filters map[string]interface{}, fn func(row models.OrderExportRow) error) error {
+	for _, row := range r.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestWriteOrderExportCSV(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	placed := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	line := func(orderID uuid.UUID, number, item string, price int64, quantity int) models.OrderExportRow {
+		return models.OrderExportRow{
+			OrderNumber: number, OrderID: orderID, CreatedAt: placed, OrderStatus: "PAID", PaymentMethod: "CARD",
+			OrderTotal: models.NewMoney(349800, ""), CODFee: models.NewMoney(0, ""),
+			ItemName: item, Quantity: quantity, Price: models.NewMoney(price, ""), Subtotal: models.NewMoney(price*int64(quantity), ""),
+			ItemStatus: "ACTIVE", ShipPincode: "560001",
+		}
+	}
+	repo := &exportOrderRepo{rows: []models.OrderExportRow{
+		line(first, "M11-2026-000001", "Classic Tee", 99900, 2),
+		line(first, "M11-2026-000001", "Joggers, black", 149900, 1),
+		line(second, "M11-2026-000002", "Cap", 49900, 1),
+	}}
+	s := &ExportService{OrderRepo: repo}
+
+	var buf bytes.Buffer
+	var orders []int
+	lines, err := s.writeOrderExport(context.Background(), nil, ExportFormatCSV, &buf, func(n int) { orders = append(orders, n) })
+	assert.NoError(t, err)
+	assert.Equal(t, 3, lines)
+	assert.Equal(t, []int{1, 2}, orders)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 4)
+	assert.Equal(t, "Order Number", records[0][0])
+	for _, record := range records {
+		assert.Len(t, record, len(orderExportHeader))
+	}
+
+	// One row per line, each repeating its order's details.
+	assert.Equal(t, []string{
+		"M11-2026-000001", first.String(), "2026-04-01T10:00:00Z", "PAID", "CARD", "", "",
+		models.NewMoney(349800, "").String(), models.NewMoney(0, "").String(), "INR", "", "Joggers, black", "", "1",
+		models.NewMoney(149900, "").String(), models.NewMoney(149900, "").String(), "ACTIVE", "", "", "560001",
+	}, records[2])
+	assert.Equal(t, models.NewMoney(199800, "").String(), records[1][15])
+	assert.Equal(t, "M11-2026-000002", records[3][0])
+}
+
+func TestWriteOrderExportRejectsUnknownFormat(t *testing.T) {
+	s := &ExportService{OrderRepo: &exportOrderRepo{}}
+	_, err := s.writeOrderExport(context.Background(), nil, "pdf", &bytes.Buffer{}, nil)
+	assert.Equal(t, ErrInvalidExportFormat, err)
+}
+
+func TestEscapeFormula(t *testing.T) {
+	cases := map[string]string{
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+91 98450 00000":   "'+91 98450 00000",
+		"-2+3":              "'-2+3",
+		"@SUM(A1)":          "'@SUM(A1)",
+		"\tcmd":             "'\tcmd",
+		"\rcmd":             "'\rcmd",
+		"Asha Rao":          "Asha Rao",
+		"a=b":               "a=b",
+		"":                  "",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, escapeFormula(in), in)
+	}
+	// Amounts are not customer input and keep their sign.
+	assert.Equal(t, "-1", formatExportValue(-1))
+}
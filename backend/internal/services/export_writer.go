@@ -0,0 +1,126 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+var ErrInvalidExportFormat = errors.New("export format must be csv or xlsx")
+
+// rowWriter writes tabular export data in a particular file format.
+type rowWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+func newRowWriter(format string, w io.Writer) (rowWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvRowWriter{w: csv.NewWriter(w)}, nil
+	case ExportFormatXLSX:
+		f := excelize.NewFile()
+		sw, err := f.NewStreamWriter("Sheet1")
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &xlsxRowWriter{file: f, stream: sw, out: w}, nil
+	}
+	return nil, ErrInvalidExportFormat
+}
+
+// ExportContentType returns the MIME type of an export format.
+func ExportContentType(format string) string {
+	if format == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+type csvRowWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (c *csvRowWriter) WriteRow(values []interface{}) error {
+	c.record = c.record[:0]
+	for _, v := range values {
+		c.record = append(c.record, formatExportValue(v))
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvRowWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatExportValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return escapeFormula(val)
+	case int:
+		return strconv.Itoa(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', 2, 64)
+	case models.Money:
+		return val.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// escapeFormula prefixes text that a spreadsheet would run as a formula with a
+// quote, so customer input such as a name starting with "=" opens as text.
+// Only strings are escaped; numbers and amounts are written by the export
+// itself.
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// xlsxRowWriter uses excelize's stream writer, which spills rows to a
+// temporary file rather than keeping the sheet in memory.
+type xlsxRowWriter struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	out    io.Writer
+	row    int
+}
+
+func (x *xlsxRowWriter) WriteRow(values []interface{}) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	// Amounts are written as numbers so spreadsheets can sum them.
+	for i, v := range values {
+		if m, ok := v.(models.Money); ok {
+			values[i] = m.Major()
+		}
+	}
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxRowWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withOrientation returns a JPEG of img carrying an EXIF orientation tag,
+// written in the given TIFF byte order.
+func withOrientation(t *testing.T, img image.Image, orientation uint16, order binary.ByteOrder) []byte {
+	var encoded bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	tiff := new(bytes.Buffer)
+	if order == binary.LittleEndian {
+		tiff.WriteString("II")
+	} else {
+		tiff.WriteString("MM")
+	}
+	binary.Write(tiff, order, uint16(42))
+	binary.Write(tiff, order, uint32(8))
+	binary.Write(tiff, order, uint16(1))
+	// One SHORT entry: tag, type, count, value padded to four bytes.
+	binary.Write(tiff, order, []uint16{0x0112, 3})
+	binary.Write(tiff, order, uint32(1))
+	binary.Write(tiff, order, []uint16{orientation, 0})
+	binary.Write(tiff, order, uint32(0))
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+
+	data := append([]byte{}, encoded.Bytes()[:2]...)
+	data = append(data, app1...)
+	data = append(data, segment...)
+	return append(data, encoded.Bytes()[2:]...)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	var plain bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&plain, img, nil))
+
+	cases := map[string]struct {
+		data []byte
+		want int
+	}{
+		"little endian": {withOrientation(t, img, 6, binary.LittleEndian), 6},
+		"big endian":    {withOrientation(t, img, 8, binary.BigEndian), 8},
+		"no EXIF":       {plain.Bytes(), 1},
+		"not a JPEG":    {[]byte("\x89PNG\r\n\x1a\n"), 1},
+		"truncated":     {withOrientation(t, img, 3, binary.BigEndian)[:20], 1},
+	}
+	for name, tc := range cases {
+		assert.Equal(t, tc.want, jpegOrientation(tc.data), name)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 3x2 image with its top-left pixel marked.
+	red := color.RGBA{R: 255, A: 255}
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.SetRGBA(0, 0, red)
+
+	cases := map[int]struct {
+		size   image.Point
+		marked image.Point
+	}{
+		1: {image.Pt(3, 2), image.Pt(0, 0)},
+		2: {image.Pt(3, 2), image.Pt(2, 0)},
+		3: {image.Pt(3, 2), image.Pt(2, 1)},
+		4: {image.Pt(3, 2), image.Pt(0, 1)},
+		5: {image.Pt(2, 3), image.Pt(0, 0)},
+		6: {image.Pt(2, 3), image.Pt(1, 0)},
+		7: {image.Pt(2, 3), image.Pt(1, 2)},
+		8: {image.Pt(2, 3), image.Pt(0, 2)},
+		9: {image.Pt(3, 2), image.Pt(0, 0)},
+	}
+	for orientation, tc := range cases {
+		got := orient(img, orientation)
+		assert.Equal(t, tc.size, got.Bounds().Size(), "orientation %d", orientation)
+		assert.Equal(t, red, got.RGBAAt(tc.marked.X, tc.marked.Y), "orientation %d", orientation)
+	}
+}
+
+func TestFitWithin(t *testing.T) {
+	cases := map[string]struct {
+		size image.Point
+		want image.Point
+	}{
+		"landscape": {image.Pt(4000, 3000), image.Pt(800, 600)},
+		"portrait":  {image.Pt(3000, 4000), image.Pt(600, 800)},
+		"smaller":   {image.Pt(640, 480), image.Pt(640, 480)},
+		"sliver":    {image.Pt(8000, 2), image.Pt(800, 1)},
+	}
+	for name, tc := range cases {
+		img := image.NewRGBA(image.Rectangle{Max: tc.size})
+		assert.Equal(t, tc.want, fitWithin(img, 800).Bounds().Size(), name)
+	}
+}
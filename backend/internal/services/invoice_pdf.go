@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// renderInvoicePDF lays out an A4 tax invoice for an order.
+func renderInvoicePDF(invoice *models.Invoice, order *models.Order, cfg config.InvoiceConfig) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Invoice "+invoice.Number, false)
+	pdf.SetMargins(15, 15, 15)
+	pdf.AddPage()
+
+	// Seller and invoice details
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(110, 8, tr(cfg.SellerName), "", 0, "L", false, 0, "")
+	pdf.CellFormat(70, 8, "TAX INVOICE", "", 1, "R", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 9)
+	if cfg.SellerAddress != "" {
+		pdf.MultiCell(110, 4.5, tr(cfg.SellerAddress), "", "L", false)
+	}
+	if cfg.GSTIN != "" {
+		pdf.CellFormat(110, 4.5, "GSTIN: "+cfg.GSTIN, "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	meta := [][2]string{
+		{"Invoice No.", invoice.Number},
+		{"Invoice Date", invoice.IssuedAt.Format("02 Jan 2006")},
+		{"Order No.", order.OrderNumber},
+		{"Order Date", order.CreatedAt.Format("02 Jan 2006")},
+		{"Payment", order.PaymentMethod},
+	}
+	for _, m := range meta {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(30, 5, m[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(150, 5, tr(m[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// Addresses
+	y := pdf.GetY()
+	writeInvoiceAddress(pdf, tr, 15, y, "Bill To", order.BillingAddress)
+	writeInvoiceAddress(pdf, tr, 105, y, "Ship To", order.ShippingAddress)
+	pdf.SetY(y + 32)
+
+	// Line items
+	widths := []float64{90, 20, 15, 25, 30}
+	headers := []string{"Item", "Size", "Qty", "Unit Price", "Amount"}
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(235, 235, 235)
+	for i, h := range headers {
+		align := "R"
+		if i < 2 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 7, h, "1", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, item := range order.Items {
+		if item.Status != constants.OrderItemStatusActive {
+			continue
+		}
+		pdf.CellFormat(widths[0], 6, tr(truncate(item.Name, 55)), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 6, tr(item.Size), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 6, fmt.Sprintf("%d", item.Quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, item.Price.String(), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 6, item.Subtotal.String(), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(2)
+
+	// Totals
+	totals := [][2]string{{"Subtotal", invoice.Subtotal.String()}}
+	if invoice.Discount.IsPositive() {
+		totals = append(totals, [2]string{"Discount", "-" + invoice.Discount.String()})
+	}
+	if order.CODFee.IsPositive() {
+		totals = append(totals, [2]string{"COD Fee", order.CODFee.String()})
+	}
+	totals = append(totals,
+		[2]string{fmt.Sprintf("GST included (%g%%)", cfg.TaxRate*100), invoice.Tax.String()},
+		[2]string{"Total (" + invoice.Total.Currency + ")", invoice.Total.String()},
+	)
+	for i, t := range totals {
+		style := ""
+		if i == len(totals)-1 {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 9)
+		pdf.CellFormat(150, 6, t[0], "", 0, "R", false, 0, "")
+		pdf.CellFormat(30, 6, t[1], "", 1, "R", false, 0, "")
+	}
+
+	pdf.Ln(8)
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.MultiCell(180, 4, "This is a computer generated invoice and does not require a signature.", "", "C", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeInvoiceAddress(pdf *gofpdf.Fpdf, tr func(string) string, x, y float64, title string, a *models.Address) {
+	pdf.SetXY(x, y)
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(90, 5, title, "", 2, "L", false, 0, "")
+	if a == nil {
+		return
+	}
+
+	lines := []string{a.Name, a.Line1}
+	if a.Line2 != "" {
+		lines = append(lines, a.Line2)
+	}
+	lines = append(lines,
+		fmt.Sprintf("%s, %s %s", a.City, a.State, a.Pincode),
+		a.Country,
+		"Phone: "+a.Phone,
+	)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range lines {
+		pdf.CellFormat(90, 4.5, tr(strings.TrimSpace(line)), "", 2, "L", false, 0, "")
+	}
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-3]) + "..."
+	}
+	return s
+}
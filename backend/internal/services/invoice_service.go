@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+var ErrInvoiceUnavailable = errors.New("invoice is available once the order has been paid or shipped")
+
+// invoiceableStatuses are the order statuses in which a new invoice may be
+// issued. Cash-on-delivery orders are invoiced when they are dispatched.
+var invoiceableStatuses = []string{
+	constants.OrderStatusPaid,
+	constants.OrderStatusPartiallyShipped,
+	constants.OrderStatusShipped,
+	constants.OrderStatusDelivered,
+}
+
+// indianStandardTime stands in for Asia/Kolkata when the zone database is
+// not available. India has no daylight saving, so the fixed offset is exact.
+var indianStandardTime = time.FixedZone("IST", 5*60*60+30*60)
+
+type InvoiceService struct {
+	InvoiceRepo interfaces.InvoiceRepository
+	OrderRepo   interfaces.OrderRepository
+	Config      config.InvoiceConfig
+	location    *time.Location
+}
+
+func NewInvoiceService(invoiceRepo interfaces.InvoiceRepository, orderRepo interfaces.OrderRepository, cfg config.InvoiceConfig) *InvoiceService {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		location = indianStandardTime
+		cfg.Timezone = location.String()
+	}
+	return &InvoiceService{
+		InvoiceRepo: invoiceRepo,
+		OrderRepo:   orderRepo,
+		Config:      cfg,
+		location:    location,
+	}
+}
+
+// GetInvoice returns the order's invoice, issuing it on first request. Later
+// requests always get the stored document, even if the order has changed.
+func (s *InvoiceService) GetInvoice(ctx context.Context, orderID string, isAdmin bool, userID string) (*models.Invoice, error) {
+	order, err := s.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && order.UserID != userID {
+		return nil, ErrOrderForbidden
+	}
+
+	invoice, err := s.InvoiceRepo.GetInvoiceByOrderID(ctx, orderID)
+	if err != nil || invoice != nil {
+		return invoice, err
+	}
+	if !containsStatus(invoiceableStatuses, order.Status) {
+		return nil, ErrInvoiceUnavailable
+	}
+
+	// The invoice date and its financial year follow the seller's calendar,
+	// not the server's.
+	now := time.Now().In(s.location)
+	invoice = &models.Invoice{
+		ID:            uuid.New(),
+		OrderID:       order.ID,
+		FinancialYear: financialYear(now, s.location),
+		IssuedAt:      now,
+	}
+	invoice.Subtotal, invoice.Discount, invoice.Tax, invoice.Total = s.invoiceTotals(order)
+
+	return s.InvoiceRepo.CreateInvoice(ctx, invoice, s.Config.Prefix, func(inv *models.Invoice) ([]byte, error) {
+		return renderInvoicePDF(inv, order, s.Config)
+	})
+}
+
+// invoiceTotals works out the invoice amounts from the order's active lines.
+// Prices include tax, so the tax shown is the share of the total it makes up,
+// rounded half to even.
+func (s *InvoiceService) invoiceTotals(order *models.Order) (subtotal, discount, tax, total models.Money) {
+	total = order.Total
+	subtotal = models.NewMoney(0, total.Currency)
+	for _, item := range order.Items {
+		if item.Status == constants.OrderItemStatusActive {
+			subtotal = subtotal.Add(item.Subtotal)
+		}
+	}
+	discount = models.NewMoney(0, total.Currency)
+	if d := subtotal.Add(order.CODFee).Sub(total); d.IsPositive() {
+		discount = d
+	}
+	tax = total.IncludedTax(s.Config.TaxRate)
+	return subtotal, discount, tax, total
+}
+
+// financialYear returns the Indian financial year, April to March, that t
+// falls in on the calendar of loc, e.g. "2026-27".
+func financialYear(t time.Time, loc *time.Location) string {
+	t = t.In(loc)
+	start := t.Year()
+	if t.Month() < time.April {
+		start--
+	}
+	return fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Shrey-Yash/Masked11/config"
+)
+
+func TestFinancialYear(t *testing.T) {
+	cases := map[string]struct {
+		at   time.Time
+		want string
+	}{
+		"1 April":              {time.Date(2026, 4, 1, 0, 0, 0, 0, indianStandardTime), "2026-27"},
+		"31 March":             {time.Date(2027, 3, 31, 23, 59, 59, 0, indianStandardTime), "2026-27"},
+		"31 December":          {time.Date(2026, 12, 31, 12, 0, 0, 0, indianStandardTime), "2026-27"},
+		"1 January":            {time.Date(2027, 1, 1, 0, 0, 0, 0, indianStandardTime), "2026-27"},
+		"last day of previous": {time.Date(2026, 3, 31, 12, 0, 0, 0, indianStandardTime), "2025-26"},
+		"century rollover":     {time.Date(2099, 4, 1, 0, 0, 0, 0, indianStandardTime), "2099-00"},
+		// Still 31 March in UTC, but already 1 April in India.
+		"after midnight IST":  {time.Date(2026, 3, 31, 18, 30, 0, 0, time.UTC), "2026-27"},
+		"before midnight IST": {time.Date(2026, 3, 31, 18, 29, 59, 0, time.UTC), "2025-26"},
+		// A server west of UTC is still on 31 March for longer.
+		"server in New York": {time.Date(2026, 3, 31, 15, 0, 0, 0, time.FixedZone("EDT", -4*60*60)), "2026-27"},
+	}
+	for name, tc := range cases {
+		assert.Equal(t, tc.want, financialYear(tc.at, indianStandardTime), name)
+	}
+}
+
+func TestNewInvoiceServiceTimezone(t *testing.T) {
+	s := NewInvoiceService(nil, nil, config.InvoiceConfig{Timezone: "Not/AZone"})
+	assert.Equal(t, indianStandardTime, s.location)
+	assert.Equal(t, "IST", s.Config.Timezone)
+
+	s = NewInvoiceService(nil, nil, config.InvoiceConfig{Timezone: "UTC"})
+	assert.Equal(t, time.UTC, s.location)
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	_ "golang.org/x/image/webp"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+var (
+	ErrUnsupportedImage     = errors.New("image must be a JPEG, PNG or WebP file")
+	ErrImageTooLarge        = errors.New("image is too large")
+	ErrProductImageNotFound = errors.New("product image not found")
+)
+
+// maxImagePixels caps the decoded size of an upload, so a small file that
+// claims enormous dimensions cannot exhaust memory.
+const maxImagePixels = 50_000_000
+
+// imageRenditions are the sizes every upload is resized to, by longest edge.
+var imageRenditions = []struct {
+	Name string
+	Size int
+}{
+	{"large", 1600},
+	{"medium", 800},
+	{"thumbnail", 240},
+}
+
+type MediaService struct {
+	ProductRepo interfaces.ProductRepository
+	Store       BlobStore
+	Config      config.MediaConfig
+}
+
+func NewMediaService(productRepo interfaces.ProductRepository, store BlobStore, cfg config.MediaConfig) *MediaService {
+	return &MediaService{
+		ProductRepo: productRepo,
+		Store:       store,
+		Config:      cfg,
+	}
+}
+
+// UploadProductImage validates an uploaded image, stores JPEG renditions of
+// it without the original's metadata, and links them to the product.
+func (s *MediaService) UploadProductImage(ctx context.Context, productID string, data []byte) (*models.ProductImage, error) {
+	if len(data) > s.Config.MaxUploadSize {
+		return nil, ErrImageTooLarge
+	}
+	// Trust the bytes, not the client's Content-Type.
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/webp" {
+		return nil, ErrUnsupportedImage
+	}
+	if _, err := findProduct(ctx, s.ProductRepo, productID); err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+	original, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	orientation := 1
+	if contentType == "image/jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	upload := &models.ProductImage{
+		ID:         uuid.New().String(),
+		UploadedAt: time.Now(),
+	}
+	keys := []string{}
+	// Every rendition is scaled from the upright original, so the smaller
+	// ones are not resampled twice.
+	bounds := original.Bounds()
+	upright := orient(fitWithin(original, max(bounds.Dx(), bounds.Dy())), orientation)
+	for i, rendition := range imageRenditions {
+		resized := fitWithin(upright, rendition.Size)
+		if i == 0 {
+			upload.Width, upload.Height = resized.Bounds().Dx(), resized.Bounds().Dy()
+		}
+
+		encoded, err := encodeJPEG(resized, s.Config.JPEGQuality)
+		if err != nil {
+			s.deleteBlobs(keys)
+			return nil, err
+		}
+		key := imageKey(productID, upload.ID, rendition.Name)
+		url, err := s.Store.Put(ctx, key, "image/jpeg", encoded)
+		if err != nil {
+			s.deleteBlobs(keys)
+			return nil, err
+		}
+		keys = append(keys, key)
+
+		switch rendition.Name {
+		case "large":
+			upload.Large = url
+		case "medium":
+			upload.Medium = url
+		case "thumbnail":
+			upload.Thumbnail = url
+		}
+	}
+
+	if err := s.ProductRepo.AddProductImage(ctx, productID, *upload); err != nil {
+		s.deleteBlobs(keys)
+		return nil, err
+	}
+	return upload, nil
+}
+
+// DeleteProductImage unlinks an uploaded image from its product and deletes
+// its renditions.
+func (s *MediaService) DeleteProductImage(ctx context.Context, productID, imageID string) error {
+	product, err := findProduct(ctx, s.ProductRepo, productID)
+	if err != nil {
+		return err
+	}
+
+	for _, upload := range product.Uploads {
+		if upload.ID != imageID {
+			continue
+		}
+		if err := s.ProductRepo.RemoveProductImage(ctx, productID, upload); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(imageRenditions))
+		for _, rendition := range imageRenditions {
+			keys = append(keys, imageKey(productID, upload.ID, rendition.Name))
+		}
+		s.deleteBlobs(keys)
+		return nil
+	}
+	return ErrProductImageNotFound
+}
+
+// PurgeProductImages deletes the stored renditions of every image uploaded
+// to a product that has been purged.
+func (s *MediaService) PurgeProductImages(product *models.Product) {
+	keys := make([]string, 0, len(product.Uploads)*len(imageRenditions))
+	for _, upload := range product.Uploads {
+		for _, rendition := range imageRenditions {
+			keys = append(keys, imageKey(product.ID.Hex(), upload.ID, rendition.Name))
+		}
+	}
+	s.deleteBlobs(keys)
+}
+
+func imageKey(productID, imageID, rendition string) string {
+	return fmt.Sprintf("products/%s/%s/%s.jpg", productID, imageID, rendition)
+}
+
+// deleteBlobs removes stored files on a best-effort basis; a leftover file
+// is only wasted space.
+func (s *MediaService) deleteBlobs(keys []string) {
+	for _, key := range keys {
+		if err := s.Store.Delete(context.Background(), key); err != nil {
+			log.Printf("Failed to delete blob %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+// imageProductRepo serves one product and records the images linked to it.
+type imageProductRepo struct {
+	interfaces.ProductRepository
+	images []models.ProductImage
+}
+
+func (r *imageProductRepo) GetProductByID(ctx context.Context, id string) (*models.Product, error) {
+	return &models.Product{ID: primitive.NewObjectID()}, nil
+}
+
+func (r *imageProductRepo) AddProductImage(ctx context.Context, id string, image models.ProductImage) error {
+	r.images = append(r.images, image)
+	return nil
+}
+
+// memoryBlobStore keeps blobs in a map.
+type memoryBlobStore struct {
+	blobs map[string][]byte
+}
+
+func (s *memoryBlobStore) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	s.blobs[key] = data
+	return "/media/" + key, nil
+}
+
+func (s *memoryBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s.blobs, key)
+	return nil
+}
+
+// pngHeader returns the start of a PNG that claims the given dimensions.
+// Decoding its configuration needs nothing past the header chunk.
+func pngHeader(width, height uint32) []byte {
+	ihdr := []byte("IHDR\x00\x00\x00\x00\x00\x00\x00\x00\x08\x02\x00\x00\x00")
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+
+	data := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0d")
+	data = append(data, ihdr...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestUploadProductImageCapsPixels(t *testing.T) {
+	products := &imageProductRepo{}
+	store := &memoryBlobStore{blobs: map[string][]byte{}}
+	s := &MediaService{ProductRepo: products, Store: store, Config: config.MediaConfig{MaxUploadSize: 1 << 20, JPEGQuality: 80}}
+
+	// 10000 x 5001 is just over the cap, in a file of a few dozen bytes.
+	_, err := s.UploadProductImage(context.Background(), "p1", pngHeader(10000, 5001))
+	assert.Equal(t, ErrImageTooLarge, err)
+	assert.Empty(t, store.blobs)
+	assert.Empty(t, products.images)
+}
+
+func TestUploadProductImageTurnsRenditionsUpright(t *testing.T) {
+	products := &imageProductRepo{}
+	store := &memoryBlobStore{blobs: map[string][]byte{}}
+	s := &MediaService{ProductRepo: products, Store: store, Config: config.MediaConfig{MaxUploadSize: 1 << 20, JPEGQuality: 80}}
+
+	// A landscape sensor image of a portrait shot, rotated by EXIF.
+	data := withOrientation(t, image.NewRGBA(image.Rect(0, 0, 2000, 1000)), 6, binary.BigEndian)
+	upload, err := s.UploadProductImage(context.Background(), "p1", data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 800, upload.Width)
+	assert.Equal(t, 1600, upload.Height)
+	assert.Len(t, products.images, 1)
+
+	want := map[string]image.Point{
+		imageKey("p1", upload.ID, "large"):     image.Pt(800, 1600),
+		imageKey("p1", upload.ID, "medium"):    image.Pt(400, 800),
+		imageKey("p1", upload.ID, "thumbnail"): image.Pt(120, 240),
+	}
+	assert.Len(t, store.blobs, len(want))
+	for key, size := range want {
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(store.blobs[key]))
+		if assert.NoError(t, err, key) {
+			assert.Equal(t, size, image.Pt(cfg.Width, cfg.Height), key)
+		}
+	}
+}
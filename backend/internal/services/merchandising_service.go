@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+var (
+	ErrFeaturedNotFound     = errors.New("featured entry not found")
+	ErrInvalidFeaturedEntry = errors.New("featured entry needs an existing product and an end date after its start")
+)
+
+type MerchandisingService struct {
+	MerchandisingRepo interfaces.MerchandisingRepository
+	AnalyticsRepo     interfaces.AnalyticsRepository
+	ProductRepo       interfaces.ProductRepository
+	Config            config.MerchandisingConfig
+}
+
+func NewMerchandisingService(
+	merchandisingRepo interfaces.MerchandisingRepository,
+	analyticsRepo interfaces.AnalyticsRepository,
+	productRepo interfaces.ProductRepository,
+	cfg config.MerchandisingConfig,
+) *MerchandisingService {
+	return &MerchandisingService{
+		MerchandisingRepo: merchandisingRepo,
+		AnalyticsRepo:     analyticsRepo,
+		ProductRepo:       productRepo,
+		Config:            cfg,
+	}
+}
+
+// StartRefresher keeps the best seller ranking and the co-purchase lists up
+// to date in the background.
+func (s *MerchandisingService) StartRefresher() {
+	runEvery(s.Config.BestSellerRefreshInterval, "best seller refresh", s.RefreshBestSellers)
+	runEvery(s.Config.BoughtTogetherInterval, "bought together refresh", s.RefreshBoughtTogether)
+}
+
+// RefreshBestSellers ranks products by units sold over the configured window
+// and materializes the ranking for the product listings to read.
+func (s *MerchandisingService) RefreshBestSellers(ctx context.Context) error {
+	now := time.Now()
+	velocities, err := s.AnalyticsRepo.GetSalesVelocity(ctx, now.Add(-s.Config.BestSellerWindow), s.Config.BestSellerSize)
+	if err != nil {
+		return err
+	}
+
+	rankings := make([]models.BestSeller, 0, len(velocities))
+	for _, v := range velocities {
+		productID, err := primitive.ObjectIDFromHex(v.ProductID)
+		if err != nil {
+			log.Printf("Skipping best seller with invalid product ID %q", v.ProductID)
+			continue
+		}
+		rankings = append(rankings, models.BestSeller{
+			ProductID:  productID,
+			Rank:       len(rankings) + 1,
+			Quantity:   v.Quantity,
+			PerDay:     roundRate(v.PerDay),
+			ComputedAt: now,
+		})
+	}
+	return s.MerchandisingRepo.ReplaceBestSellers(ctx, rankings)
+}
+
+// RefreshBoughtTogether mines co-purchase pairs from recent orders and stores
+// the strongest partners of each product.
+func (s *MerchandisingService) RefreshBoughtTogether(ctx context.Context) error {
+	now := time.Now()
+	pairs, err := s.AnalyticsRepo.GetCoPurchases(ctx, now.Add(-s.Config.BoughtTogetherWindow), s.Config.BoughtTogetherSize)
+	if err != nil {
+		return err
+	}
+
+	// Pairs arrive grouped by product, strongest partner first.
+	lists := []models.BoughtTogether{}
+	for _, pair := range pairs {
+		productID, err := primitive.ObjectIDFromHex(pair.ProductID)
+		if err != nil {
+			continue
+		}
+		otherID, err := primitive.ObjectIDFromHex(pair.OtherID)
+		if err != nil {
+			continue
+		}
+
+		if len(lists) == 0 || lists[len(lists)-1].ProductID != productID {
+			lists = append(lists, models.BoughtTogether{ProductID: productID, ComputedAt: now})
+		}
+		last := &lists[len(lists)-1]
+		last.Products = append(last.Products, models.BoughtTogetherEntry{ProductID: otherID, Orders: pair.Orders})
+	}
+	return s.MerchandisingRepo.ReplaceBoughtTogether(ctx, lists)
+}
+
+func (s *MerchandisingService) ListFeatured(ctx context.Context) ([]*models.FeaturedProduct, error) {
+	return s.MerchandisingRepo.ListFeatured(ctx)
+}
+
+// AddFeatured places a product on the featured list. Without a start date
+// it goes live immediately.
+func (s *MerchandisingService) AddFeatured(ctx context.Context, req models.FeaturedProductRequest, adminID string) (*models.FeaturedProduct, error) {
+	productID, err := primitive.ObjectIDFromHex(req.ProductID)
+	if err != nil {
+		return nil, ErrInvalidFeaturedEntry
+	}
+	if _, err := s.ProductRepo.GetProductByID(ctx, req.ProductID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrInvalidFeaturedEntry
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	featured := &models.FeaturedProduct{
+		ProductID: productID,
+		CreatedBy: adminID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyFeaturedRequest(featured, req, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.MerchandisingRepo.AddFeatured(ctx, featured); err != nil {
+		return nil, err
+	}
+	return featured, nil
+}
+
+// UpdateFeatured changes the position or schedule of a featured entry. The
+// product itself cannot be swapped; remove the entry and add a new one.
+func (s *MerchandisingService) UpdateFeatured(ctx context.Context, id string, req models.FeaturedProductRequest) (*models.FeaturedProduct, error) {
+	featured, err := s.MerchandisingRepo.GetFeatured(ctx, id)
+	if err != nil {
+		return nil, featuredError(err)
+	}
+
+	if req.StartsAt == nil {
+		req.StartsAt = &featured.StartsAt
+	}
+	featured.UpdatedAt = time.Now()
+	if err := applyFeaturedRequest(featured, req, featured.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := s.MerchandisingRepo.UpdateFeatured(ctx, featured); err != nil {
+		return nil, featuredError(err)
+	}
+	return featured, nil
+}
+
+func (s *MerchandisingService) RemoveFeatured(ctx context.Context, id string) error {
+	if err := s.MerchandisingRepo.RemoveFeatured(ctx, id); err != nil {
+		return featuredError(err)
+	}
+	return nil
+}
+
+// featuredError reports a missing or malformed featured entry ID as not
+// found and passes other errors through.
+func featuredError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return ErrFeaturedNotFound
+	}
+	return err
+}
+
+func applyFeaturedRequest(featured *models.FeaturedProduct, req models.FeaturedProductRequest, now time.Time) error {
+	if err := validate.Struct(req); err != nil {
+		return ErrInvalidFeaturedEntry
+	}
+
+	featured.Position = req.Position
+	featured.StartsAt = now
+	if req.StartsAt != nil {
+		featured.StartsAt = *req.StartsAt
+	}
+	featured.EndsAt = req.EndsAt
+	if featured.EndsAt != nil && !featured.EndsAt.After(featured.StartsAt) {
+		return ErrInvalidFeaturedEntry
+	}
+	return nil
+}
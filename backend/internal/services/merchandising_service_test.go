@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type fakeAnalyticsRepo struct {
+	interfaces.AnalyticsRepository
+	velocities []models.ProductVelocity
+	since      time.Time
+	limit      int
+}
+
+func (r *fakeAnalyticsRepo) GetSalesVelocity(ctx context.Context, since time.Time, limit int) ([]models.ProductVelocity, error) {
+	r.since, r.limit = since, limit
+	return r.velocities, nil
+}
+
+type fakeMerchandisingRepo struct {
+	interfaces.MerchandisingRepository
+	rankings []models.BestSeller
+}
+
+func (r *fakeMerchandisingRepo) ReplaceBestSellers(ctx context.Context, rankings []models.BestSeller) error {
+	r.rankings = rankings
+	return nil
+}
+
+func TestRefreshBestSellers(t *testing.T) {
+	tee, hat := primitive.NewObjectID(), primitive.NewObjectID()
+	analytics := &fakeAnalyticsRepo{velocities: []models.ProductVelocity{
+		{ProductID: tee.Hex(), Quantity: 90, PerDay: 3},
+		{ProductID: "legacy-sku", Quantity: 40, PerDay: 1.3333},
+		{ProductID: hat.Hex(), Quantity: 20, PerDay: 0.6666},
+	}}
+	merchandising := &fakeMerchandisingRepo{}
+	s := &MerchandisingService{
+		AnalyticsRepo:     analytics,
+		MerchandisingRepo: merchandising,
+		Config:            config.MerchandisingConfig{BestSellerWindow: 30 * 24 * time.Hour, BestSellerSize: 20},
+	}
+
+	before := time.Now()
+	assert.NoError(t, s.RefreshBestSellers(context.Background()))
+	assert.Equal(t, 20, analytics.limit)
+	assert.WithinDuration(t, before.Add(-30*24*time.Hour), analytics.since, time.Second)
+
+	// Rows with an unusable product ID are skipped without leaving a gap in
+	// the ranking.
+	assert.Len(t, merchandising.rankings, 2)
+	for i, want := range []struct {
+		id       primitive.ObjectID
+		quantity int64
+		perDay   float64
+	}{{tee, 90, 3}, {hat, 20, 0.67}} {
+		got := merchandising.rankings[i]
+		assert.Equal(t, i+1, got.Rank)
+		assert.Equal(t, want.id, got.ProductID)
+		assert.Equal(t, want.quantity, got.Quantity)
+		assert.Equal(t, want.perDay, got.PerDay)
+	}
+}
+
+func TestApplyFeaturedRequest(t *testing.T) {
+	now := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { when := now.Add(d); return &when }
+
+	cases := map[string]struct {
+		req      models.FeaturedProductRequest
+		startsAt time.Time
+		err      error
+	}{
+		"starts now":            {models.FeaturedProductRequest{Position: 1}, now, nil},
+		"open ended":            {models.FeaturedProductRequest{StartsAt: at(time.Hour)}, now.Add(time.Hour), nil},
+		"window":                {models.FeaturedProductRequest{StartsAt: at(time.Hour), EndsAt: at(2 * time.Hour)}, now.Add(time.Hour), nil},
+		"ends before it starts": {models.FeaturedProductRequest{StartsAt: at(2 * time.Hour), EndsAt: at(time.Hour)}, time.Time{}, ErrInvalidFeaturedEntry},
+		"ends as it starts":     {models.FeaturedProductRequest{EndsAt: at(0)}, time.Time{}, ErrInvalidFeaturedEntry},
+		"ended already":         {models.FeaturedProductRequest{EndsAt: at(-time.Hour)}, time.Time{}, ErrInvalidFeaturedEntry},
+		"negative position":     {models.FeaturedProductRequest{Position: -1}, time.Time{}, ErrInvalidFeaturedEntry},
+	}
+	for name, tc := range cases {
+		featured := &models.FeaturedProduct{}
+		err := applyFeaturedRequest(featured, tc.req, now)
+		assert.Equal(t, tc.err, err, name)
+		if tc.err == nil {
+			assert.Equal(t, tc.startsAt, featured.StartsAt, name)
+			assert.Equal(t, tc.req.EndsAt, featured.EndsAt, name)
+			assert.Equal(t, tc.req.Position, featured.Position, name)
+		}
+	}
+}
+
+func TestFeaturedError(t *testing.T) {
+	cases := map[error]error{
+		mongo.ErrNoDocuments:        ErrFeaturedNotFound,
+		primitive.ErrInvalidHex:     ErrFeaturedNotFound,
+		context.DeadlineExceeded:    context.DeadlineExceeded,
+		mongo.ErrClientDisconnected: mongo.ErrClientDisconnected,
+	}
+	for err, want := range cases {
+		assert.Equal(t, want, featuredError(err), err.Error())
+	}
+}
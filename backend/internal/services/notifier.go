@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+// Notifier is the boundary to whatever delivers messages to users, such as
+// email, push or an in-app inbox.
+type Notifier interface {
+	Notify(ctx context.Context, notification models.Notification) error
+}
+
+// LogNotifier writes notifications to the server log. It stands in until a
+// delivery channel is integrated.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, n models.Notification) error {
+	log.Printf("Notification %s for user %s: %s", n.Type, n.UserID, n.Title)
+	return nil
+}
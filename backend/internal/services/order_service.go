@@ -2,31 +2,86 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Shrey-Yash/Masked11/config"
 	"github.com/Shrey-Yash/Masked11/internal/constants"
 	"github.com/Shrey-Yash/Masked11/internal/models"
 	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
 )
 
+var (
+	ErrInvalidPaymentMethod = errors.New("invalid payment method")
+	ErrCODUnavailable       = errors.New("cash on delivery is not available")
+	ErrCODOrderLimit        = errors.New("order value exceeds the cash on delivery limit")
+	ErrCODPincode           = errors.New("cash on delivery is not available for this pincode")
+	ErrCODPhoneUnverified   = errors.New("a verified phone number is required for cash on delivery")
+	ErrInvalidTransition    = errors.New("invalid order status transition")
+	ErrOrderNotCancellable  = errors.New("order can no longer be cancelled")
+	ErrOrderForbidden       = errors.New("unauthorized access to this order")
+	ErrNoItemsToCancel      = errors.New("no cancellable items selected")
+	ErrAddressRequired      = errors.New("a shipping address is required")
+	ErrAddressNotFound      = errors.New("saved address not found")
+	ErrInvalidAddress       = errors.New("invalid address")
+	ErrShipmentRequired     = errors.New("orders are marked shipped by creating a shipment")
+	ErrInvalidCursor        = errors.New("invalid pagination cursor")
+	ErrOrderInvoiced        = errors.New("order has been invoiced and cannot be deleted; cancel or refund it instead")
+)
+
+var pincodePattern = regexp.MustCompile(`^[1-9][0-9]{5}$`)
+
+// codTransitions lists the statuses a cash-on-delivery order may move to from
+// each state. The courier collects payment, so COD orders never pass through
+// PAID and go straight from COD_PENDING to SHIPPED.
+var codTransitions = map[string][]string{
+	constants.OrderStatusCODPending:       {constants.OrderStatusPartiallyShipped, constants.OrderStatusShipped, constants.OrderStatusCancelled},
+	constants.OrderStatusPartiallyShipped: {constants.OrderStatusShipped},
+	constants.OrderStatusShipped:          {constants.OrderStatusDelivered},
+}
+
+// cancellableStatuses are the order statuses in which lines may still be
+// cancelled, i.e. before anything has left the warehouse.
+var cancellableStatuses = []string{
+	constants.OrderStatusPending,
+	constants.OrderStatusPaid,
+	constants.OrderStatusCODPending,
+}
+
 type OrderService struct {
-	OrderRepo interfaces.OrderRepository
-	CartRepo  interfaces.CartRepository
-	UserRepo interfaces.UserRepository
+	OrderRepo      interfaces.OrderRepository
+	CartRepo       interfaces.CartRepository
+	UserRepo       interfaces.UserRepository
+	ProductRepo    interfaces.ProductRepository
+	PaymentRepo    interfaces.PaymentRepository
+	PaymentService *PaymentService
+	PricingService *PricingService
+	CODConfig      config.CODConfig
 }
 
-func NewOrderService(orderRepo interfaces.OrderRepository, cartRepo interfaces.CartRepository, userRepo interfaces.UserRepository) *OrderService {
+func NewOrderService(orderRepo interfaces.OrderRepository, cartRepo interfaces.CartRepository, userRepo interfaces.UserRepository, productRepo interfaces.ProductRepository, paymentRepo interfaces.PaymentRepository, paymentService *PaymentService, pricingService *PricingService, codConfig config.CODConfig) *OrderService {
 	return &OrderService{
-		OrderRepo: orderRepo, 
-		CartRepo: cartRepo,
-		UserRepo: userRepo,
+		OrderRepo:      orderRepo,
+		CartRepo:       cartRepo,
+		UserRepo:       userRepo,
+		ProductRepo:    productRepo,
+		PaymentRepo:    paymentRepo,
+		PaymentService: paymentService,
+		PricingService: pricingService,
+		CODConfig:      codConfig,
 	}
 }
 
-func (s *OrderService) CreateOrder(userID string) error {
+func (s *OrderService) CreateOrder(userID string, req models.CheckoutRequest) (*models.Order, error) {
 	cart, err := s.CartRepo.GetCart(userID)
 	if err != nil || cart == nil || len(cart.Items) == 0 {
 		if err != nil {
@@ -34,15 +89,27 @@ func (s *OrderService) CreateOrder(userID string) error {
 		} else {
 			fmt.Println("Empty or nil cart")
 		}
-		return errors.New("invalid cart")
+		return nil, errors.New("invalid cart")
+	}
+
+	if req.PaymentMethod == "" {
+		req.PaymentMethod = constants.PaymentMethodOnline
+	}
+	if req.PaymentMethod != constants.PaymentMethodOnline && req.PaymentMethod != constants.PaymentMethodCOD {
+		return nil, ErrInvalidPaymentMethod
+	}
+
+	// Charge what the catalog says now; cart prices may be stale.
+	if err := s.PricingService.PriceCartItems(context.Background(), req.CustomerID, cart.Items); err != nil {
+		return nil, err
 	}
 
 	orderID := uuid.New()
 	var orderItems []models.OrderItem
-	total := 0.0
+	total := models.NewMoney(0, models.DefaultCurrency)
 
 	for _, item := range cart.Items {
-		sub := float64(item.Quantity) * item.Price
+		sub := item.Price.Mul(item.Quantity)
 		orderItems = append(orderItems, models.OrderItem{
 			ID:        uuid.New(),
 			OrderID:   orderID,
@@ -53,33 +120,327 @@ func (s *OrderService) CreateOrder(userID string) error {
 			Size:      item.Size,
 			Image:     item.Image,
 			Subtotal:  sub,
+			Status:    constants.OrderItemStatusActive,
 		})
-		total += sub
+		total = total.Add(sub)
+	}
+
+	shipping, billing, err := s.resolveCheckoutAddresses(req)
+	if err != nil {
+		return nil, err
 	}
 
 	order := &models.Order{
-		ID:        orderID,
-		UserID:    userID,
-		Status:    constants.OrderStatusPending,
-		Total:     total,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:              orderID,
+		UserID:          userID,
+		CustomerEmail:   req.CustomerEmail,
+		Status:          constants.OrderStatusPending,
+		PaymentMethod:   req.PaymentMethod,
+		Total:           total,
+		ShippingAddress: shipping,
+		BillingAddress:  billing,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
 
+	if req.PaymentMethod == constants.PaymentMethodCOD {
+		if err := s.applyCOD(order, req.CustomerID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.reserveStock(orderItems); err != nil {
+		return nil, err
+	}
+	s.snapshotCategories(orderItems)
 	if err := s.OrderRepo.CreateOrder(order, orderItems); err != nil {
-		return err
+		s.releaseStock(orderItems)
+		return nil, err
 	}
 
 	_ = s.CartRepo.DeleteCart(userID)
+	order.Items = orderItems
+	return order, nil
+}
+
+// applyCOD checks that the customer may pay cash on delivery for the order
+// and, if so, holds it in COD_PENDING with the COD fee added to its total.
+func (s *OrderService) applyCOD(order *models.Order, customerID string) error {
+	if err := s.CheckCODEligibility(customerID, order.Total, order.ShippingAddress.Pincode); err != nil {
+		return err
+	}
+	order.Status = constants.OrderStatusCODPending
+	order.CODFee = s.CODConfig.Fee
+	order.Total = order.Total.Add(s.CODConfig.Fee)
 	return nil
 }
 
+// CheckCODEligibility reports whether a customer may pay cash on delivery for
+// an order of the given value shipped to the given pincode.
+func (s *OrderService) CheckCODEligibility(customerID string, orderValue models.Money, pincode string) error {
+	if !s.CODConfig.Enabled {
+		return ErrCODUnavailable
+	}
+	if s.CODConfig.MaxOrderValue.IsPositive() && orderValue.Cmp(s.CODConfig.MaxOrderValue) > 0 {
+		return ErrCODOrderLimit
+	}
+	if !pincodePattern.MatchString(pincode) || !s.isServiceablePincode(pincode) {
+		return ErrCODPincode
+	}
+
+	objID, err := primitive.ObjectIDFromHex(customerID)
+	if err != nil {
+		return ErrCODPhoneUnverified
+	}
+	user, err := s.UserRepo.GetUserByID(objID)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Phone == "" || !user.PhoneVerified {
+		return ErrCODPhoneUnverified
+	}
+	return nil
+}
+
+// resolveCheckoutAddresses returns validated copies of the shipping and
+// billing addresses for an order, looking up saved addresses where the
+// request refers to them by ID.
+func (s *OrderService) resolveCheckoutAddresses(req models.CheckoutRequest) (*models.Address, *models.Address, error) {
+	var user *models.User
+	loadUser := func() (*models.User, error) {
+		if user == nil {
+			objID, err := primitive.ObjectIDFromHex(req.CustomerID)
+			if err != nil {
+				return nil, ErrAddressNotFound
+			}
+			if user, err = s.UserRepo.GetUserByID(objID); err != nil {
+				return nil, err
+			}
+			if user == nil {
+				return nil, ErrAddressNotFound
+			}
+		}
+		return user, nil
+	}
+	lookup := func(addressID string) (*models.Address, error) {
+		user, err := loadUser()
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range user.Addresses {
+			if a.ID == addressID {
+				return &a, nil
+			}
+		}
+		return nil, ErrAddressNotFound
+	}
+
+	shipping := req.ShippingAddress
+	if req.ShippingAddressID != "" {
+		a, err := lookup(req.ShippingAddressID)
+		if err != nil {
+			return nil, nil, err
+		}
+		shipping = a
+	}
+	if shipping == nil {
+		// Clients written before addresses were required send no address,
+		// so fall back to the one the customer has saved as their default.
+		user, err := loadUser()
+		if err != nil && !errors.Is(err, ErrAddressNotFound) {
+			return nil, nil, err
+		}
+		if user != nil {
+			shipping = defaultAddress(user.Addresses)
+		}
+	}
+	if shipping == nil {
+		return nil, nil, ErrAddressRequired
+	}
+
+	billing := req.BillingAddress
+	if req.BillingAddressID != "" {
+		a, err := lookup(req.BillingAddressID)
+		if err != nil {
+			return nil, nil, err
+		}
+		billing = a
+	}
+	if billing == nil {
+		billing = shipping
+	}
+
+	shippingCopy, err := snapshotAddress(*shipping)
+	if err != nil {
+		return nil, nil, err
+	}
+	billingCopy, err := snapshotAddress(*billing)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shippingCopy, billingCopy, nil
+}
+
+// defaultAddress returns the saved address marked as the default, or the
+// only saved address when there is just one.
+func defaultAddress(addresses []models.Address) *models.Address {
+	for i := range addresses {
+		if addresses[i].Default {
+			return &addresses[i]
+		}
+	}
+	if len(addresses) == 1 {
+		return &addresses[0]
+	}
+	return nil
+}
+
+// snapshotAddress validates an address and returns a copy suitable for
+// storing on an order.
+func snapshotAddress(a models.Address) (*models.Address, error) {
+	a.ID = ""
+	a.Default = false
+	a.Name = strings.TrimSpace(a.Name)
+	a.Phone = strings.TrimSpace(a.Phone)
+	a.Line1 = strings.TrimSpace(a.Line1)
+	a.Line2 = strings.TrimSpace(a.Line2)
+	a.City = strings.TrimSpace(a.City)
+	a.State = strings.TrimSpace(a.State)
+	a.Pincode = strings.TrimSpace(a.Pincode)
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	if a.Country == "" {
+		a.Country = "IN"
+	}
+	if err := validate.Struct(a); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	return &a, nil
+}
+
+// snapshotCategories copies each product's current category onto its order
+// line so sales can be reported by category later.
+func (s *OrderService) snapshotCategories(items []models.OrderItem) {
+	for i := range items {
+		product, err := s.ProductRepo.GetProductByID(context.Background(), items[i].ProductID)
+		if err == nil && product != nil {
+			items[i].Category = product.Category
+		}
+	}
+}
+
+// reserveStock takes the ordered quantities out of stock, undoing any
+// reservations already made if one of the products has run out.
+func (s *OrderService) reserveStock(items []models.OrderItem) error {
+	for i, item := range items {
+		if err := s.ProductRepo.AdjustStock(context.Background(), item.ProductID, -item.Quantity); err != nil {
+			s.releaseStock(items[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *OrderService) releaseStock(items []models.OrderItem) {
+	for _, item := range items {
+		if err := s.ProductRepo.AdjustStock(context.Background(), item.ProductID, item.Quantity); err != nil {
+			log.Printf("Failed to release stock for product %s: %v", item.ProductID, err)
+		}
+	}
+}
+
+func (s *OrderService) isServiceablePincode(pincode string) bool {
+	// An empty list means COD is offered everywhere we ship.
+	if len(s.CODConfig.ServiceablePincodes) == 0 {
+		return true
+	}
+	for _, p := range s.CODConfig.ServiceablePincodes {
+		if p == pincode {
+			return true
+		}
+	}
+	return false
+}
+
+// ListOrders returns a page of orders for the admin order list and the cursor
+// of the next page, which is empty on the last page.
+func (s *OrderService) ListOrders(ctx context.Context, filters map[string]interface{}) ([]models.Order, int64, string, error) {
+	sortBy, _ := filters["sortBy"].(string)
+	if cursor, _ := filters["cursor"].(string); cursor != "" {
+		value, id, err := decodeOrderCursor(cursor, sortBy)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		filters["afterValue"] = value
+		filters["afterID"] = id
+	}
+
+	// Fetch one extra row to find out whether there is a next page.
+	limit, _ := filters["limit"].(int)
+	filters["limit"] = limit + 1
+	orders, total, err := s.OrderRepo.ListOrders(ctx, filters)
+	filters["limit"] = limit
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	next := ""
+	if len(orders) > limit {
+		orders = orders[:limit]
+		next = encodeOrderCursor(orders[limit-1], sortBy)
+	}
+	return orders, total, next, nil
+}
+
+// encodeOrderCursor builds an opaque cursor from the sort value and ID of
+// the last order on a page.
+func encodeOrderCursor(order models.Order, sortBy string) string {
+	value := order.CreatedAt.UTC().Format(time.RFC3339Nano)
+	if sortBy == "total" {
+		value = strconv.FormatInt(order.Total.Amount, 10)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(value + "|" + order.ID.String()))
+}
+
+func decodeOrderCursor(cursor, sortBy string) (interface{}, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	value, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, "", ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+
+	if sortBy == "total" {
+		total, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, "", ErrInvalidCursor
+		}
+		return total, id, nil
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	return createdAt, id, nil
+}
+
 func (s *OrderService) GetOrdersByUserID(ctx context.Context, userID string) ([]models.Order, error) {
 	return s.OrderRepo.GetOrdersByUserID(ctx, userID)
 }
 
+// GetOrderByID looks an order up by its UUID or by its order number.
 func (s *OrderService) GetOrderByID(ctx context.Context, orderID string, isAdmin bool, userID string) (*models.Order, error) {
-	order, err := s.OrderRepo.GetOrderByID(ctx, orderID)
+	var order *models.Order
+	var err error
+	if _, parseErr := uuid.Parse(orderID); parseErr == nil {
+		order, err = s.OrderRepo.GetOrderByID(ctx, orderID)
+	} else {
+		order, err = s.OrderRepo.GetOrderByNumber(ctx, strings.ToUpper(strings.TrimSpace(orderID)))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -87,22 +448,229 @@ func (s *OrderService) GetOrderByID(ctx context.Context, orderID string, isAdmin
 	return order, nil
 }
 
+// UpdateOrderStatus is the admin's manual status change. Shipping statuses
+// are set by ShipmentService so every shipped order has a tracking number.
 func (s *OrderService) UpdateOrderStatus(orderID, status string) error {
-	return s.OrderRepo.UpdateOrderStatus(orderID, status)
+	switch strings.ToUpper(status) {
+	case constants.OrderStatusShipped, constants.OrderStatusPartiallyShipped:
+		return ErrShipmentRequired
+	}
+
+	order, err := s.OrderRepo.GetOrderByID(context.Background(), orderID)
+	if err != nil {
+		return err
+	}
+	return s.transitionOrder(order, status)
+}
+
+func (s *OrderService) transitionOrder(order *models.Order, status string) error {
+	orderID := order.ID.String()
+	if order.PaymentMethod != constants.PaymentMethodCOD {
+		if err := s.OrderRepo.UpdateOrderStatus(orderID, status); err != nil {
+			return err
+		}
+		if strings.EqualFold(status, constants.OrderStatusPaid) {
+			return s.PaymentService.RecordOnlinePayment(context.Background(), order, "")
+		}
+		return nil
+	}
+
+	status = strings.ToUpper(status)
+	if !isAllowedTransition(codTransitions, order.Status, status) {
+		return ErrInvalidTransition
+	}
+	if status != constants.OrderStatusDelivered {
+		return s.OrderRepo.UpdateOrderStatus(orderID, status)
+	}
+
+	// The courier hands over the cash on delivery, so that is when the
+	// payment is recorded. Reconciliation with the courier happens later.
+	now := time.Now()
+	err := s.OrderRepo.DeliverCODOrder(context.Background(), orderID, order.Status, &models.Payment{
+		ID:          uuid.New(),
+		OrderID:     order.ID,
+		Method:      constants.PaymentMethodCOD,
+		Amount:      order.Total,
+		Status:      constants.PaymentStatusCollected,
+		CollectedAt: &now,
+	})
+	if errors.Is(err, interfaces.ErrOrderStatusChanged) {
+		return ErrInvalidTransition
+	}
+	return err
 }
 
+func isAllowedTransition(transitions map[string][]string, from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteOrder removes an order and its lines. Invoiced orders are kept, as
+// their invoices must stay on record.
 func (s *OrderService) DeleteOrder(orderID string) error {
-	return s.OrderRepo.DeleteOrder(orderID)
+	if err := s.OrderRepo.DeleteOrder(orderID); err != nil {
+		if errors.Is(err, interfaces.ErrOrderInvoiced) {
+			return ErrOrderInvoiced
+		}
+		return err
+	}
+	return nil
 }
 
+// BackfillCustomerEmails copies each customer's account email onto their
+// orders placed before orders recorded it, so order search and exports can
+// find them. Orders of guests and deleted accounts are left without one.
+func (s *OrderService) BackfillCustomerEmails(ctx context.Context) error {
+	userIDs, err := s.OrderRepo.GetUserIDsWithoutEmail(ctx)
+	if err != nil {
+		return err
+	}
+
+	var filled int64
+	for _, userID := range userIDs {
+		objID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			continue
+		}
+		user, err := s.UserRepo.GetUserByID(objID)
+		if err != nil {
+			return err
+		}
+		if user == nil || user.Email == "" {
+			continue
+		}
+		n, err := s.OrderRepo.SetCustomerEmail(ctx, userID, user.Email)
+		if err != nil {
+			return err
+		}
+		filled += n
+	}
+	if filled > 0 {
+		log.Printf("Recorded customer emails on %d existing orders", filled)
+	}
+	return nil
+}
+
+// CancelOrder cancels every line of an order that has not shipped yet.
 func (s *OrderService) CancelOrder(ctx context.Context, userID string, orderID string) error {
 	order, err := s.OrderRepo.GetOrderByID(ctx, orderID)
 	if err != nil {
-		return nil
+		return err
+	}
+
+	var itemIDs []string
+	for _, item := range order.Items {
+		if item.Status == constants.OrderItemStatusActive {
+			itemIDs = append(itemIDs, item.ID.String())
+		}
 	}
+	_, _, err = s.CancelOrderItems(ctx, userID, orderID, itemIDs)
+	return err
+}
 
+// CancelOrderItems cancels individual lines of an unshipped order, puts their
+// stock back and, if the order was already paid, refunds the cancelled lines.
+// The refund is recorded in the same transaction as the cancellation and only
+// then sent, so a gateway failure leaves it pending rather than losing it.
+// It returns the updated order and the refund, if any.
+func (s *OrderService) CancelOrderItems(ctx context.Context, userID string, orderID string, itemIDs []string) (*models.Order, *models.Refund, error) {
+	order, err := s.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
 	if order.UserID != userID {
-		return errors.New("unauthorized access to cancel order")
+		return nil, nil, ErrOrderForbidden
+	}
+	if !containsStatus(cancellableStatuses, order.Status) {
+		return nil, nil, ErrOrderNotCancellable
+	}
+
+	var ids []string
+	var cancelling []models.OrderItem
+	seen := make(map[string]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		item := findOrderItem(order, id)
+		if item == nil || item.Status != constants.OrderItemStatusActive {
+			return nil, nil, ErrNoItemsToCancel
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+			cancelling = append(cancelling, *item)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil, ErrNoItemsToCancel
+	}
+
+	var refund *models.Refund
+	paid, err := s.PaymentService.PaidAmount(ctx, order)
+	if err != nil {
+		return nil, nil, err
+	}
+	if amount := cancellationRefund(paid, order.Items, cancelling); amount.IsPositive() {
+		if refund, err = s.PaymentService.NewRefund(ctx, order, amount, "cancelled items"); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := s.OrderRepo.CancelOrderItems(ctx, orderID, order.Status, ids, refund, unrecordedPayment(order)); err != nil {
+		return nil, nil, cancellationError(err)
+	}
+	s.releaseStock(cancelling)
+	if refund != nil {
+		s.PaymentService.SendRefund(ctx, order, refund)
+	}
+
+	updated, err := s.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, refund, nil
+}
+
+// cancellationRefund returns the part of paid to refund for cancelling lines
+// of an order. Each line is refunded its share of what was paid, measured
+// against every line originally ordered, so order-level adjustments are split
+// proportionally across lines. Shares are taken of the running total of
+// cancelled lines and differenced, so however each one rounds, the refunds
+// add up to exactly what was paid once every line is cancelled.
+func cancellationRefund(paid models.Money, items, cancelling []models.OrderItem) models.Money {
+	var itemsTotal, before int64
+	for _, item := range items {
+		itemsTotal += item.Subtotal.Amount
+		if item.Status == constants.OrderItemStatusCancelled {
+			before += item.Subtotal.Amount
+		}
+	}
+	after := before
+	for _, item := range cancelling {
+		after += item.Subtotal.Amount
+	}
+	return paid.MulFrac(after, itemsTotal).Sub(paid.MulFrac(before, itemsTotal))
+}
+
+// cancellationError maps a cancellation the database turned down onto the
+// service errors.
+func cancellationError(err error) error {
+	switch {
+	case errors.Is(err, interfaces.ErrOrderStatusChanged):
+		return ErrOrderNotCancellable
+	case errors.Is(err, interfaces.ErrOrderItemsChanged):
+		return ErrNoItemsToCancel
+	}
+	return refundError(err)
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, st := range statuses {
+		if st == status {
+			return true
+		}
 	}
-	return s.OrderRepo.CancelOrder(orderID)
+	return false
 }
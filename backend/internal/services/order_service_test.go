@@ -0,0 +1,376 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+// fakeUserRepo serves users from a map. Methods the tests do not need are
+// left to the embedded nil interface.
+type fakeUserRepo struct {
+	interfaces.UserRepository
+	users map[primitive.ObjectID]*models.User
+}
+
+func (r *fakeUserRepo) GetUserByID(id primitive.ObjectID) (*models.User, error) {
+	return r.users[id], nil
+}
+
+func newCODTestService() (*OrderService, map[string]string) {
+	verified, unverified, noPhone := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	repo := &fakeUserRepo{users: map[primitive.ObjectID]*models.User{
+		verified:   {ID: verified, Phone: "+919876543210", PhoneVerified: true},
+		unverified: {ID: unverified, Phone: "+919876543210"},
+		noPhone:    {ID: noPhone, PhoneVerified: true},
+	}}
+	s := &OrderService{UserRepo: repo, CODConfig: config.CODConfig{
+		Enabled:             true,
+		MaxOrderValue:       models.NewMoney(1000000, ""),
+		ServiceablePincodes: []string{"560001", "110001"},
+		Fee:                 models.NewMoney(4900, ""),
+	}}
+	return s, map[string]string{
+		"verified":   verified.Hex(),
+		"unverified": unverified.Hex(),
+		"noPhone":    noPhone.Hex(),
+	}
+}
+
+func TestCheckCODEligibility(t *testing.T) {
+	s, customers := newCODTestService()
+	cases := map[string]struct {
+		customer string
+		value    int64
+		pincode  string
+		want     error
+	}{
+		"eligible":             {customers["verified"], 250000, "560001", nil},
+		"at the limit":         {customers["verified"], 1000000, "560001", nil},
+		"over the limit":       {customers["verified"], 1000001, "560001", ErrCODOrderLimit},
+		"unserviceable":        {customers["verified"], 250000, "400001", ErrCODPincode},
+		"malformed pincode":    {customers["verified"], 250000, "56001", ErrCODPincode},
+		"unverified phone":     {customers["unverified"], 250000, "560001", ErrCODPhoneUnverified},
+		"no phone":             {customers["noPhone"], 250000, "560001", ErrCODPhoneUnverified},
+		"unknown customer":     {primitive.NewObjectID().Hex(), 250000, "560001", ErrCODPhoneUnverified},
+		"malformed customerID": {"guest", 250000, "560001", ErrCODPhoneUnverified},
+	}
+	for name, tc := range cases {
+		err := s.CheckCODEligibility(tc.customer, models.NewMoney(tc.value, ""), tc.pincode)
+		assert.Equal(t, tc.want, err, name)
+	}
+
+	s.CODConfig.Enabled = false
+	assert.Equal(t, ErrCODUnavailable, s.CheckCODEligibility(customers["verified"], models.NewMoney(100, ""), "560001"))
+
+	// With no limit and no pincode list, any value and valid pincode will do.
+	s.CODConfig = config.CODConfig{Enabled: true}
+	assert.NoError(t, s.CheckCODEligibility(customers["verified"], models.NewMoney(99999999, ""), "400001"))
+}
+
+func TestApplyCODAddsFee(t *testing.T) {
+	s, customers := newCODTestService()
+	order := &models.Order{
+		Status:          constants.OrderStatusPending,
+		Total:           models.NewMoney(999900, ""),
+		ShippingAddress: &models.Address{Pincode: "110001"},
+	}
+	assert.NoError(t, s.applyCOD(order, customers["verified"]))
+	assert.Equal(t, constants.OrderStatusCODPending, order.Status)
+	assert.Equal(t, models.NewMoney(4900, ""), order.CODFee)
+	assert.Equal(t, models.NewMoney(1004800, ""), order.Total)
+
+	// The limit applies to the goods, not the fee on top of them.
+	order = &models.Order{
+		Status:          constants.OrderStatusPending,
+		Total:           models.NewMoney(1000100, ""),
+		ShippingAddress: &models.Address{Pincode: "110001"},
+	}
+	assert.Equal(t, ErrCODOrderLimit, s.applyCOD(order, customers["verified"]))
+	assert.Equal(t, constants.OrderStatusPending, order.Status)
+	assert.Equal(t, models.NewMoney(1000100, ""), order.Total)
+}
+
+func TestCODTransitions(t *testing.T) {
+	cases := map[[2]string]bool{
+		{constants.OrderStatusCODPending, constants.OrderStatusShipped}:          true,
+		{constants.OrderStatusCODPending, constants.OrderStatusPartiallyShipped}: true,
+		{constants.OrderStatusCODPending, constants.OrderStatusCancelled}:        true,
+		{constants.OrderStatusPartiallyShipped, constants.OrderStatusShipped}:    true,
+		{constants.OrderStatusShipped, constants.OrderStatusDelivered}:           true,
+		{constants.OrderStatusCODPending, constants.OrderStatusPaid}:             false,
+		{constants.OrderStatusCODPending, constants.OrderStatusDelivered}:        false,
+		{constants.OrderStatusShipped, constants.OrderStatusCancelled}:           false,
+		{constants.OrderStatusDelivered, constants.OrderStatusShipped}:           false,
+		{constants.OrderStatusCancelled, constants.OrderStatusCODPending}:        false,
+	}
+	for step, want := range cases {
+		assert.Equal(t, want, isAllowedTransition(codTransitions, step[0], step[1]), "%s -> %s", step[0], step[1])
+	}
+}
+
+// deliveringOrderRepo records the payment a COD delivery was stored with,
+// unless the order has moved on.
+type deliveringOrderRepo struct {
+	interfaces.OrderRepository
+	status  string
+	payment *models.Payment
+}
+
+func (r *deliveringOrderRepo) DeliverCODOrder(ctx context.Context, orderID, from string, payment *models.Payment) error {
+	if from != r.status {
+		return interfaces.ErrOrderStatusChanged
+	}
+	r.payment = payment
+	return nil
+}
+
+func TestDeliverCODOrderRecordsPayment(t *testing.T) {
+	order := &models.Order{
+		ID:            uuid.New(),
+		Status:        constants.OrderStatusShipped,
+		PaymentMethod: constants.PaymentMethodCOD,
+		Total:         models.NewMoney(104900, ""),
+	}
+	// The payment repository is left nil, so the payment must go through the
+	// order repository's transaction.
+	repo := &deliveringOrderRepo{status: constants.OrderStatusShipped}
+	s := &OrderService{OrderRepo: repo}
+	assert.NoError(t, s.transitionOrder(order, "delivered"))
+	assert.Equal(t, constants.PaymentStatusCollected, repo.payment.Status)
+	assert.Equal(t, order.Total, repo.payment.Amount)
+
+	repo = &deliveringOrderRepo{status: constants.OrderStatusDelivered}
+	s = &OrderService{OrderRepo: repo}
+	assert.Equal(t, ErrInvalidTransition, s.transitionOrder(order, constants.OrderStatusDelivered))
+	assert.Nil(t, repo.payment)
+}
+
+func TestCancellationRefund(t *testing.T) {
+	line := func(subtotal int64, status string) models.OrderItem {
+		return models.OrderItem{ID: uuid.New(), Subtotal: models.NewMoney(subtotal, ""), Status: status}
+	}
+	active, cancelled := constants.OrderItemStatusActive, constants.OrderItemStatusCancelled
+
+	cases := map[string]struct {
+		paid       int64
+		items      []models.OrderItem
+		cancelling []int
+		want       int64
+	}{
+		"full price line": {
+			paid:       30000,
+			items:      []models.OrderItem{line(10000, active), line(20000, active)},
+			cancelling: []int{0},
+			want:       10000,
+		},
+		// 10% off at the order level comes off each line in proportion.
+		"order discount": {
+			paid:       27000,
+			items:      []models.OrderItem{line(10000, active), line(20000, active)},
+			cancelling: []int{1},
+			want:       18000,
+		},
+		// A third of 1.00 rounds down to 0.33...
+		"first third": {
+			paid:       100,
+			items:      []models.OrderItem{line(100, active), line(100, active), line(100, active)},
+			cancelling: []int{0},
+			want:       33,
+		},
+		// ...two thirds round up to 0.67, so the second line gets 0.34...
+		"second third": {
+			paid:       100,
+			items:      []models.OrderItem{line(100, cancelled), line(100, active), line(100, active)},
+			cancelling: []int{1},
+			want:       34,
+		},
+		// ...and the last gets what is left, so the three add up to 1.00.
+		"last third": {
+			paid:       100,
+			items:      []models.OrderItem{line(100, cancelled), line(100, cancelled), line(100, active)},
+			cancelling: []int{2},
+			want:       33,
+		},
+		"unpaid": {
+			paid:       0,
+			items:      []models.OrderItem{line(10000, active)},
+			cancelling: []int{0},
+			want:       0,
+		},
+	}
+	for name, tc := range cases {
+		var cancelling []models.OrderItem
+		for _, i := range tc.cancelling {
+			cancelling = append(cancelling, tc.items[i])
+		}
+		got := cancellationRefund(models.NewMoney(tc.paid, ""), tc.items, cancelling)
+		assert.Equal(t, models.NewMoney(tc.want, ""), got, name)
+	}
+}
+
+func TestResolveCheckoutAddressesFallsBackToDefault(t *testing.T) {
+	address := func(id, pincode string, isDefault bool) models.Address {
+		return models.Address{ID: id, Name: "Asha Rao", Phone: "9876543210", Line1: "12 MG Road", City: "Bengaluru", State: "Karnataka", Pincode: pincode, Default: isDefault}
+	}
+	single, several, noDefault, none := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	s := &OrderService{UserRepo: &fakeUserRepo{users: map[primitive.ObjectID]*models.User{
+		single:    {ID: single, Addresses: []models.Address{address("a", "560001", false)}},
+		several:   {ID: several, Addresses: []models.Address{address("a", "560001", false), address("b", "110001", true)}},
+		noDefault: {ID: noDefault, Addresses: []models.Address{address("a", "560001", false), address("b", "110001", false)}},
+		none:      {ID: none},
+	}}}
+
+	cases := map[string]struct {
+		req     models.CheckoutRequest
+		pincode string
+		err     error
+	}{
+		"only saved address":    {models.CheckoutRequest{CustomerID: single.Hex()}, "560001", nil},
+		"default address":       {models.CheckoutRequest{CustomerID: several.Hex()}, "110001", nil},
+		"named address wins":    {models.CheckoutRequest{CustomerID: several.Hex(), ShippingAddressID: "a"}, "560001", nil},
+		"no default":            {models.CheckoutRequest{CustomerID: noDefault.Hex()}, "", ErrAddressRequired},
+		"no saved addresses":    {models.CheckoutRequest{CustomerID: none.Hex()}, "", ErrAddressRequired},
+		"guest without address": {models.CheckoutRequest{CustomerID: "guest-123"}, "", ErrAddressRequired},
+	}
+	for name, tc := range cases {
+		shipping, billing, err := s.resolveCheckoutAddresses(tc.req)
+		assert.Equal(t, tc.err, err, name)
+		if tc.err == nil {
+			assert.Equal(t, tc.pincode, shipping.Pincode, name)
+			assert.Equal(t, shipping, billing, name)
+			assert.Empty(t, shipping.ID, name)
+			assert.False(t, shipping.Default, name)
+		}
+	}
+}
+
+// invoicedOrderRepo refuses to delete orders, as the invoices foreign key does.
+type invoicedOrderRepo struct {
+	interfaces.OrderRepository
+}
+
+func (r *invoicedOrderRepo) DeleteOrder(orderID string) error {
+	return interfaces.ErrOrderInvoiced
+}
+
+func TestDeleteInvoicedOrder(t *testing.T) {
+	s := &OrderService{OrderRepo: &invoicedOrderRepo{}}
+	assert.Equal(t, ErrOrderInvoiced, s.DeleteOrder(uuid.NewString()))
+}
+
+// backfillOrderRepo lists customers missing an email and records the emails
+// it is given.
+type backfillOrderRepo struct {
+	interfaces.OrderRepository
+	userIDs []string
+	emails  map[string]string
+}
+
+func (r *backfillOrderRepo) GetUserIDsWithoutEmail(ctx context.Context) ([]string, error) {
+	return r.userIDs, nil
+}
+
+func (r *backfillOrderRepo) SetCustomerEmail(ctx context.Context, userID, email string) (int64, error) {
+	r.emails[userID] = email
+	return 1, nil
+}
+
+func TestBackfillCustomerEmails(t *testing.T) {
+	known, deleted := primitive.NewObjectID(), primitive.NewObjectID()
+	orders := &backfillOrderRepo{
+		userIDs: []string{known.Hex(), deleted.Hex(), "guest-123"},
+		emails:  map[string]string{},
+	}
+	s := &OrderService{
+		OrderRepo: orders,
+		UserRepo: &fakeUserRepo{users: map[primitive.ObjectID]*models.User{
+			known: {ID: known, Email: "asha@example.com"},
+		}},
+	}
+	assert.NoError(t, s.BackfillCustomerEmails(context.Background()))
+	assert.Equal(t, map[string]string{known.Hex(): "asha@example.com"}, orders.emails)
+}
+
+func TestOrderCursorRoundTrip(t *testing.T) {
+	order := models.Order{
+		ID:        uuid.New(),
+		CreatedAt: time.Date(2026, 5, 4, 10, 30, 15, 123456789, time.FixedZone("IST", 5*60*60+30*60)),
+		Total:     models.NewMoney(129900, ""),
+	}
+	cases := map[string]interface{}{
+		"":          order.CreatedAt.UTC(),
+		"createdAt": order.CreatedAt.UTC(),
+		"total":     int64(129900),
+	}
+	for sortBy, want := range cases {
+		value, id, err := decodeOrderCursor(encodeOrderCursor(order, sortBy), sortBy)
+		assert.NoError(t, err, sortBy)
+		assert.Equal(t, want, value, sortBy)
+		assert.Equal(t, order.ID.String(), id, sortBy)
+	}
+}
+
+func TestDecodeOrderCursorRejectsMalformed(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+	id := uuid.NewString()
+
+	cases := map[string]struct {
+		cursor string
+		sortBy string
+	}{
+		"not base64":            {"not a cursor!", ""},
+		"no separator":          {encode("2026-05-04T10:30:15Z"), ""},
+		"bad order ID":          {encode("2026-05-04T10:30:15Z|42"), ""},
+		"bad time":              {encode("yesterday|" + id), ""},
+		"total cursor for time": {encode("129900|" + id), ""},
+		"time cursor for total": {encode("2026-05-04T10:30:15Z|" + id), "total"},
+		"fractional total":      {encode("1299.00|" + id), "total"},
+	}
+	for name, tc := range cases {
+		_, _, err := decodeOrderCursor(tc.cursor, tc.sortBy)
+		assert.Equal(t, ErrInvalidCursor, err, name)
+	}
+}
+
+// lookupOrderRepo records which lookup found an order.
+type lookupOrderRepo struct {
+	interfaces.OrderRepository
+	lookups []string
+}
+
+func (r *lookupOrderRepo) GetOrderByID(ctx context.Context, orderID string) (*models.Order, error) {
+	r.lookups = append(r.lookups, "id "+orderID)
+	return &models.Order{}, nil
+}
+
+func (r *lookupOrderRepo) GetOrderByNumber(ctx context.Context, orderNumber string) (*models.Order, error) {
+	r.lookups = append(r.lookups, "number "+orderNumber)
+	return &models.Order{}, nil
+}
+
+func TestGetOrderByIDAcceptsOrderNumbers(t *testing.T) {
+	id := uuid.NewString()
+	cases := map[string]string{
+		id:                  "id " + id,
+		"M11-2026-000042":   "number M11-2026-000042",
+		" m11-2026-000042 ": "number M11-2026-000042",
+	}
+	for ref, want := range cases {
+		repo := &lookupOrderRepo{}
+		s := &OrderService{OrderRepo: repo}
+		_, err := s.GetOrderByID(context.Background(), ref, true, "")
+		assert.NoError(t, err, ref)
+		assert.Equal(t, []string{want}, repo.lookups, ref)
+	}
+}
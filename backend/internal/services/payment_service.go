@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+var (
+	ErrOrderNotPaid        = errors.New("order has not been paid")
+	ErrInvalidRefundAmount = errors.New("invalid refund amount")
+)
+
+// PaymentGateway is the boundary to whatever moves money back to the customer.
+// A refund keeps its ID when it is retried, so gateways can use it as an
+// idempotency key. settled reports whether the money has been sent; it is
+// false when the gateway only passes the refund on to be paid by hand.
+type PaymentGateway interface {
+	Refund(ctx context.Context, order *models.Order, refund *models.Refund) (reference string, settled bool, err error)
+}
+
+// ManualPaymentGateway leaves refunds for finance to pay outside the system,
+// such as by bank transfer for cash-on-delivery orders. Its refunds wait in
+// SETTLEMENT_PENDING until an admin marks them settled.
+type ManualPaymentGateway struct{}
+
+func (ManualPaymentGateway) Refund(ctx context.Context, order *models.Order, refund *models.Refund) (string, bool, error) {
+	return "", false, nil
+}
+
+// refundRetryDelay is how long a PENDING refund is left to the request that
+// recorded it before RetryPendingRefunds sends it again.
+const refundRetryDelay = time.Minute
+
+type PaymentService struct {
+	PaymentRepo interfaces.PaymentRepository
+	OrderRepo   interfaces.OrderRepository
+	Gateway     PaymentGateway
+}
+
+func NewPaymentService(paymentRepo interfaces.PaymentRepository, orderRepo interfaces.OrderRepository, gateway PaymentGateway) *PaymentService {
+	return &PaymentService{
+		PaymentRepo: paymentRepo,
+		OrderRepo:   orderRepo,
+		Gateway:     gateway,
+	}
+}
+
+// GetPendingCODPayments returns COD payments the courier has collected but
+// that have not yet been reconciled against cash received.
+func (s *PaymentService) GetPendingCODPayments(ctx context.Context) ([]models.Payment, error) {
+	return s.PaymentRepo.GetUnreconciledCODPayments(ctx)
+}
+
+// ReconcileCOD marks the collected COD payments of the given orders as received.
+func (s *PaymentService) ReconcileCOD(ctx context.Context, orderIDs []string, adminID string) (int64, error) {
+	if len(orderIDs) == 0 {
+		return 0, errors.New("no orders to reconcile")
+	}
+	for _, id := range orderIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return 0, errors.New("invalid order id: " + id)
+		}
+	}
+	return s.PaymentRepo.ReconcileCODPayments(ctx, orderIDs, adminID)
+}
+
+// Refund records a refund of amount against an order and sends it through
+// the gateway. Once everything paid for the order has been refunded the order
+// is marked REFUNDED.
+func (s *PaymentService) Refund(ctx context.Context, order *models.Order, amount models.Money, reason string) (*models.Refund, error) {
+	refund, err := s.NewRefund(ctx, order, amount, reason)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.PaymentRepo.CreateRefund(ctx, refund, unrecordedPayment(order)); err != nil {
+		return nil, refundError(err)
+	}
+	s.SendRefund(ctx, order, refund)
+	return refund, nil
+}
+
+// NewRefund checks that a refund of amount can be made against an order and
+// returns it ready to be recorded. The amount must be in the order's
+// currency. Whether it fits in what is left of the amount paid is checked
+// when it is recorded.
+func (s *PaymentService) NewRefund(ctx context.Context, order *models.Order, amount models.Money, reason string) (*models.Refund, error) {
+	if !amount.IsPositive() || amount.Currency != order.Total.Currency {
+		return nil, ErrInvalidRefundAmount
+	}
+
+	paid, err := s.PaidAmount(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if !paid.IsPositive() {
+		return nil, ErrOrderNotPaid
+	}
+
+	return &models.Refund{
+		ID:        uuid.New(),
+		OrderID:   order.ID,
+		Amount:    amount,
+		Reason:    reason,
+		Status:    constants.RefundStatusPending,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// SendRefund passes a recorded refund to the gateway. A refund the gateway
+// does not take stays PENDING for RetryPendingRefunds, so failures are
+// logged rather than returned: the refund is owed either way.
+func (s *PaymentService) SendRefund(ctx context.Context, order *models.Order, refund *models.Refund) {
+	reference, settled, err := s.Gateway.Refund(ctx, order, refund)
+	if err != nil {
+		log.Printf("Refund %s for order %s failed: %v", refund.ID, order.ID, err)
+		return
+	}
+
+	refund.Reference = reference
+	refund.Status = constants.RefundStatusSettlementPending
+	if settled {
+		refund.Status = constants.RefundStatusCompleted
+	}
+	if err := s.PaymentRepo.UpdateRefundStatus(ctx, refund); err != nil {
+		log.Printf("Failed to record refund %s as %s: %v", refund.ID, refund.Status, err)
+	}
+}
+
+// RetryPendingRefunds sends refunds the gateway has not taken yet.
+func (s *PaymentService) RetryPendingRefunds(ctx context.Context) error {
+	refunds, err := s.PaymentRepo.GetPendingRefunds(ctx, time.Now().Add(-refundRetryDelay))
+	if err != nil {
+		return err
+	}
+	for i := range refunds {
+		order, err := s.OrderRepo.GetOrderByID(ctx, refunds[i].OrderID.String())
+		if err != nil {
+			return err
+		}
+		s.SendRefund(ctx, order, &refunds[i])
+	}
+	return nil
+}
+
+// StartRefundRetrier retries PENDING refunds on every interval.
+func (s *PaymentService) StartRefundRetrier(interval time.Duration) {
+	runEvery(interval, "pending refund retry", s.RetryPendingRefunds)
+}
+
+// GetUnsettledRefunds returns refunds waiting for finance to pay them by hand.
+func (s *PaymentService) GetUnsettledRefunds(ctx context.Context) ([]models.Refund, error) {
+	return s.PaymentRepo.GetUnsettledRefunds(ctx)
+}
+
+// SettleRefunds marks refunds finance has paid by hand as completed, with
+// the reference of the transfer if there is one.
+func (s *PaymentService) SettleRefunds(ctx context.Context, refundIDs []string, reference, adminID string) (int64, error) {
+	if len(refundIDs) == 0 {
+		return 0, errors.New("no refunds to settle")
+	}
+	for _, id := range refundIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return 0, errors.New("invalid refund id: " + id)
+		}
+	}
+	return s.PaymentRepo.SettleRefunds(ctx, refundIDs, strings.TrimSpace(reference), adminID)
+}
+
+// RecordOnlinePayment stores the amount captured for a prepaid order.
+func (s *PaymentService) RecordOnlinePayment(ctx context.Context, order *models.Order, reference string) error {
+	now := time.Now()
+	return s.PaymentRepo.CreatePayment(ctx, &models.Payment{
+		ID:          uuid.New(),
+		OrderID:     order.ID,
+		Method:      constants.PaymentMethodOnline,
+		Amount:      order.Total,
+		Status:      constants.PaymentStatusCaptured,
+		Reference:   reference,
+		CollectedAt: &now,
+	})
+}
+
+// PaidAmount returns how much has been received for an order. Refunds are
+// capped at this amount rather than the order total, which shrinks when
+// lines are cancelled after payment.
+func (s *PaymentService) PaidAmount(ctx context.Context, order *models.Order) (models.Money, error) {
+	payments, err := s.PaymentRepo.GetPaymentsByOrderID(ctx, order.ID.String())
+	if err != nil {
+		return models.Money{}, err
+	}
+
+	paid := models.NewMoney(0, order.Total.Currency)
+	for _, p := range payments {
+		switch p.Status {
+		case constants.PaymentStatusCaptured, constants.PaymentStatusCollected, constants.PaymentStatusReconciled:
+			paid = paid.Add(p.Amount)
+		}
+	}
+
+	if len(payments) == 0 {
+		paid = unrecordedPayment(order)
+	}
+	return paid, nil
+}
+
+// unrecordedPayment is what an order with no payment rows is taken to have
+// paid. Prepaid orders marked paid before payments were recorded have none,
+// so they fall back to the order total.
+func unrecordedPayment(order *models.Order) models.Money {
+	if order.PaymentMethod != constants.PaymentMethodCOD {
+		switch order.Status {
+		case constants.OrderStatusPaid, constants.OrderStatusPartiallyShipped, constants.OrderStatusShipped, constants.OrderStatusDelivered:
+			return order.Total
+		}
+	}
+	return models.NewMoney(0, order.Total.Currency)
+}
+
+// refundError maps a refund the database turned down onto the service error.
+func refundError(err error) error {
+	if errors.Is(err, interfaces.ErrRefundExceedsPaid) {
+		return ErrInvalidRefundAmount
+	}
+	return err
+}
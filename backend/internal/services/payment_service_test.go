@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+type fakeGateway struct {
+	settled bool
+	err     error
+}
+
+func (g fakeGateway) Refund(ctx context.Context, order *models.Order, refund *models.Refund) (string, bool, error) {
+	if g.err != nil {
+		return "", false, g.err
+	}
+	return "ref-" + refund.ID.String(), g.settled, nil
+}
+
+// fakePaymentRepo records refund status updates. Methods the tests do not
+// need are left to the embedded nil interface.
+type fakePaymentRepo struct {
+	interfaces.PaymentRepository
+	updated []models.Refund
+}
+
+func (r *fakePaymentRepo) UpdateRefundStatus(ctx context.Context, refund *models.Refund) error {
+	r.updated = append(r.updated, *refund)
+	return nil
+}
+
+func TestSendRefundRecordsGatewayOutcome(t *testing.T) {
+	order := &models.Order{ID: uuid.New(), Total: models.NewMoney(50000, "")}
+	cases := map[string]struct {
+		gateway PaymentGateway
+		want    string
+	}{
+		"sent":          {fakeGateway{settled: true}, constants.RefundStatusCompleted},
+		"paid by hand":  {ManualPaymentGateway{}, constants.RefundStatusSettlementPending},
+		"gateway error": {fakeGateway{err: errors.New("timeout")}, constants.RefundStatusPending},
+	}
+	for name, tc := range cases {
+		repo := &fakePaymentRepo{}
+		s := &PaymentService{PaymentRepo: repo, Gateway: tc.gateway}
+		refund := &models.Refund{ID: uuid.New(), OrderID: order.ID, Amount: models.NewMoney(1000, ""), Status: constants.RefundStatusPending}
+
+		s.SendRefund(context.Background(), order, refund)
+		assert.Equal(t, tc.want, refund.Status, name)
+		if tc.want == constants.RefundStatusPending {
+			assert.Empty(t, repo.updated, name)
+		} else {
+			assert.Len(t, repo.updated, 1, name)
+		}
+	}
+}
+
+func TestUnrecordedPayment(t *testing.T) {
+	total := models.NewMoney(129900, "")
+	cases := map[[2]string]int64{
+		{constants.PaymentMethodOnline, constants.OrderStatusPaid}:      129900,
+		{constants.PaymentMethodOnline, constants.OrderStatusDelivered}: 129900,
+		{constants.PaymentMethodOnline, constants.OrderStatusPending}:   0,
+		{constants.PaymentMethodOnline, constants.OrderStatusCancelled}: 0,
+		{constants.PaymentMethodCOD, constants.OrderStatusDelivered}:    0,
+	}
+	for c, want := range cases {
+		order := &models.Order{PaymentMethod: c[0], Status: c[1], Total: total}
+		assert.Equal(t, models.NewMoney(want, ""), unrecordedPayment(order), "%s %s", c[0], c[1])
+	}
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+var (
+	ErrProductUnavailable   = errors.New("product is no longer available")
+	ErrPriceListNotFound    = errors.New("price list not found")
+	ErrInvalidPriceList     = errors.New("price lists need a name, a customer group and prices for existing products")
+	ErrInvalidCustomerGroup = errors.New("customer group must be at most 40 characters")
+	ErrUserNotFound         = errors.New("user not found")
+)
+
+// PricingService resolves what a customer pays for a product. Listings, the
+// cart and order creation all price products through it, so a customer
+// sees the same price from browsing to checkout.
+type PricingService struct {
+	ProductRepo   interfaces.ProductRepository
+	UserRepo      interfaces.UserRepository
+	PriceListRepo interfaces.PriceListRepository
+}
+
+func NewPricingService(productRepo interfaces.ProductRepository, userRepo interfaces.UserRepository, priceListRepo interfaces.PriceListRepository) *PricingService {
+	return &PricingService{
+		ProductRepo:   productRepo,
+		UserRepo:      userRepo,
+		PriceListRepo: priceListRepo,
+	}
+}
+
+// ApplyPricing fills in each product's Pricing for the customer, who may be
+// signed out.
+func (s *PricingService) ApplyPricing(ctx context.Context, userID string, products ...*models.Product) error {
+	list, err := s.priceListFor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, p := range products {
+		pricing := resolvePrice(p, list, now)
+		p.Pricing = &pricing
+	}
+	return nil
+}
+
+// PriceCartItems sets every item's price, name and subtotal from the
+// current catalog, ignoring whatever the client sent. Items whose product
+// is gone or no longer live fail with ErrProductUnavailable.
+func (s *PricingService) PriceCartItems(ctx context.Context, userID string, items []models.CartItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	list, err := s.priceListFor(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+	found, err := s.ProductRepo.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*models.Product, len(found))
+	for _, p := range found {
+		byID[p.ID.Hex()] = p
+	}
+
+	now := time.Now()
+	for i := range items {
+		product, ok := byID[items[i].ProductID]
+		if !ok || !isPublished(product) {
+			return fmt.Errorf("%w: %s", ErrProductUnavailable, items[i].ProductID)
+		}
+		pricing := resolvePrice(product, list, now)
+		items[i].Name = product.Title
+		items[i].Price = pricing.Price
+		items[i].Subtotal = pricing.Price.Mul(items[i].Quantity)
+		if items[i].Image == "" && len(product.Images) > 0 {
+			items[i].Image = product.Images[0]
+		}
+	}
+	return nil
+}
+
+// StartSaleWatcher drops the cached product listings whenever a sale starts
+// or ends, checking every interval, so cached prices never outlive a sale
+// window by more than that.
+func (s *PricingService) StartSaleWatcher(interval time.Duration, onChange func()) {
+	last := time.Now()
+	runEvery(interval, "sale window watch", func(ctx context.Context) error {
+		now := time.Now()
+		crossed, err := s.ProductRepo.CountSaleBoundaries(ctx, last, now)
+		if err != nil {
+			return err
+		}
+		last = now
+		if crossed > 0 {
+			onChange()
+		}
+		return nil
+	})
+}
+
+// ConvertLegacyPrices converts the product and price list prices stored as
+// plain numbers, from before amounts carried a currency, to minor units of
+// the store's currency.
+func (s *PricingService) ConvertLegacyPrices(ctx context.Context) error {
+	products, err := s.ProductRepo.ConvertLegacyPrices(ctx, models.DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	lists, err := s.PriceListRepo.ConvertLegacyPrices(ctx, models.DefaultCurrency)
+	if products+lists > 0 {
+		log.Printf("Converted the prices of %d products and %d price lists to minor units", products, lists)
+	}
+	return err
+}
+
+func (s *PricingService) ListPriceLists(ctx context.Context) ([]*models.PriceList, error) {
+	return s.PriceListRepo.ListPriceLists(ctx)
+}
+
+func (s *PricingService) CreatePriceList(ctx context.Context, req models.PriceListRequest) (*models.PriceList, error) {
+	now := time.Now()
+	list := &models.PriceList{
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyPriceListRequest(ctx, list, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.PriceListRepo.CreatePriceList(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *PricingService) UpdatePriceList(ctx context.Context, id string, req models.PriceListRequest) (*models.PriceList, error) {
+	list, err := s.PriceListRepo.GetPriceListByID(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return nil, ErrPriceListNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyPriceListRequest(ctx, list, req); err != nil {
+		return nil, err
+	}
+	list.UpdatedAt = time.Now()
+
+	if err := s.PriceListRepo.UpdatePriceList(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *PricingService) DeletePriceList(ctx context.Context, id string) error {
+	if err := s.PriceListRepo.DeletePriceList(ctx, id); err != nil {
+		return ErrPriceListNotFound
+	}
+	return nil
+}
+
+// SetCustomerGroup moves a customer into a pricing group. An empty group
+// puts them back on the standard prices.
+func (s *PricingService) SetCustomerGroup(ctx context.Context, userID string, req models.CustomerGroupRequest) error {
+	if validate.Struct(req) != nil {
+		return ErrInvalidCustomerGroup
+	}
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if _, err := s.UserRepo.GetUserByID(objectID); err != nil {
+		return ErrUserNotFound
+	}
+	return s.UserRepo.UpdateUser(objectID, bson.M{"customerGroup": utils.Slugify(req.Group)})
+}
+
+func (s *PricingService) applyPriceListRequest(ctx context.Context, list *models.PriceList, req models.PriceListRequest) error {
+	if err := validate.Struct(req); err != nil {
+		return ErrInvalidPriceList
+	}
+	group := utils.Slugify(req.Group)
+	if group == "" {
+		return ErrInvalidPriceList
+	}
+
+	prices := make([]models.PriceListPrice, 0, len(req.Prices))
+	ids := make([]string, 0, len(req.Prices))
+	seen := make(map[primitive.ObjectID]bool, len(req.Prices))
+	for _, p := range req.Prices {
+		productID, err := primitive.ObjectIDFromHex(p.ProductID)
+		if err != nil || seen[productID] || !inStoreCurrency(p.Price) {
+			return ErrInvalidPriceList
+		}
+		seen[productID] = true
+		prices = append(prices, models.PriceListPrice{ProductID: productID, Price: p.Price})
+		ids = append(ids, p.ProductID)
+	}
+	if len(ids) > 0 {
+		found, err := s.ProductRepo.GetProductsByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		if len(found) != len(ids) {
+			return ErrInvalidPriceList
+		}
+	}
+
+	list.Name = strings.TrimSpace(req.Name)
+	list.Group = group
+	list.DiscountPercent = req.DiscountPercent
+	list.Prices = prices
+	if req.Active != nil {
+		list.Active = *req.Active
+	}
+	return nil
+}
+
+// priceListFor returns the active price list of the customer's group, or
+// nil for guests and customers without one.
+func (s *PricingService) priceListFor(ctx context.Context, userID string) (*models.PriceList, error) {
+	if userID == "" {
+		return nil, nil
+	}
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, nil
+	}
+	user, err := s.UserRepo.GetUserByID(objectID)
+	if err != nil || user == nil || user.CustomerGroup == "" {
+		return nil, nil
+	}
+
+	list, err := s.PriceListRepo.GetActivePriceList(ctx, user.CustomerGroup)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	return list, err
+}
+
+// resolvePrice works out the lowest price on offer at now: the product's
+// own price, a running sale, or the customer's price list. A list discount is
+// rounded half to even. The price is compared against the compare-at price
+// when that is higher, or else the regular price when a sale or price list
+// brings it down.
+func resolvePrice(p *models.Product, list *models.PriceList, now time.Time) models.ProductPricing {
+	pricing := models.ProductPricing{Price: p.Price}
+
+	for _, sale := range p.Sales {
+		if !now.Before(sale.StartsAt) && now.Before(sale.EndsAt) && sale.Price.Cmp(pricing.Price) < 0 {
+			endsAt := sale.EndsAt
+			pricing.Price = sale.Price
+			pricing.OnSale = true
+			pricing.SaleEndsAt = &endsAt
+		}
+	}
+
+	if list != nil {
+		listPrice := p.Price.Sub(p.Price.Percent(list.DiscountPercent))
+		for _, entry := range list.Prices {
+			if entry.ProductID == p.ID {
+				listPrice = entry.Price
+				break
+			}
+		}
+		if listPrice.Cmp(pricing.Price) < 0 {
+			pricing.Price = listPrice
+			pricing.OnSale = false
+			pricing.SaleEndsAt = nil
+			pricing.PriceList = list.Name
+		}
+	}
+
+	compareAt := p.Price
+	if p.CompareAtPrice != nil && p.CompareAtPrice.Cmp(compareAt) > 0 {
+		compareAt = *p.CompareAtPrice
+	}
+	if compareAt.Cmp(pricing.Price) > 0 {
+		pricing.CompareAtPrice = &compareAt
+	}
+	return pricing
+}
+
+// inStoreCurrency reports whether every amount is in the currency the store
+// sells in. Prices in any other currency could not be added to a cart.
+func inStoreCurrency(amounts ...models.Money) bool {
+	for _, m := range amounts {
+		if m.Currency != models.DefaultCurrency {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+func TestResolvePrice(t *testing.T) {
+	now := time.Date(2026, 11, 27, 10, 0, 0, 0, time.UTC)
+	inr := func(amount int64) models.Money { return models.NewMoney(amount, "") }
+	ptr := func(m models.Money) *models.Money { return &m }
+	at := func(t time.Time) *time.Time { return &t }
+	sale := func(amount int64, startsAt, endsAt time.Time) models.ProductSale {
+		return models.ProductSale{Price: inr(amount), StartsAt: startsAt, EndsAt: endsAt}
+	}
+	saleEnds := now.Add(48 * time.Hour)
+	running := sale(80000, now.Add(-time.Hour), saleEnds)
+
+	id := primitive.NewObjectID()
+	product := func(compareAt *models.Money, sales ...models.ProductSale) *models.Product {
+		return &models.Product{ID: id, Price: inr(100000), CompareAtPrice: compareAt, Sales: sales}
+	}
+	discount := func(percent float64, prices ...models.PriceListPrice) *models.PriceList {
+		return &models.PriceList{Name: "Wholesale", DiscountPercent: percent, Prices: prices}
+	}
+	fixed := func(amount int64) models.PriceListPrice {
+		return models.PriceListPrice{ProductID: id, Price: inr(amount)}
+	}
+
+	cases := map[string]struct {
+		product *models.Product
+		list    *models.PriceList
+		want    models.ProductPricing
+	}{
+		"regular price": {
+			product(nil), nil,
+			models.ProductPricing{Price: inr(100000)},
+		},
+		"compare-at price": {
+			product(ptr(inr(120000))), nil,
+			models.ProductPricing{Price: inr(100000), CompareAtPrice: ptr(inr(120000))},
+		},
+		"compare-at below price": {
+			product(ptr(inr(90000))), nil,
+			models.ProductPricing{Price: inr(100000)},
+		},
+		"running sale": {
+			product(ptr(inr(120000)), running), nil,
+			models.ProductPricing{Price: inr(80000), CompareAtPrice: ptr(inr(120000)), OnSale: true, SaleEndsAt: at(saleEnds)},
+		},
+		"sale compared to regular price": {
+			product(nil, running), nil,
+			models.ProductPricing{Price: inr(80000), CompareAtPrice: ptr(inr(100000)), OnSale: true, SaleEndsAt: at(saleEnds)},
+		},
+		"sale starting now": {
+			product(nil, sale(80000, now, saleEnds)), nil,
+			models.ProductPricing{Price: inr(80000), CompareAtPrice: ptr(inr(100000)), OnSale: true, SaleEndsAt: at(saleEnds)},
+		},
+		"sale ending now": {
+			product(nil, sale(80000, now.Add(-time.Hour), now)), nil,
+			models.ProductPricing{Price: inr(100000)},
+		},
+		"upcoming sale": {
+			product(nil, sale(80000, now.Add(time.Hour), saleEnds)), nil,
+			models.ProductPricing{Price: inr(100000)},
+		},
+		"sale above price": {
+			product(nil, sale(110000, now.Add(-time.Hour), saleEnds)), nil,
+			models.ProductPricing{Price: inr(100000)},
+		},
+		"overlapping sales": {
+			product(nil, running, sale(75000, now.Add(-time.Hour), now.Add(time.Hour))), nil,
+			models.ProductPricing{Price: inr(75000), CompareAtPrice: ptr(inr(100000)), OnSale: true, SaleEndsAt: at(now.Add(time.Hour))},
+		},
+		"list discount": {
+			product(ptr(inr(120000))), discount(12.5),
+			models.ProductPricing{Price: inr(87500), CompareAtPrice: ptr(inr(120000)), PriceList: "Wholesale"},
+		},
+		"list discount rounded half to even": {
+			&models.Product{ID: id, Price: inr(1001)}, discount(50),
+			models.ProductPricing{Price: inr(501), CompareAtPrice: ptr(inr(1001)), PriceList: "Wholesale"},
+		},
+		"fixed list price beats the discount": {
+			product(nil), discount(50, fixed(70000)),
+			models.ProductPricing{Price: inr(70000), CompareAtPrice: ptr(inr(100000)), PriceList: "Wholesale"},
+		},
+		"fixed list price for another product": {
+			product(nil), discount(10, models.PriceListPrice{ProductID: primitive.NewObjectID(), Price: inr(50000)}),
+			models.ProductPricing{Price: inr(90000), CompareAtPrice: ptr(inr(100000)), PriceList: "Wholesale"},
+		},
+		"fixed list price above price": {
+			product(nil), discount(0, fixed(110000)),
+			models.ProductPricing{Price: inr(100000)},
+		},
+		"sale beats the list": {
+			product(nil, running), discount(10),
+			models.ProductPricing{Price: inr(80000), CompareAtPrice: ptr(inr(100000)), OnSale: true, SaleEndsAt: at(saleEnds)},
+		},
+		"list beats the sale": {
+			product(nil, running), discount(30),
+			models.ProductPricing{Price: inr(70000), CompareAtPrice: ptr(inr(100000)), PriceList: "Wholesale"},
+		},
+	}
+	for name, tc := range cases {
+		assert.Equal(t, tc.want, resolvePrice(tc.product, tc.list, now), name)
+	}
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+var (
+	ErrProductVersionNotFound = errors.New("product version not found")
+	ErrNothingToRollBack      = errors.New("product is already at that version")
+)
+
+// productHistoryFields are the product fields the change history tracks.
+// Stock, ratings and images change outside of product edits, so rolling
+// them back would undo sales, reviews and uploads.
+var productHistoryFields = []string{
+	"title", "description", "price", "compareAtPrice", "sales", "category",
+	"categoryId", "sku", "sizes", "tags", "status", "publishAt", "unpublishAt",
+}
+
+// GetProductHistory pages through a product's change history, newest first.
+func (s *ProductService) GetProductHistory(ctx context.Context, productID string, page, limit int) ([]*models.ProductVersion, int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(productID)
+	if err != nil {
+		return nil, 0, ErrProductNotFound
+	}
+	versions, total, err := s.HistoryRepo.ListVersions(ctx, objectID, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	// Stored prices and sales decode as BSON documents; give them back in
+	// the shape the API takes them.
+	for _, v := range versions {
+		for i := range v.Changes {
+			v.Changes[i].From = historyValue(v.Changes[i].From)
+			v.Changes[i].To = historyValue(v.Changes[i].To)
+		}
+	}
+	return versions, total, nil
+}
+
+// RollbackProduct restores the tracked fields to their values as of version
+// by undoing every later change, newest first. The rollback is itself
+// recorded as a new version.
+func (s *ProductService) RollbackProduct(ctx context.Context, productID string, version int, adminID string) (*models.ProductVersion, error) {
+	product, err := findProduct(ctx, s.Repo, productID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.HistoryRepo.GetVersion(ctx, product.ID, version); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProductVersionNotFound
+		}
+		return nil, err
+	}
+
+	later, err := s.HistoryRepo.GetVersionsAfter(ctx, product.ID, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(later) == 0 {
+		return nil, ErrNothingToRollBack
+	}
+
+	restored, err := s.updateProduct(ctx, productID, rollbackUpdates(later), adminID, constants.ProductChangeRollback, version)
+	if err == nil && restored == nil {
+		// Later changes cancelled each other out.
+		return nil, ErrNothingToRollBack
+	}
+	return restored, err
+}
+
+// rollbackUpdates works out the update that undoes the later versions: each
+// tracked field they changed goes back to its value before the earliest of
+// those changes.
+func rollbackUpdates(later []*models.ProductVersion) map[string]interface{} {
+	earliest := map[string]int{}
+	updates := map[string]interface{}{}
+	for _, v := range later {
+		for _, change := range v.Changes {
+			if !slices.Contains(productHistoryFields, change.Field) {
+				continue
+			}
+			if at, ok := earliest[change.Field]; ok && at < v.Version {
+				continue
+			}
+			earliest[change.Field] = v.Version
+			updates[change.Field] = historyValue(change.From)
+		}
+	}
+	return updates
+}
+
+// GetPriceHistory returns the prices a live product has had, oldest first,
+// each with when it took effect. A product whose history starts after it was
+// created is assumed to have had its first recorded old price since then.
+func (s *ProductService) GetPriceHistory(ctx context.Context, productID string) ([]models.PricePoint, error) {
+	product, err := s.GetPublishedProduct(productID)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.HistoryRepo.GetPriceChanges(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []models.PricePoint{}
+	for _, v := range versions {
+		for _, change := range v.Changes {
+			if change.Field != "price" {
+				continue
+			}
+			if from, ok := moneyValue(change.From); ok && len(points) == 0 {
+				points = append(points, models.PricePoint{Price: from, Since: product.CreatedAt})
+			}
+			if to, ok := moneyValue(change.To); ok {
+				points = append(points, models.PricePoint{Price: to, Since: v.CreatedAt})
+			}
+		}
+	}
+	if len(points) == 0 {
+		points = append(points, models.PricePoint{Price: product.Price, Since: product.CreatedAt})
+	}
+	return points, nil
+}
+
+// recordChange stores the tracked fields that differ between before and
+// after as the product's next version. before is nil for a new product.
+// Failing to record is logged rather than undoing the change.
+func (s *ProductService) recordChange(ctx context.Context, before, after *models.Product, adminID, action string, rolledBackTo int) *models.ProductVersion {
+	var from map[string]interface{}
+	if before != nil {
+		from = productSnapshot(before)
+	}
+	changes := diffSnapshots(from, productSnapshot(after))
+	if len(changes) == 0 {
+		return nil
+	}
+
+	version := &models.ProductVersion{
+		ProductID:    after.ID,
+		Action:       action,
+		AdminID:      adminID,
+		RolledBackTo: rolledBackTo,
+		Changes:      changes,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.HistoryRepo.AddVersion(ctx, version); err != nil {
+		log.Printf("Failed to record history for product %s: %v", after.ID.Hex(), err)
+		return nil
+	}
+	return version
+}
+
+// productSnapshot returns the tracked fields of a product in the shape
+// UpdateProduct accepts, so old values can be written straight back.
+func productSnapshot(p *models.Product) map[string]interface{} {
+	snapshot := map[string]interface{}{
+		"title":          p.Title,
+		"description":    p.Description,
+		"price":          p.Price,
+		"compareAtPrice": nil,
+		"sales":          saleList(p.Sales),
+		"category":       p.Category,
+		"categoryId":     nil,
+		// An empty SKU is stored as null so it stays out of the unique index.
+		"sku":         nil,
+		"sizes":       stringList(p.Sizes),
+		"tags":        stringList(p.Tags),
+		"status":      p.Status,
+		"publishAt":   nil,
+		"unpublishAt": nil,
+	}
+	if p.CompareAtPrice != nil {
+		snapshot["compareAtPrice"] = *p.CompareAtPrice
+	}
+	if p.CategoryID != nil {
+		snapshot["categoryId"] = p.CategoryID.Hex()
+	}
+	if p.SKU != "" {
+		snapshot["sku"] = p.SKU
+	}
+	if p.PublishAt != nil {
+		snapshot["publishAt"] = p.PublishAt.UTC().Format(time.RFC3339)
+	}
+	if p.UnpublishAt != nil {
+		snapshot["unpublishAt"] = p.UnpublishAt.UTC().Format(time.RFC3339)
+	}
+	return snapshot
+}
+
+// diffSnapshots lists the tracked fields whose values differ. With no
+// before snapshot every set field is listed as new.
+func diffSnapshots(before, after map[string]interface{}) []models.ProductFieldChange {
+	changes := []models.ProductFieldChange{}
+	for _, field := range productHistoryFields {
+		to := after[field]
+		if before == nil {
+			if to != nil {
+				changes = append(changes, models.ProductFieldChange{Field: field, To: to})
+			}
+			continue
+		}
+		if from := before[field]; !reflect.DeepEqual(from, to) {
+			changes = append(changes, models.ProductFieldChange{Field: field, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+func stringList(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// saleList returns sales in the shape a JSON update carries them.
+func saleList(sales []models.ProductSale) []interface{} {
+	values := make([]interface{}, 0, len(sales))
+	for _, sale := range sales {
+		values = append(values, map[string]interface{}{
+			"price":    sale.Price,
+			"startsAt": sale.StartsAt.UTC().Format(time.RFC3339),
+			"endsAt":   sale.EndsAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return values
+}
+
+// historyValue converts a value read back from the history into the type
+// productSnapshot produced; stored lists decode as generic arrays and
+// stored sales as documents.
+func historyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.A:
+		if len(v) > 0 {
+			if _, ok := v[0].(string); !ok {
+				values := make([]interface{}, 0, len(v))
+				for _, item := range v {
+					values = append(values, historyValue(item))
+				}
+				return values
+			}
+		}
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	case primitive.D:
+		values := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			values[e.Key] = historyValue(e.Value)
+		}
+		return values
+	case primitive.M:
+		values := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			values[k] = historyValue(item)
+		}
+		return values
+	}
+	return value
+}
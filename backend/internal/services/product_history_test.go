@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+func TestRollbackUpdates(t *testing.T) {
+	change := func(field string, from, to interface{}) models.ProductFieldChange {
+		return models.ProductFieldChange{Field: field, From: from, To: to}
+	}
+	// Versions 3 to 5, as undone by rolling back to version 2.
+	v3 := &models.ProductVersion{Version: 3, Changes: []models.ProductFieldChange{
+		change("title", "Classic Tee", "Classic Tee v2"),
+		change("price", primitive.D{{Key: "amount", Value: int64(49900)}, {Key: "currency", Value: "INR"}}, primitive.D{{Key: "amount", Value: int64(59900)}, {Key: "currency", Value: "INR"}}),
+	}}
+	v4 := &models.ProductVersion{Version: 4, Changes: []models.ProductFieldChange{
+		change("title", "Classic Tee v2", "Classic Tee v3"),
+		change("tags", nil, primitive.A{"summer"}),
+		// Older history may still carry untracked fields.
+		change("inStock", int32(10), int32(4)),
+	}}
+	v5 := &models.ProductVersion{Version: 5, Changes: []models.ProductFieldChange{
+		change("sizes", primitive.A{"S", "M"}, primitive.A{"S", "M", "L"}),
+		change("images", primitive.A{"a.jpg"}, primitive.A{"b.jpg"}),
+	}}
+
+	want := map[string]interface{}{
+		"title": "Classic Tee",
+		"price": map[string]interface{}{"amount": int64(49900), "currency": "INR"},
+		"tags":  nil,
+		"sizes": []string{"S", "M"},
+	}
+	cases := map[string][]*models.ProductVersion{
+		"newest first": {v5, v4, v3},
+		"oldest first": {v3, v4, v5},
+	}
+	for name, later := range cases {
+		assert.Equal(t, want, rollbackUpdates(later), name)
+	}
+	assert.Empty(t, rollbackUpdates(nil))
+}
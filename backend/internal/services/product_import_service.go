@@ -0,0 +1,477 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+// productCSVColumns are the catalog export columns, which the import reads
+// back. Images, sizes and tags hold "|"-separated lists.
+var productCSVColumns = []string{
+	"id", "sku", "slug", "title", "description", "price", "category", "inStock", "images", "sizes", "tags", "status",
+}
+
+// requiredImportColumns must appear in an import file's header.
+var requiredImportColumns = []string{"title", "description", "price", "category", "instock"}
+
+// maxImportErrors caps the errors kept in a report, so a file in the wrong
+// format does not produce one error per cell.
+const maxImportErrors = 500
+
+var (
+	ErrInvalidImportFile = errors.New("import file must be a CSV with title, description, price, category and inStock columns")
+	ErrImportJobNotFound = errors.New("import job not found")
+)
+
+type ProductImportService struct {
+	ProductRepo    interfaces.ProductRepository
+	CategoryRepo   interfaces.CategoryRepository
+	JobRepo        interfaces.JobRepository
+	ProductService *ProductService
+	Config         config.ImportConfig
+}
+
+func NewProductImportService(
+	productRepo interfaces.ProductRepository,
+	categoryRepo interfaces.CategoryRepository,
+	jobRepo interfaces.JobRepository,
+	productService *ProductService,
+	cfg config.ImportConfig,
+) *ProductImportService {
+	return &ProductImportService{
+		ProductRepo:    productRepo,
+		CategoryRepo:   categoryRepo,
+		JobRepo:        jobRepo,
+		ProductService: productService,
+		Config:         cfg,
+	}
+}
+
+// Import validates a product CSV and, unless dryRun is set, upserts its
+// valid rows: by SKU when the row has one, otherwise by slug, otherwise as a
+// new product. Invalid rows are skipped and listed in the report. Imports
+// over the configured row limit run as a background job instead, and the
+// report ends up in the job's result.
+func (s *ProductImportService) Import(ctx context.Context, data []byte, dryRun bool, adminID string) (*models.ProductImportReport, *models.Job, error) {
+	records, err := readProductCSV(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if dryRun || len(records) <= s.Config.SyncLimit {
+		report, err := s.importRecords(ctx, records, dryRun, adminID, nil)
+		return report, nil, err
+	}
+
+	job := &models.Job{
+		ID:        uuid.New(),
+		Type:      constants.JobTypeProductImport,
+		Status:    constants.JobStatusPending,
+		Params:    map[string]interface{}{"rows": len(records)},
+		Total:     len(records),
+		CreatedBy: adminID,
+	}
+	if err := s.JobRepo.CreateJob(ctx, job); err != nil {
+		return nil, nil, err
+	}
+
+	go s.runImport(job, records)
+	return nil, job, nil
+}
+
+// GetImportJob returns an import job, with its report once it is done.
+func (s *ProductImportService) GetImportJob(ctx context.Context, id string) (*models.Job, error) {
+	job, err := s.JobRepo.GetJobByID(ctx, id)
+	if err != nil || job.Type != constants.JobTypeProductImport {
+		return nil, ErrImportJobNotFound
+	}
+	return job, nil
+}
+
+// WriteCatalogExport writes every product to w in the import's column
+// layout, so an export can be edited and imported again.
+func (s *ProductImportService) WriteCatalogExport(ctx context.Context, format string, w io.Writer) error {
+	rw, err := newRowWriter(format, w)
+	if err != nil {
+		return err
+	}
+	header := make([]interface{}, len(productCSVColumns))
+	for i, column := range productCSVColumns {
+		header[i] = column
+	}
+	if err := rw.WriteRow(header); err != nil {
+		return err
+	}
+
+	err = s.ProductRepo.StreamProducts(ctx, func(p *models.Product) error {
+		return rw.WriteRow([]interface{}{
+			p.ID.Hex(), p.SKU, p.Slug, p.Title, p.Description, p.Price, p.Category, p.InStock,
+			strings.Join(p.Images, "|"), strings.Join(p.Sizes, "|"), strings.Join(p.Tags, "|"), p.Status,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+func (s *ProductImportService) runImport(job *models.Job, records []map[string]string) {
+	ctx := context.Background()
+	update := func() {
+		if err := s.JobRepo.UpdateJob(ctx, job); err != nil {
+			log.Println("Product import job update error:", err)
+		}
+	}
+
+	job.Status = constants.JobStatusRunning
+	update()
+
+	report, err := s.importRecords(ctx, records, false, job.CreatedBy, func(done int) {
+		job.Progress = done
+		update()
+	})
+
+	now := time.Now()
+	job.CompletedAt = &now
+	if report != nil {
+		job.Result = reportResult(report)
+	}
+	if err != nil {
+		log.Println("Product import job", job.ID, "failed:", err)
+		job.Status = constants.JobStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = constants.JobStatusCompleted
+		job.Progress = job.Total
+	}
+	update()
+}
+
+// importRecords validates every record and then writes the valid ones in
+// batches, recording each product's changes in its history and calling
+// onProgress with the number of records handled so far.
+func (s *ProductImportService) importRecords(ctx context.Context, records []map[string]string, dryRun bool, adminID string, onProgress func(done int)) (*models.ProductImportReport, error) {
+	products, previous, report, err := s.prepareRecords(ctx, records)
+	if err != nil || dryRun {
+		report.DryRun = dryRun
+		return report, err
+	}
+
+	// Count what was written rather than what was planned.
+	report.Created, report.Updated = 0, 0
+	batchSize := max(1, s.Config.BatchSize)
+	for start := 0; start < len(products); start += batchSize {
+		end := min(start+batchSize, len(products))
+		inserted, updated, err := s.ProductRepo.BulkUpsertProducts(ctx, products[start:end])
+		report.Created += int(inserted)
+		report.Updated += int(updated)
+		if err != nil {
+			return report, err
+		}
+		for _, p := range products[start:end] {
+			before := previous[p.ID]
+			if before == nil {
+				s.ProductService.recordChange(ctx, nil, p, adminID, constants.ProductChangeImport, 0)
+			} else {
+				s.ProductService.recordChange(ctx, before, importedProduct(before, p), adminID, constants.ProductChangeImport, 0)
+			}
+		}
+		if onProgress != nil {
+			onProgress(report.Invalid + end)
+		}
+	}
+	return report, nil
+}
+
+// prepareRecords turns the valid records into products ready to upsert:
+// existing products carry their ID, new ones a fresh unique slug. The
+// stored state of each existing product is returned by ID.
+func (s *ProductImportService) prepareRecords(ctx context.Context, records []map[string]string) ([]*models.Product, map[primitive.ObjectID]*models.Product, *models.ProductImportReport, error) {
+	report := &models.ProductImportReport{Rows: len(records), Errors: []models.ProductImportError{}}
+
+	var skus, slugs []string
+	for _, record := range records {
+		if record["sku"] != "" {
+			skus = append(skus, record["sku"])
+		}
+		if record["slug"] != "" {
+			slugs = append(slugs, strings.ToLower(record["slug"]))
+		}
+	}
+	existing, err := s.ProductRepo.GetProductsBySKUsOrSlugs(ctx, skus, slugs)
+	if err != nil {
+		return nil, nil, report, err
+	}
+	bySKU := map[string]*models.Product{}
+	bySlug := map[string]*models.Product{}
+	for _, p := range existing {
+		if p.SKU != "" {
+			bySKU[p.SKU] = p
+		}
+		bySlug[p.Slug] = p
+	}
+
+	categories := map[string]*models.Category{}
+	reservedSlugs := map[string]bool{}
+	skuRows := map[string]int{}
+	productRows := map[primitive.ObjectID]int{}
+	previous := map[primitive.ObjectID]*models.Product{}
+	now := time.Now()
+	products := make([]*models.Product, 0, len(records))
+
+	for i, record := range records {
+		row := i + 1
+		var rowErrors []models.ProductImportError
+		fail := func(field, message string) {
+			rowErrors = append(rowErrors, models.ProductImportError{Row: row, Field: field, Message: message})
+		}
+
+		data := map[string]interface{}{
+			"name":        record["title"],
+			"description": record["description"],
+			"category":    record["category"],
+		}
+		price, err := models.ParseMoney(record["price"], models.DefaultCurrency)
+		if err == nil {
+			data["price"] = price.Major()
+		}
+		for _, e := range utils.ValidateProductData(data) {
+			field := e.Field
+			if field == "name" {
+				field = "title"
+			}
+			fail(field, e.Message)
+		}
+		inStock, err := strconv.Atoi(record["instock"])
+		if err != nil || inStock < 0 {
+			fail("inStock", "inStock must be a whole number of zero or more")
+		}
+
+		// Scheduling needs publish times, which imports do not carry.
+		status := strings.ToUpper(strings.TrimSpace(record["status"]))
+		switch status {
+		case "", constants.ProductStatusDraft, constants.ProductStatusActive, constants.ProductStatusArchived:
+		default:
+			fail("status", "status must be DRAFT, ACTIVE or ARCHIVED")
+		}
+
+		var category *models.Category
+		if record["category"] != "" {
+			category, err = s.lookupCategory(ctx, categories, record["category"])
+			if err != nil {
+				return nil, nil, report, err
+			}
+			if category == nil {
+				fail("category", "category does not exist")
+			}
+		}
+
+		sku := record["sku"]
+		slug := strings.ToLower(record["slug"])
+		var match *models.Product
+		if sku != "" {
+			if previous, ok := skuRows[sku]; ok {
+				fail("sku", "sku already used by row "+strconv.Itoa(previous))
+			}
+			skuRows[sku] = row
+			match = bySKU[sku]
+		}
+		if slugMatch := bySlug[slug]; slug != "" && slugMatch != nil {
+			if match != nil && match.ID != slugMatch.ID {
+				fail("slug", "sku and slug belong to different products")
+			} else if match == nil {
+				if slugMatch.SKU != "" && sku != "" && slugMatch.SKU != sku {
+					fail("sku", "product with this slug already has sku "+slugMatch.SKU)
+				}
+				match = slugMatch
+			}
+		}
+		if match != nil && match.DeletedAt != nil {
+			fail("sku", "product is deleted; restore it before importing")
+		}
+		if match != nil {
+			if previous, ok := productRows[match.ID]; ok {
+				fail("sku", "product already updated by row "+strconv.Itoa(previous))
+			}
+			productRows[match.ID] = row
+		}
+
+		images := splitImportList(record["images"])
+		if match == nil && len(images) == 0 {
+			fail("images", "new products need at least one image")
+		}
+
+		if len(rowErrors) > 0 {
+			report.Invalid++
+			if room := maxImportErrors - len(report.Errors); room > 0 {
+				report.Errors = append(report.Errors, rowErrors[:min(room, len(rowErrors))]...)
+			}
+			continue
+		}
+
+		product := &models.Product{
+			SKU:         sku,
+			Title:       strings.TrimSpace(record["title"]),
+			Description: record["description"],
+			Price:       price,
+			Category:    category.Name,
+			CategoryID:  &category.ID,
+			InStock:     inStock,
+			Images:      images,
+			Sizes:       splitImportList(record["sizes"]),
+			Tags:        splitImportList(record["tags"]),
+			Status:      status,
+			UpdatedAt:   now,
+		}
+		if match != nil {
+			product.ID = match.ID
+			previous[match.ID] = match
+			report.Updated++
+		} else {
+			source := slug
+			if source == "" {
+				source = product.Title
+			}
+			product.Slug, err = s.ProductService.uniqueSlugExcept(ctx, source, "", reservedSlugs)
+			if err != nil {
+				return nil, nil, report, err
+			}
+			reservedSlugs[product.Slug] = true
+			if product.Status == "" {
+				product.Status = constants.ProductStatusDraft
+			}
+			product.CreatedAt = now
+			report.Created++
+		}
+		report.Valid++
+		products = append(products, product)
+	}
+	return products, previous, report, nil
+}
+
+// importedProduct is the state an existing product is left in once the
+// imported row p is written over it by BulkUpsertProducts.
+func importedProduct(before, p *models.Product) *models.Product {
+	after := *before
+	after.Title = p.Title
+	after.Description = p.Description
+	after.Price = p.Price
+	after.Category = p.Category
+	after.CategoryID = p.CategoryID
+	after.InStock = p.InStock
+	if p.SKU != "" {
+		after.SKU = p.SKU
+	}
+	if p.Sizes != nil {
+		after.Sizes = p.Sizes
+	}
+	if p.Tags != nil {
+		after.Tags = p.Tags
+	}
+	if p.Status != "" {
+		after.Status = p.Status
+	}
+	return &after
+}
+
+// lookupCategory resolves a category name or slug, remembering the answer
+// for later rows. A nil category means there is no such category.
+func (s *ProductImportService) lookupCategory(ctx context.Context, cache map[string]*models.Category, name string) (*models.Category, error) {
+	slug := utils.Slugify(name)
+	if category, ok := cache[slug]; ok {
+		return category, nil
+	}
+
+	category, err := s.CategoryRepo.GetCategoryBySlug(ctx, slug)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		category, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cache[slug] = category
+	return category, nil
+}
+
+// readProductCSV parses an import file into one map per row, keyed by the
+// lower-cased column name.
+func readProductCSV(data []byte) ([]map[string]string, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidImportFile
+	}
+	columns := make([]string, len(header))
+	present := map[string]bool{}
+	for i, name := range header {
+		columns[i] = strings.ToLower(strings.TrimSpace(name))
+		present[columns[i]] = true
+	}
+	for _, required := range requiredImportColumns {
+		if !present[required] {
+			return nil, ErrInvalidImportFile
+		}
+	}
+
+	records := []map[string]string{}
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, ErrInvalidImportFile
+		}
+		record := make(map[string]string, len(columns))
+		for i, value := range values {
+			if i < len(columns) {
+				record[columns[i]] = strings.TrimSpace(value)
+			}
+		}
+		records = append(records, record)
+	}
+}
+
+// splitImportList splits a "|"-separated cell. A blank cell gives nil, which
+// leaves an existing product's list unchanged.
+func splitImportList(cell string) []string {
+	if cell == "" {
+		return nil
+	}
+	var values []string
+	for _, value := range strings.Split(cell, "|") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// reportResult stores a report as a job result.
+func reportResult(report *models.ProductImportReport) map[string]interface{} {
+	var result map[string]interface{}
+	encoded, _ := json.Marshal(report)
+	json.Unmarshal(encoded, &result)
+	return result
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+// fakeImportProductRepo matches import rows against a fixed set of products.
+type fakeImportProductRepo struct {
+	interfaces.ProductRepository
+	products []*models.Product
+}
+
+func (r *fakeImportProductRepo) GetProductsBySKUsOrSlugs(ctx context.Context, skus, slugs []string) ([]*models.Product, error) {
+	return r.products, nil
+}
+
+func (r *fakeImportProductRepo) SlugTaken(ctx context.Context, slug, excludeID string) (bool, error) {
+	for _, p := range r.products {
+		if p.Slug == slug {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestPrepareRecordsValidatesRows(t *testing.T) {
+	deletedAt := time.Now()
+	products := &fakeImportProductRepo{products: []*models.Product{
+		{ID: primitive.NewObjectID(), SKU: "TEE-1", Slug: "classic-tee"},
+		{ID: primitive.NewObjectID(), Slug: "plain-shirt"},
+		{ID: primitive.NewObjectID(), SKU: "OLD-1", Slug: "old-tee", DeletedAt: &deletedAt},
+	}}
+	tees := primitive.NewObjectID()
+	s := &ProductImportService{
+		ProductRepo:    products,
+		CategoryRepo:   &fakeCategoryRepo{categories: map[primitive.ObjectID]*models.Category{tees: {ID: tees, Name: "Tees", Slug: "tees"}}},
+		ProductService: &ProductService{Repo: products},
+	}
+
+	// row is a valid new product with the given columns changed.
+	row := func(changes ...string) map[string]string {
+		record := map[string]string{
+			"title": "Oversized Tee", "description": "Heavy cotton", "price": "799.00",
+			"category": "Tees", "instock": "12", "images": "front.jpg|back.jpg",
+		}
+		for i := 0; i+1 < len(changes); i += 2 {
+			record[changes[i]] = changes[i+1]
+		}
+		return record
+	}
+
+	cases := map[string]struct {
+		records []map[string]string
+		fields  []string
+	}{
+		"new product":               {[]map[string]string{row()}, nil},
+		"update by sku":             {[]map[string]string{row("sku", "TEE-1", "images", "")}, nil},
+		"update by slug":            {[]map[string]string{row("slug", "plain-shirt", "sku", "SHIRT-1")}, nil},
+		"category by slug":          {[]map[string]string{row("category", "tees")}, nil},
+		"short title":               {[]map[string]string{row("title", "Te")}, []string{"title"}},
+		"unparseable price":         {[]map[string]string{row("price", "cheap")}, []string{"price"}},
+		"zero price":                {[]map[string]string{row("price", "0")}, []string{"price"}},
+		"negative stock":            {[]map[string]string{row("instock", "-1")}, []string{"inStock"}},
+		"fractional stock":          {[]map[string]string{row("instock", "2.5")}, []string{"inStock"}},
+		"scheduled status":          {[]map[string]string{row("status", "SCHEDULED")}, []string{"status"}},
+		"unknown category":          {[]map[string]string{row("category", "Hats")}, []string{"category"}},
+		"missing category":          {[]map[string]string{row("category", "")}, []string{"category"}},
+		"new product without image": {[]map[string]string{row("images", "")}, []string{"images"}},
+		"sku and slug disagree":     {[]map[string]string{row("sku", "TEE-1", "slug", "plain-shirt")}, []string{"slug"}},
+		"slug has another sku":      {[]map[string]string{row("sku", "TEE-2", "slug", "classic-tee")}, []string{"sku"}},
+		"deleted product":           {[]map[string]string{row("sku", "OLD-1")}, []string{"sku"}},
+		"sku repeated":              {[]map[string]string{row("sku", "NEW-1"), row("sku", "NEW-1")}, []string{"sku"}},
+		"product updated twice":     {[]map[string]string{row("sku", "TEE-1"), row("slug", "classic-tee")}, []string{"sku"}},
+		"several problems":          {[]map[string]string{row("title", "", "price", "-5", "status", "LIVE")}, []string{"title", "title", "price", "status"}},
+	}
+	for name, tc := range cases {
+		prepared, _, report, err := s.prepareRecords(context.Background(), tc.records)
+		assert.NoError(t, err, name)
+
+		var fields []string
+		for _, e := range report.Errors {
+			fields = append(fields, e.Field)
+		}
+		assert.Equal(t, tc.fields, fields, name)
+		if tc.fields == nil {
+			assert.Len(t, prepared, len(tc.records), name)
+			assert.Zero(t, report.Invalid, name)
+		} else {
+			assert.Equal(t, 1, report.Invalid, name)
+		}
+	}
+}
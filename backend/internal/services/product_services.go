@@ -2,36 +2,110 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
 	"github.com/Shrey-Yash/Masked11/internal/models"
 	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+var (
+	ErrInvalidProductStatus   = errors.New("status must be DRAFT, SCHEDULED, ACTIVE or ARCHIVED")
+	ErrInvalidProductSchedule = errors.New("scheduled products need a publishAt, and unpublishAt must come after publishAt")
+	ErrInvalidProductPricing  = errors.New("compare-at and sale prices must be positive, and sales must end after they start")
 )
 
 type ProductService struct {
-	Repo interfaces.ProductRepository
+	Repo         interfaces.ProductRepository
+	CategoryRepo interfaces.CategoryRepository
+	HistoryRepo  interfaces.ProductHistoryRepository
 }
 
-func NewProductService(repo interfaces.ProductRepository) *ProductService {
-	return &ProductService{Repo: repo}
+func NewProductService(repo interfaces.ProductRepository, categoryRepo interfaces.CategoryRepository, historyRepo interfaces.ProductHistoryRepository) *ProductService {
+	return &ProductService{Repo: repo, CategoryRepo: categoryRepo, HistoryRepo: historyRepo}
 }
 
-func (s *ProductService) CreateProduct(p *models.Product) error {
+// CreateProduct saves a new product and records it as the first version in
+// its history. Without a status it starts as a draft, or as scheduled when
+// given a future publishAt.
+func (s *ProductService) CreateProduct(p *models.Product, adminID string) error {
+	ctx := context.Background()
+	status, err := resolveStatus(p.Status, p.PublishAt, p.UnpublishAt, time.Now())
+	if err != nil {
+		return err
+	}
+	p.Status = status
+	if err := validatePricing(p.Price, p.CompareAtPrice, p.Sales); err != nil {
+		return err
+	}
+
+	var categoryID string
+	if p.CategoryID != nil {
+		categoryID = p.CategoryID.Hex()
+	}
+	category, err := s.resolveCategory(ctx, categoryID, p.Category)
+	if err != nil {
+		return err
+	}
+	p.CategoryID = &category.ID
+	p.Category = category.Name
+
+	slug, err := s.uniqueSlug(ctx, p.Title, "")
+	if err != nil {
+		return err
+	}
+	p.Slug = slug
+	p.PreviousSlugs = nil
 	p.CreatedAt = time.Now()
 	p.UpdatedAt = time.Now()
-	return s.Repo.CreateProduct(context.Background(), p)
+	if err := s.Repo.CreateProduct(ctx, p); err != nil {
+		return err
+	}
+	s.recordChange(ctx, nil, p, adminID, constants.ProductChangeCreate, 0)
+	return nil
 }
 
+// GetProductByID returns a product in any status, for admins.
 func (s *ProductService) GetProductByID(id string) (*models.Product, error) {
 	return s.Repo.GetProductByID(context.Background(), id)
 }
 
+// GetPublishedProduct returns a product only if it is live and not deleted,
+// reporting any other product as not found.
+func (s *ProductService) GetPublishedProduct(id string) (*models.Product, error) {
+	product, err := s.Repo.GetProductByID(context.Background(), id)
+	if err == nil && !isPublished(product) {
+		return nil, mongo.ErrNoDocuments
+	}
+	return product, err
+}
+
 func (s *ProductService) GetAllProducts() ([]*models.Product, error) {
 	return s.Repo.GetAllProducts(context.Background())
 }
 
+// GetAllProductsWithFilters pages through the live products matching the
+// listing filters.
 func (s *ProductService) GetAllProductsWithFilters(filters map[string]interface{}) ([]*models.Product, int64, error) {
+	filters["status"] = constants.ProductStatusActive
+	return s.Repo.GetAllProductsWithFilters(context.Background(), filters)
+}
+
+// AdminListProducts pages through products in the status filter, or in any
+// status when it is empty, so admins can review drafts and schedules.
+func (s *ProductService) AdminListProducts(filters map[string]interface{}) ([]*models.Product, int64, error) {
+	if status, _ := filters["status"].(string); status != "" && !validProductStatus(status) {
+		return nil, 0, ErrInvalidProductStatus
+	}
 	return s.Repo.GetAllProductsWithFilters(context.Background(), filters)
 }
 
@@ -52,38 +126,497 @@ func (s *ProductService) SearchProducts(query string, page, limit int) ([]*model
 	return s.Repo.SearchProducts(context.Background(), searchFilters)
 }
 
-func (s *ProductService) GetProductsByCategory(category string, page, limit int) ([]*models.Product, int64, error) {
-	filters := map[string]interface{}{
-		"category": category,
-		"page":     page,
-		"limit":    limit,
+// GetProductsByCategory pages through the products in a visible category
+// and in all of its visible subcategories.
+func (s *ProductService) GetProductsByCategory(slug string, page, limit int) (*models.Category, []*models.Product, int64, error) {
+	ctx := context.Background()
+	category, err := s.CategoryRepo.GetCategoryBySlug(ctx, strings.ToLower(slug))
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && !category.Visible) {
+		return nil, nil, 0, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, nil, 0, err
 	}
 
-	return s.Repo.GetProductsByCategory(context.Background(), filters)
+	categoryIDs, err := s.CategoryRepo.GetDescendantIDs(ctx, category.ID, true)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	filters := map[string]interface{}{
+		"categoryIds": categoryIDs,
+		"page":        page,
+		"limit":       limit,
+	}
+	products, total, err := s.Repo.GetProductsByCategory(ctx, filters)
+	return category, products, total, err
 }
 
+// GetFeaturedProducts returns the curated featured list, falling back to the
+// best sellers while nothing is scheduled.
 func (s *ProductService) GetFeaturedProducts(limit int) ([]*models.Product, error) {
-	return s.Repo.GetFeaturedProducts(context.Background(), limit)
+	products, err := s.Repo.GetFeaturedProducts(context.Background(), limit)
+	if err != nil || len(products) > 0 {
+		return products, err
+	}
+	return s.Repo.GetBestSellers(context.Background(), limit)
 }
 
-func (s *ProductService) GetProductCategories() ([]string, error) {
-	return s.Repo.GetProductCategories(context.Background())
+// UpdateProduct applies updates to a product and records what changed in
+// its history. A new title, or an explicit slug, gives the product a new
+// slug; the old one is kept for redirects. Uploaded images are managed
+// through the MediaService instead.
+func (s *ProductService) UpdateProduct(id string, updates map[string]interface{}, adminID string) error {
+	_, err := s.updateProduct(context.Background(), id, updates, adminID, constants.ProductChangeUpdate, 0)
+	return err
 }
 
-func (s *ProductService) UpdateProduct(id string, updates map[string]interface{}) error {
+// updateProduct applies updates and returns the history version recorded
+// for them, or nil when no tracked field changed.
+func (s *ProductService) updateProduct(ctx context.Context, id string, updates map[string]interface{}, adminID, action string, rolledBackTo int) (*models.ProductVersion, error) {
+	before, err := findProduct(ctx, s.Repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(updates, "previousSlugs")
+	delete(updates, "uploads")
+	delete(updates, "deletedAt")
+	if err := applyStatusChange(before, updates); err != nil {
+		return nil, err
+	}
+	if err := applyPricingChange(updates); err != nil {
+		return nil, err
+	}
+	if err := s.applySlugChange(ctx, before, updates); err != nil {
+		return nil, err
+	}
+	if err := s.applyCategoryChange(ctx, updates); err != nil {
+		return nil, err
+	}
 	updates["updatedAt"] = time.Now()
-	return s.Repo.UpdateProduct(context.Background(), id, updates)
+	if err := s.Repo.UpdateProduct(ctx, id, updates); err != nil {
+		return nil, err
+	}
+
+	after, err := s.Repo.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.recordChange(ctx, before, after, adminID, action, rolledBackTo), nil
+}
+
+// GetProductBySlug looks a live product up by slug. moved is true when slug
+// is one the product used to have, so the caller should redirect.
+func (s *ProductService) GetProductBySlug(slug string) (product *models.Product, moved bool, err error) {
+	product, err = s.Repo.GetProductBySlug(context.Background(), strings.ToLower(slug))
+	if err != nil {
+		return nil, false, err
+	}
+	if !isPublished(product) {
+		return nil, false, mongo.ErrNoDocuments
+	}
+	return product, product.Slug != strings.ToLower(slug), nil
+}
+
+// BackfillSlugs gives every product created before slugs existed one.
+func (s *ProductService) BackfillSlugs(ctx context.Context) error {
+	for {
+		products, err := s.Repo.GetProductsWithoutSlug(ctx, 100)
+		if err != nil || len(products) == 0 {
+			return err
+		}
+		for _, p := range products {
+			slug, err := s.uniqueSlug(ctx, p.Title, p.ID.Hex())
+			if err != nil {
+				return err
+			}
+			if err := s.Repo.UpdateProduct(ctx, p.ID.Hex(), map[string]interface{}{"slug": slug}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// BackfillStatuses marks every product created before statuses existed as
+// active, since all of them were live.
+func (s *ProductService) BackfillStatuses(ctx context.Context) error {
+	activated, err := s.Repo.ActivateLegacyProducts(ctx)
+	if activated > 0 {
+		log.Printf("Marked %d existing products active", activated)
+	}
+	return err
+}
+
+// StartPublisher applies publishAt and unpublishAt schedules every interval,
+// calling onChange whenever products went live or were taken down so cached
+// listings can be dropped.
+func (s *ProductService) StartPublisher(interval time.Duration, onChange func()) {
+	runEvery(interval, "product publishing", func(ctx context.Context) error {
+		published, unpublished, err := s.Repo.ApplyProductSchedules(ctx, time.Now())
+		if published+unpublished > 0 {
+			log.Printf("Published %d and archived %d scheduled products", published, unpublished)
+			onChange()
+		}
+		return err
+	})
+}
+
+// applyStatusChange settles a new status, publishAt or unpublishAt in
+// updates against the rest of the product's schedule.
+func applyStatusChange(product *models.Product, updates map[string]interface{}) error {
+	rawStatus, hasStatus := updates["status"]
+	rawPublishAt, hasPublishAt := updates["publishAt"]
+	rawUnpublishAt, hasUnpublishAt := updates["unpublishAt"]
+	if !hasStatus && !hasPublishAt && !hasUnpublishAt {
+		return nil
+	}
+
+	var err error
+	status, publishAt, unpublishAt := product.Status, product.PublishAt, product.UnpublishAt
+	if hasStatus {
+		var ok bool
+		if status, ok = rawStatus.(string); !ok {
+			return ErrInvalidProductStatus
+		}
+	}
+	if hasPublishAt {
+		if publishAt, err = parseScheduleTime(rawPublishAt); err != nil {
+			return err
+		}
+	}
+	if hasUnpublishAt {
+		if unpublishAt, err = parseScheduleTime(rawUnpublishAt); err != nil {
+			return err
+		}
+	}
+
+	status, err = resolveStatus(status, publishAt, unpublishAt, time.Now())
+	if err != nil {
+		return err
+	}
+	updates["status"] = status
+	updates["publishAt"] = publishAt
+	updates["unpublishAt"] = unpublishAt
+	return nil
+}
+
+// resolveStatus settles a product's status against its schedule at now.
+// Without a status, a future publishAt schedules the product and anything
+// else leaves it a draft. A scheduled or active product whose times have
+// already passed moves straight on to active or archived.
+func resolveStatus(status string, publishAt, unpublishAt *time.Time, now time.Time) (string, error) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return "", ErrInvalidProductSchedule
+	}
+
+	switch status {
+	case "":
+		if publishAt != nil && publishAt.After(now) {
+			return constants.ProductStatusScheduled, nil
+		}
+		return constants.ProductStatusDraft, nil
+	case constants.ProductStatusDraft, constants.ProductStatusArchived:
+		return status, nil
+	case constants.ProductStatusScheduled:
+		if publishAt == nil {
+			return "", ErrInvalidProductSchedule
+		}
+	case constants.ProductStatusActive:
+	default:
+		return "", ErrInvalidProductStatus
+	}
+
+	if publishAt != nil && publishAt.After(now) {
+		return constants.ProductStatusScheduled, nil
+	}
+	if unpublishAt != nil && !unpublishAt.After(now) {
+		return constants.ProductStatusArchived, nil
+	}
+	return constants.ProductStatusActive, nil
 }
 
+// parseScheduleTime reads a publishAt or unpublishAt from a JSON update,
+// where null or an empty string clears it.
+func parseScheduleTime(value interface{}) (*time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, ErrInvalidProductSchedule
+		}
+		return &t, nil
+	}
+	return nil, ErrInvalidProductSchedule
+}
+
+// applyPricingChange checks a new price, compareAtPrice or sales in updates
+// and converts them to the types the product stores. null clears a
+// compareAtPrice or sales.
+func applyPricingChange(updates map[string]interface{}) error {
+	if raw, ok := updates["price"]; ok {
+		price, ok := moneyValue(raw)
+		if !ok || !price.IsPositive() || !inStoreCurrency(price) {
+			return ErrInvalidProductPricing
+		}
+		updates["price"] = price
+	}
+	if raw, ok := updates["compareAtPrice"]; ok && raw != nil {
+		price, ok := moneyValue(raw)
+		if !ok || !price.IsPositive() || !inStoreCurrency(price) {
+			return ErrInvalidProductPricing
+		}
+		updates["compareAtPrice"] = price
+	}
+
+	raw, ok := updates["sales"]
+	if !ok || raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return ErrInvalidProductPricing
+	}
+	var sales []models.ProductSale
+	if err := json.Unmarshal(data, &sales); err != nil {
+		return ErrInvalidProductPricing
+	}
+	for _, sale := range sales {
+		if validate.Struct(sale) != nil || !inStoreCurrency(sale.Price) {
+			return ErrInvalidProductPricing
+		}
+	}
+	updates["sales"] = sales
+	return nil
+}
+
+// validatePricing checks a product's price, compare-at price and sales. All
+// of them must be in the store's currency.
+func validatePricing(price models.Money, compareAtPrice *models.Money, sales []models.ProductSale) error {
+	if !price.IsPositive() || !inStoreCurrency(price) {
+		return ErrInvalidProductPricing
+	}
+	if compareAtPrice != nil && (!compareAtPrice.IsPositive() || !inStoreCurrency(*compareAtPrice)) {
+		return ErrInvalidProductPricing
+	}
+	for _, sale := range sales {
+		if validate.Struct(sale) != nil || !inStoreCurrency(sale.Price) {
+			return ErrInvalidProductPricing
+		}
+	}
+	return nil
+}
+
+// moneyValue reads an amount from a JSON update or a history entry: a money
+// object, or a plain number of major units as amounts were given before
+// they carried a currency.
+func moneyValue(value interface{}) (models.Money, bool) {
+	switch v := value.(type) {
+	case models.Money:
+		return v, true
+	case float64:
+		return models.MoneyFromMajor(v, models.DefaultCurrency), true
+	case int32:
+		return models.MoneyFromMajor(float64(v), models.DefaultCurrency), true
+	case int64:
+		return models.MoneyFromMajor(float64(v), models.DefaultCurrency), true
+	case map[string]interface{}, primitive.M, primitive.D:
+		data, err := json.Marshal(historyValue(v))
+		if err != nil {
+			return models.Money{}, false
+		}
+		var m models.Money
+		if err := json.Unmarshal(data, &m); err != nil {
+			return models.Money{}, false
+		}
+		return m, true
+	}
+	return models.Money{}, false
+}
+
+func validProductStatus(status string) bool {
+	switch status {
+	case constants.ProductStatusDraft, constants.ProductStatusScheduled,
+		constants.ProductStatusActive, constants.ProductStatusArchived:
+		return true
+	}
+	return false
+}
+
+// isPublished reports whether a product may be shown publicly.
+func isPublished(p *models.Product) bool {
+	return p.Status == constants.ProductStatusActive && p.DeletedAt == nil
+}
+
+func (s *ProductService) applySlugChange(ctx context.Context, product *models.Product, updates map[string]interface{}) error {
+	source, _ := updates["slug"].(string)
+	if source == "" {
+		source, _ = updates["title"].(string)
+	}
+	delete(updates, "slug")
+	if source == "" {
+		return nil
+	}
+
+	slug, err := s.uniqueSlug(ctx, source, product.ID.Hex())
+	if err != nil || slug == product.Slug {
+		return err
+	}
+
+	history := make([]string, 0, len(product.PreviousSlugs)+1)
+	for _, previous := range product.PreviousSlugs {
+		if previous != slug {
+			history = append(history, previous)
+		}
+	}
+	if product.Slug != "" {
+		history = append(history, product.Slug)
+	}
+	updates["slug"] = slug
+	updates["previousSlugs"] = history
+	return nil
+}
+
+// applyCategoryChange resolves a new categoryId or category name in updates
+// and stores both the category's ID and its name.
+func (s *ProductService) applyCategoryChange(ctx context.Context, updates map[string]interface{}) error {
+	categoryID, hasID := updates["categoryId"]
+	name, hasName := updates["category"]
+	if !hasID && !hasName {
+		return nil
+	}
+
+	id, _ := categoryID.(string)
+	nameStr, _ := name.(string)
+	category, err := s.resolveCategory(ctx, id, nameStr)
+	if err != nil {
+		return err
+	}
+	updates["categoryId"] = category.ID
+	updates["category"] = category.Name
+	return nil
+}
+
+// resolveCategory finds a product's category by ID or, failing that, by the
+// slug of its name.
+func (s *ProductService) resolveCategory(ctx context.Context, id, name string) (*models.Category, error) {
+	var (
+		category *models.Category
+		err      error
+	)
+	if id != "" {
+		category, err = s.CategoryRepo.GetCategoryByID(ctx, id)
+	} else {
+		category, err = s.CategoryRepo.GetCategoryBySlug(ctx, utils.Slugify(name))
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// uniqueSlug slugifies source and, if another product already uses or used
+// that slug, appends the first free numeric suffix.
+func (s *ProductService) uniqueSlug(ctx context.Context, source, productID string) (string, error) {
+	return s.uniqueSlugExcept(ctx, source, productID, nil)
+}
+
+// uniqueSlugExcept is uniqueSlug that also avoids the reserved slugs, which
+// are about to be used by products not saved yet.
+func (s *ProductService) uniqueSlugExcept(ctx context.Context, source, productID string, reserved map[string]bool) (string, error) {
+	base := utils.Slugify(source)
+	if base == "" {
+		base = "product"
+	}
+
+	slug := base
+	for n := 2; ; n++ {
+		if !reserved[slug] {
+			taken, err := s.Repo.SlugTaken(ctx, slug, productID)
+			if err != nil || !taken {
+				return slug, err
+			}
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// DeleteProduct soft deletes a product. It can be restored until the purge
+// job removes it after the retention period.
 func (s *ProductService) DeleteProduct(id string) error {
-	return s.Repo.DeleteProduct(context.Background(), id)
+	err := s.Repo.DeleteProduct(context.Background(), id)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return ErrProductNotFound
+	}
+	return err
+}
+
+// RestoreProduct brings back a soft deleted product in the status it had.
+func (s *ProductService) RestoreProduct(id string) error {
+	err := s.Repo.RestoreProduct(context.Background(), id)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return ErrProductNotFound
+	}
+	return err
+}
+
+// GetDeletedProducts pages through the soft deleted products that can still
+// be restored.
+func (s *ProductService) GetDeletedProducts(page, limit int) ([]*models.Product, int64, error) {
+	return s.Repo.GetDeletedProducts(context.Background(), map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// StartPurger permanently removes products deleted longer than retention
+// ago, every interval. onPurge is called with each removed product so its
+// stored files can be cleaned up.
+func (s *ProductService) StartPurger(interval, retention time.Duration, onPurge func(*models.Product)) {
+	runEvery(interval, "deleted product purge", func(ctx context.Context) error {
+		return s.PurgeDeletedProducts(ctx, time.Now().Add(-retention), onPurge)
+	})
+}
+
+// PurgeDeletedProducts permanently removes the products deleted before the
+// given time.
+func (s *ProductService) PurgeDeletedProducts(ctx context.Context, before time.Time, onPurge func(*models.Product)) error {
+	purged := 0
+	defer func() {
+		if purged > 0 {
+			log.Printf("Purged %d deleted products", purged)
+		}
+	}()
+
+	for {
+		products, err := s.Repo.GetProductsDeletedBefore(ctx, before, 100)
+		if err != nil || len(products) == 0 {
+			return err
+		}
+		for _, p := range products {
+			removed, err := s.Repo.PurgeProduct(ctx, p.ID, before)
+			if err != nil {
+				return err
+			}
+			if removed {
+				purged++
+				onPurge(p)
+			}
+		}
+	}
 }
 
 // GetProductsByPriceRange returns products within a specific price range
-func (s *ProductService) GetProductsByPriceRange(minPrice, maxPrice float64, page, limit int) ([]*models.Product, int64, error) {
+func (s *ProductService) GetProductsByPriceRange(minPrice, maxPrice models.Money, page, limit int) ([]*models.Product, int64, error) {
 	filters := map[string]interface{}{
-		"minPrice": minPrice,
-		"maxPrice": maxPrice,
+		"minPrice": minPrice.Amount,
+		"maxPrice": maxPrice.Amount,
 		"page":     page,
 		"limit":    limit,
 	}
@@ -121,7 +654,65 @@ func (s *ProductService) UpdateProductStock(productID string, quantity int) erro
 	return s.Repo.UpdateProduct(context.Background(), productID, updates)
 }
 
-// GetRelatedProducts returns products related to a given product
+// GetRelatedProducts returns products similar to a given product by category,
+// tags and price, topped up with best sellers when there are too few.
 func (s *ProductService) GetRelatedProducts(productID string, limit int) ([]*models.Product, error) {
-	return s.Repo.GetRelatedProducts(context.Background(), productID, limit)
+	ctx := context.Background()
+	related, err := s.Repo.GetRelatedProducts(ctx, productID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(related) >= limit {
+		return related, nil
+	}
+
+	bestSellers, err := s.Repo.GetBestSellers(ctx, limit+len(related)+1)
+	if err != nil {
+		return nil, err
+	}
+	return mergeProducts(productID, limit, related, bestSellers), nil
+}
+
+// GetBoughtTogether returns products frequently bought with a given product,
+// topped up with related products while co-purchase data is sparse.
+func (s *ProductService) GetBoughtTogether(productID string, limit int) ([]*models.Product, error) {
+	ctx := context.Background()
+	if _, err := s.Repo.GetProductByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	together, err := s.Repo.GetBoughtTogether(ctx, productID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(together) >= limit {
+		return together, nil
+	}
+
+	related, err := s.GetRelatedProducts(productID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return mergeProducts(productID, limit, together, related), nil
+}
+
+// mergeProducts concatenates product lists in order, dropping duplicates and
+// the product being viewed, up to limit products.
+func mergeProducts(excludeID string, limit int, lists ...[]*models.Product) []*models.Product {
+	seen := map[string]bool{excludeID: true}
+	merged := make([]*models.Product, 0, limit)
+	for _, list := range lists {
+		for _, p := range list {
+			if len(merged) == limit {
+				return merged
+			}
+			id := p.ID.Hex()
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			merged = append(merged, p)
+		}
+	}
+	return merged
 }
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+)
+
+func TestResolveStatus(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	past, future, later := now.Add(-time.Hour), now.Add(time.Hour), now.Add(2*time.Hour)
+
+	cases := map[string]struct {
+		status      string
+		publishAt   *time.Time
+		unpublishAt *time.Time
+		want        string
+		err         error
+	}{
+		"no status":                  {"", nil, nil, constants.ProductStatusDraft, nil},
+		"no status, future publish":  {"", &future, nil, constants.ProductStatusScheduled, nil},
+		"no status, past publish":    {"", &past, nil, constants.ProductStatusDraft, nil},
+		"draft keeps its schedule":   {"draft", &future, nil, constants.ProductStatusDraft, nil},
+		"archived":                   {" Archived ", nil, nil, constants.ProductStatusArchived, nil},
+		"active":                     {"ACTIVE", nil, nil, constants.ProductStatusActive, nil},
+		"active before publish":      {"ACTIVE", &future, nil, constants.ProductStatusScheduled, nil},
+		"active after unpublish":     {"ACTIVE", nil, &past, constants.ProductStatusArchived, nil},
+		"active until unpublish":     {"ACTIVE", &past, &future, constants.ProductStatusActive, nil},
+		"unpublish exactly now":      {"ACTIVE", nil, &now, constants.ProductStatusArchived, nil},
+		"scheduled":                  {"SCHEDULED", &future, &later, constants.ProductStatusScheduled, nil},
+		"scheduled, publish passed":  {"SCHEDULED", &past, nil, constants.ProductStatusActive, nil},
+		"scheduled, window passed":   {"SCHEDULED", &past, &now, constants.ProductStatusArchived, nil},
+		"scheduled without publish":  {"SCHEDULED", nil, &future, "", ErrInvalidProductSchedule},
+		"unpublish before publish":   {"ACTIVE", &later, &future, "", ErrInvalidProductSchedule},
+		"unpublish equal to publish": {"", &future, &future, "", ErrInvalidProductSchedule},
+		"unknown status":             {"LIVE", nil, nil, "", ErrInvalidProductStatus},
+	}
+	for name, tc := range cases {
+		got, err := resolveStatus(tc.status, tc.publishAt, tc.unpublishAt, now)
+		assert.Equal(t, tc.err, err, name)
+		assert.Equal(t, tc.want, got, name)
+	}
+}
+
+func TestApplyStatusChange(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	futureText := future.Format(time.RFC3339)
+	scheduled := func() *models.Product {
+		publishAt := future
+		return &models.Product{Status: constants.ProductStatusScheduled, PublishAt: &publishAt}
+	}
+
+	cases := map[string]struct {
+		product *models.Product
+		updates map[string]interface{}
+		want    map[string]interface{}
+		err     error
+	}{
+		"unrelated update": {
+			product: scheduled(),
+			updates: map[string]interface{}{"title": "Tee"},
+			want:    map[string]interface{}{"title": "Tee"},
+		},
+		"status only keeps the schedule": {
+			product: scheduled(),
+			updates: map[string]interface{}{"status": "ACTIVE"},
+			want:    map[string]interface{}{"status": constants.ProductStatusScheduled, "publishAt": &future, "unpublishAt": (*time.Time)(nil)},
+		},
+		"clearing publishAt makes it live": {
+			product: scheduled(),
+			updates: map[string]interface{}{"status": "ACTIVE", "publishAt": nil},
+			want:    map[string]interface{}{"status": constants.ProductStatusActive, "publishAt": (*time.Time)(nil), "unpublishAt": (*time.Time)(nil)},
+		},
+		"publishAt on a draft schedules it": {
+			product: &models.Product{},
+			updates: map[string]interface{}{"publishAt": futureText},
+			want:    map[string]interface{}{"status": constants.ProductStatusScheduled, "publishAt": &future, "unpublishAt": (*time.Time)(nil)},
+		},
+		"unpublishAt before the stored publishAt": {
+			product: scheduled(),
+			updates: map[string]interface{}{"unpublishAt": future.Add(-time.Hour).Format(time.RFC3339)},
+			err:     ErrInvalidProductSchedule,
+		},
+		"malformed time": {
+			product: scheduled(),
+			updates: map[string]interface{}{"publishAt": "tomorrow"},
+			err:     ErrInvalidProductSchedule,
+		},
+		"status that is not a string": {
+			product: scheduled(),
+			updates: map[string]interface{}{"status": 1},
+			err:     ErrInvalidProductStatus,
+		},
+	}
+	for name, tc := range cases {
+		err := applyStatusChange(tc.product, tc.updates)
+		assert.Equal(t, tc.err, err, name)
+		if tc.err == nil {
+			assert.Equal(t, tc.want, tc.updates, name)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+var (
+	ErrQuestionNotFound     = errors.New("question not found")
+	ErrAnswerNotFound       = errors.New("answer not found")
+	ErrInvalidQuestion      = errors.New("question must be between 10 and 1000 characters")
+	ErrInvalidAnswer        = errors.New("answer must be between 2 and 2000 characters")
+	ErrAnswerNotAllowed     = errors.New("only customers who received this product can answer questions about it")
+	ErrAnswerNotModeratable = errors.New("answer has already been moderated")
+	ErrOwnAnswerVote        = errors.New("you cannot vote on your own answer")
+)
+
+type QuestionService struct {
+	QuestionRepo interfaces.QuestionRepository
+	OrderRepo    interfaces.OrderRepository
+	ProductRepo  interfaces.ProductRepository
+	UserRepo     interfaces.UserRepository
+	Notifier     Notifier
+}
+
+func NewQuestionService(questionRepo interfaces.QuestionRepository, orderRepo interfaces.OrderRepository, productRepo interfaces.ProductRepository, userRepo interfaces.UserRepository, notifier Notifier) *QuestionService {
+	return &QuestionService{
+		QuestionRepo: questionRepo,
+		OrderRepo:    orderRepo,
+		ProductRepo:  productRepo,
+		UserRepo:     userRepo,
+		Notifier:     notifier,
+	}
+}
+
+func (s *QuestionService) AskQuestion(ctx context.Context, userID, productID string, req models.QuestionRequest) (*models.Question, error) {
+	req.Body = strings.TrimSpace(req.Body)
+	if err := validate.Struct(req); err != nil {
+		return nil, ErrInvalidQuestion
+	}
+
+	product, err := findProduct(ctx, s.ProductRepo, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	question := &models.Question{
+		ProductID: product.ID,
+		UserID:    userID,
+		UserName:  displayName(s.UserRepo, userID),
+		Body:      req.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.QuestionRepo.CreateQuestion(ctx, question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// GetProductQuestions pages through a product's questions, newest first,
+// each with its most upvoted answer.
+func (s *QuestionService) GetProductQuestions(ctx context.Context, productID string, page, limit int) ([]*models.Question, int64, error) {
+	if _, err := findProduct(ctx, s.ProductRepo, productID); err != nil {
+		return nil, 0, err
+	}
+	questions, total, err := s.QuestionRepo.ListQuestions(ctx, map[string]interface{}{
+		"productId": productID,
+		"page":      page,
+		"limit":     limit,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return questions, total, s.attachTopAnswers(ctx, questions)
+}
+
+// GetTopAnsweredQuestions returns the product's most answered questions for
+// the product page, each with its most upvoted answer.
+func (s *QuestionService) GetTopAnsweredQuestions(ctx context.Context, productID string, limit int) ([]*models.Question, error) {
+	if _, err := findProduct(ctx, s.ProductRepo, productID); err != nil {
+		return nil, err
+	}
+	questions, _, err := s.QuestionRepo.ListQuestions(ctx, map[string]interface{}{
+		"productId":    productID,
+		"answeredOnly": true,
+		"sortBy":       "answered",
+		"page":         1,
+		"limit":        limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return questions, s.attachTopAnswers(ctx, questions)
+}
+
+func (s *QuestionService) attachTopAnswers(ctx context.Context, questions []*models.Question) error {
+	for _, question := range questions {
+		if question.AnswerCount == 0 {
+			continue
+		}
+		answers, _, err := s.QuestionRepo.ListAnswers(ctx, map[string]interface{}{
+			"questionId": question.ID.Hex(),
+			"status":     constants.AnswerStatusApproved,
+			"page":       1,
+			"limit":      1,
+		})
+		if err != nil {
+			return err
+		}
+		if len(answers) > 0 {
+			question.TopAnswer = answers[0]
+		}
+	}
+	return nil
+}
+
+// GetAnswers pages through a question's approved answers, most upvoted first.
+func (s *QuestionService) GetAnswers(ctx context.Context, questionID string, page, limit int) ([]*models.Answer, int64, error) {
+	if _, err := s.QuestionRepo.GetQuestionByID(ctx, questionID); err != nil {
+		return nil, 0, ErrQuestionNotFound
+	}
+	return s.QuestionRepo.ListAnswers(ctx, map[string]interface{}{
+		"questionId": questionID,
+		"status":     constants.AnswerStatusApproved,
+		"page":       page,
+		"limit":      limit,
+	})
+}
+
+// AnswerQuestion submits a customer's answer for moderation. Only customers
+// with a delivered order for the product may answer.
+func (s *QuestionService) AnswerQuestion(ctx context.Context, userID, questionID string, req models.AnswerRequest) (*models.Answer, error) {
+	question, err := s.answerableQuestion(ctx, questionID, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Orders are owned by the customer's cart key rather than their bare ID.
+	_, err = s.OrderRepo.GetDeliveredOrderID(ctx, utils.UserScopedKey(userID, "cart"), question.ProductID.Hex())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAnswerNotAllowed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	answer := newAnswer(question, userID, displayName(s.UserRepo, userID), req.Body)
+	answer.VerifiedBuyer = true
+	answer.Status = constants.AnswerStatusPending
+	if err := s.QuestionRepo.CreateAnswer(ctx, answer); err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+// AnswerAsStore posts an answer on behalf of the store. It skips moderation
+// and is published immediately.
+func (s *QuestionService) AnswerAsStore(ctx context.Context, adminID, questionID string, req models.AnswerRequest) (*models.Answer, error) {
+	question, err := s.answerableQuestion(ctx, questionID, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := newAnswer(question, adminID, displayName(s.UserRepo, adminID), req.Body)
+	answer.FromStore = true
+	answer.Status = constants.AnswerStatusApproved
+	if err := s.QuestionRepo.CreateAnswer(ctx, answer); err != nil {
+		return nil, err
+	}
+	if err := s.publishAnswer(ctx, question, answer); err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+func (s *QuestionService) answerableQuestion(ctx context.Context, questionID string, req *models.AnswerRequest) (*models.Question, error) {
+	req.Body = strings.TrimSpace(req.Body)
+	if err := validate.Struct(req); err != nil {
+		return nil, ErrInvalidAnswer
+	}
+
+	question, err := s.QuestionRepo.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		return nil, ErrQuestionNotFound
+	}
+	return question, nil
+}
+
+func newAnswer(question *models.Question, userID, userName, body string) *models.Answer {
+	now := time.Now()
+	return &models.Answer{
+		QuestionID: question.ID,
+		ProductID:  question.ProductID,
+		UserID:     userID,
+		UserName:   userName,
+		Body:       body,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// GetModerationQueue lists answers by status, oldest first, pending ones by
+// default.
+func (s *QuestionService) GetModerationQueue(ctx context.Context, status string, page, limit int) ([]*models.Answer, int64, error) {
+	if status == "" {
+		status = constants.AnswerStatusPending
+	}
+	return s.QuestionRepo.ListAnswers(ctx, map[string]interface{}{
+		"status": status,
+		"sortBy": "oldest",
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+func (s *QuestionService) ApproveAnswer(ctx context.Context, answerID, adminID string) (*models.Answer, error) {
+	return s.moderateAnswer(ctx, answerID, constants.AnswerStatusApproved, adminID)
+}
+
+func (s *QuestionService) RejectAnswer(ctx context.Context, answerID, adminID string) (*models.Answer, error) {
+	return s.moderateAnswer(ctx, answerID, constants.AnswerStatusRejected, adminID)
+}
+
+func (s *QuestionService) moderateAnswer(ctx context.Context, answerID, status, adminID string) (*models.Answer, error) {
+	answer, err := s.QuestionRepo.GetAnswerByID(ctx, answerID)
+	if err != nil {
+		return nil, ErrAnswerNotFound
+	}
+	if answer.Status != constants.AnswerStatusPending {
+		return nil, ErrAnswerNotModeratable
+	}
+
+	now := time.Now()
+	answer.Status = status
+	answer.ModeratedBy = adminID
+	answer.ModeratedAt = &now
+	answer.UpdatedAt = now
+	if err := s.QuestionRepo.UpdateAnswerStatus(ctx, answer); err != nil {
+		return nil, err
+	}
+
+	if status == constants.AnswerStatusApproved {
+		question, err := s.QuestionRepo.GetQuestionByID(ctx, answer.QuestionID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		if err := s.publishAnswer(ctx, question, answer); err != nil {
+			return nil, err
+		}
+	}
+	return answer, nil
+}
+
+// publishAnswer counts a newly visible answer against its question and lets
+// the asker know. A failed notification does not undo the answer.
+func (s *QuestionService) publishAnswer(ctx context.Context, question *models.Question, answer *models.Answer) error {
+	if err := s.QuestionRepo.IncrementAnswerCount(ctx, question.ID.Hex(), 1); err != nil {
+		return err
+	}
+	if question.UserID == answer.UserID {
+		return nil
+	}
+
+	err := s.Notifier.Notify(ctx, models.Notification{
+		UserID: question.UserID,
+		Type:   constants.NotificationQuestionAnswered,
+		Title:  "Your question has a new answer",
+		Body:   truncate(answer.Body, 200),
+		Link:   fmt.Sprintf("/products/%s?question=%s", question.ProductID.Hex(), question.ID.Hex()),
+		Data: map[string]string{
+			"productId":  question.ProductID.Hex(),
+			"questionId": question.ID.Hex(),
+			"answerId":   answer.ID.Hex(),
+		},
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("Failed to notify %s about answer %s: %v", question.UserID, answer.ID.Hex(), err)
+	}
+	return nil
+}
+
+// UpvoteAnswer records a customer's upvote on an approved answer.
+func (s *QuestionService) UpvoteAnswer(ctx context.Context, answerID, userID string) error {
+	answer, err := s.QuestionRepo.GetAnswerByID(ctx, answerID)
+	if err != nil || answer.Status != constants.AnswerStatusApproved {
+		return ErrAnswerNotFound
+	}
+	if answer.UserID == userID {
+		return ErrOwnAnswerVote
+	}
+	return s.QuestionRepo.AddAnswerVote(ctx, answerID, userID)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+const recentlyViewedList = "recently_viewed"
+
+type RecentlyViewedService struct {
+	Repo        interfaces.RecentlyViewedRepository
+	ProductRepo interfaces.ProductRepository
+	Config      config.RecentlyViewedConfig
+}
+
+func NewRecentlyViewedService(repo interfaces.RecentlyViewedRepository, productRepo interfaces.ProductRepository, cfg config.RecentlyViewedConfig) *RecentlyViewedService {
+	return &RecentlyViewedService{
+		Repo:        repo,
+		ProductRepo: productRepo,
+		Config:      cfg,
+	}
+}
+
+func (s *RecentlyViewedService) RecordView(key, productID string) error {
+	return s.Repo.Record(key, productID, s.Config.Limit, s.Config.TTL)
+}
+
+// GetRecentlyViewed returns the viewed products most recent first, skipping
+// any that have since been deleted or taken down.
+func (s *RecentlyViewedService) GetRecentlyViewed(key string, limit int) ([]*models.Product, error) {
+	ids, err := s.Repo.List(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []*models.Product{}, nil
+	}
+
+	found, err := s.ProductRepo.GetProductsByIDs(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Product, len(found))
+	for _, p := range found {
+		byID[p.ID.Hex()] = p
+	}
+
+	products := make([]*models.Product, 0, len(found))
+	for _, id := range ids {
+		if len(products) == limit {
+			break
+		}
+		if p, ok := byID[id]; ok && isPublished(p) {
+			products = append(products, p)
+		}
+	}
+	return products, nil
+}
+
+// MergeGuestHistory folds a guest session's views into the user's history
+// when they sign in.
+func (s *RecentlyViewedService) MergeGuestHistory(sessionID, userID string) error {
+	return s.Repo.Merge(
+		utils.GuestScopedKey(sessionID, recentlyViewedList),
+		utils.UserScopedKey(userID, recentlyViewedList),
+		s.Config.Limit,
+		s.Config.TTL,
+	)
+}
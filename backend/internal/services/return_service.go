@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+var (
+	ErrReturnNotAllowed    = errors.New("order is not eligible for return")
+	ErrReturnWindowExpired = errors.New("return window has expired")
+	ErrReturnQuantity      = errors.New("invalid return quantity")
+	ErrReturnReason        = errors.New("a reason is required for returns")
+	ErrOrderItemNotFound   = errors.New("order item not found")
+	ErrReturnForbidden     = errors.New("unauthorized access to this return")
+)
+
+// returnTransitions lists the statuses a return may move to from each state.
+var returnTransitions = map[string][]string{
+	constants.ReturnStatusRequested: {constants.ReturnStatusApproved, constants.ReturnStatusRejected},
+	constants.ReturnStatusApproved:  {constants.ReturnStatusReceived},
+	constants.ReturnStatusReceived:  {constants.ReturnStatusRefunded},
+}
+
+type ReturnService struct {
+	ReturnRepo     interfaces.ReturnRepository
+	OrderRepo      interfaces.OrderRepository
+	ProductRepo    interfaces.ProductRepository
+	PaymentService *PaymentService
+	Config         config.ReturnsConfig
+}
+
+func NewReturnService(returnRepo interfaces.ReturnRepository, orderRepo interfaces.OrderRepository, productRepo interfaces.ProductRepository, paymentService *PaymentService, cfg config.ReturnsConfig) *ReturnService {
+	return &ReturnService{
+		ReturnRepo:     returnRepo,
+		OrderRepo:      orderRepo,
+		ProductRepo:    productRepo,
+		PaymentService: paymentService,
+		Config:         cfg,
+	}
+}
+
+// RequestReturn opens a return for some or all units of a delivered order
+// item. The units left to return are checked again under a lock on the order
+// line when the return is stored.
+func (s *ReturnService) RequestReturn(ctx context.Context, userID, orderID, orderItemID string, quantity int, reason string) (*models.ReturnRequest, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReturnReason
+	}
+
+	order, err := s.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrReturnForbidden
+	}
+	if order.Status != constants.OrderStatusDelivered || order.DeliveredAt == nil {
+		return nil, ErrReturnNotAllowed
+	}
+	if time.Since(*order.DeliveredAt) > s.Config.Window {
+		return nil, ErrReturnWindowExpired
+	}
+
+	item := findOrderItem(order, orderItemID)
+	if item == nil {
+		return nil, ErrOrderItemNotFound
+	}
+	// Cancelled lines were refunded when they were cancelled.
+	if item.Status != constants.OrderItemStatusActive {
+		return nil, ErrReturnNotAllowed
+	}
+
+	returned, err := s.ReturnRepo.GetReturnedQuantity(ctx, orderItemID)
+	if err != nil {
+		return nil, err
+	}
+	if quantity < 1 || quantity > item.Quantity-returned {
+		return nil, ErrReturnQuantity
+	}
+
+	ret := &models.ReturnRequest{
+		ID:           uuid.New(),
+		OrderID:      order.ID,
+		OrderItemID:  item.ID,
+		UserID:       userID,
+		Quantity:     quantity,
+		Reason:       reason,
+		Status:       constants.ReturnStatusRequested,
+		RefundAmount: item.Price.Mul(quantity),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if err := s.ReturnRepo.CreateReturn(ctx, ret, userID); err != nil {
+		return nil, createReturnError(err)
+	}
+	return ret, nil
+}
+
+func (s *ReturnService) GetReturnsByUserID(ctx context.Context, userID string) ([]models.ReturnRequest, error) {
+	return s.ReturnRepo.GetReturnsByUserID(ctx, userID)
+}
+
+func (s *ReturnService) GetReturnByID(ctx context.Context, id string, isAdmin bool, userID string) (*models.ReturnRequest, error) {
+	ret, err := s.ReturnRepo.GetReturnByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && ret.UserID != userID {
+		return nil, ErrReturnForbidden
+	}
+	return ret, nil
+}
+
+func (s *ReturnService) GetReturns(ctx context.Context, status string) ([]models.ReturnRequest, error) {
+	return s.ReturnRepo.GetReturns(ctx, strings.ToUpper(status))
+}
+
+func (s *ReturnService) ApproveReturn(ctx context.Context, id, adminID, note string) (*models.ReturnRequest, error) {
+	return s.transition(ctx, id, constants.ReturnStatusApproved, adminID, note)
+}
+
+func (s *ReturnService) RejectReturn(ctx context.Context, id, adminID, note string) (*models.ReturnRequest, error) {
+	return s.transition(ctx, id, constants.ReturnStatusRejected, adminID, note)
+}
+
+// MarkReceived records that the returned units arrived at the warehouse and
+// puts them back into stock. Stock lives outside the returns database, so a
+// return can end up received but not restocked; calling MarkReceived again
+// retries the restock.
+func (s *ReturnService) MarkReceived(ctx context.Context, id, adminID, note string) (*models.ReturnRequest, error) {
+	ret, err := s.transition(ctx, id, constants.ReturnStatusReceived, adminID, note)
+	if errors.Is(err, ErrInvalidTransition) {
+		ret, err = s.ReturnRepo.GetReturnByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if ret.RestockedAt != nil || (ret.Status != constants.ReturnStatusReceived && ret.Status != constants.ReturnStatusRefunded) {
+			return nil, ErrInvalidTransition
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.restock(ctx, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// restock puts a received return's units back into stock once. The return is
+// claimed first so concurrent retries cannot both restock it, and the claim
+// is released if the stock update fails so a later retry can try again.
+func (s *ReturnService) restock(ctx context.Context, ret *models.ReturnRequest) error {
+	now := time.Now()
+	claimed, err := s.ReturnRepo.ClaimRestock(ctx, ret.ID.String(), now)
+	if err != nil || !claimed {
+		return err
+	}
+
+	err = s.adjustReturnedStock(ctx, ret)
+	if err != nil {
+		if releaseErr := s.ReturnRepo.ReleaseRestock(ctx, ret.ID.String()); releaseErr != nil {
+			log.Printf("Failed to release restock of return %s for a retry: %v", ret.ID, releaseErr)
+		}
+		return err
+	}
+	ret.RestockedAt = &now
+	return nil
+}
+
+func (s *ReturnService) adjustReturnedStock(ctx context.Context, ret *models.ReturnRequest) error {
+	order, err := s.OrderRepo.GetOrderByID(ctx, ret.OrderID.String())
+	if err != nil {
+		return err
+	}
+	item := findOrderItem(order, ret.OrderItemID.String())
+	if item == nil {
+		return ErrOrderItemNotFound
+	}
+	return s.ProductRepo.AdjustStock(ctx, item.ProductID, ret.Quantity)
+}
+
+// RefundReturn refunds a received return. A nil amount refunds the full value
+// of the returned units; a smaller amount issues a partial refund. The return
+// is moved to REFUNDED and the refund recorded together before the gateway is
+// called, so a repeated request cannot refund it twice.
+func (s *ReturnService) RefundReturn(ctx context.Context, id, adminID string, amount *models.Money) (*models.ReturnRequest, *models.Refund, error) {
+	ret, err := s.ReturnRepo.GetReturnByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	from := ret.Status
+	if !isAllowedTransition(returnTransitions, from, constants.ReturnStatusRefunded) {
+		return nil, nil, ErrInvalidTransition
+	}
+
+	refundAmount := ret.RefundAmount
+	if amount != nil {
+		if !amount.IsPositive() || amount.Currency != ret.RefundAmount.Currency || amount.Cmp(ret.RefundAmount) > 0 {
+			return nil, nil, ErrInvalidRefundAmount
+		}
+		refundAmount = *amount
+	}
+
+	order, err := s.OrderRepo.GetOrderByID(ctx, ret.OrderID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	refund, err := s.PaymentService.NewRefund(ctx, order, refundAmount, "return "+ret.ID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ret.RefundAmount = refundAmount
+	if err := s.ReturnRepo.RefundReturn(ctx, ret, from, refund, unrecordedPayment(order), adminID); err != nil {
+		return nil, nil, transitionError(refundError(err))
+	}
+	s.PaymentService.SendRefund(ctx, order, refund)
+	return ret, refund, nil
+}
+
+func (s *ReturnService) transition(ctx context.Context, id, status, actor, note string) (*models.ReturnRequest, error) {
+	ret, err := s.ReturnRepo.GetReturnByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	from := ret.Status
+	if !isAllowedTransition(returnTransitions, from, status) {
+		return nil, ErrInvalidTransition
+	}
+
+	ret.Status = status
+	if note = strings.TrimSpace(note); note != "" {
+		ret.AdminNote = note
+	}
+	if err := s.ReturnRepo.UpdateReturnStatus(ctx, ret, from, note, actor); err != nil {
+		return nil, transitionError(err)
+	}
+	return ret, nil
+}
+
+// transitionError reports a return another request moved first as an
+// invalid transition, since it is no longer in the status this one started
+// from.
+func transitionError(err error) error {
+	if errors.Is(err, interfaces.ErrReturnStatusChanged) {
+		return ErrInvalidTransition
+	}
+	return err
+}
+
+// createReturnError maps an order line that changed while the return was
+// being stored to the error its new state would have produced up front.
+func createReturnError(err error) error {
+	switch {
+	case errors.Is(err, interfaces.ErrReturnExceedsOrder):
+		return ErrReturnQuantity
+	case errors.Is(err, interfaces.ErrOrderItemsChanged):
+		return ErrReturnNotAllowed
+	}
+	return err
+}
+
+func findOrderItem(order *models.Order, orderItemID string) *models.OrderItem {
+	for i := range order.Items {
+		if order.Items[i].ID.String() == orderItemID {
+			return &order.Items[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Shrey-Yash/Masked11/config"
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+func TestReturnTransitions(t *testing.T) {
+	cases := map[[2]string]bool{
+		{constants.ReturnStatusRequested, constants.ReturnStatusApproved}: true,
+		{constants.ReturnStatusRequested, constants.ReturnStatusRejected}: true,
+		{constants.ReturnStatusApproved, constants.ReturnStatusReceived}:  true,
+		{constants.ReturnStatusReceived, constants.ReturnStatusRefunded}:  true,
+		{constants.ReturnStatusRequested, constants.ReturnStatusReceived}: false,
+		{constants.ReturnStatusRequested, constants.ReturnStatusRefunded}: false,
+		{constants.ReturnStatusApproved, constants.ReturnStatusRefunded}:  false,
+		{constants.ReturnStatusApproved, constants.ReturnStatusRejected}:  false,
+		{constants.ReturnStatusRejected, constants.ReturnStatusApproved}:  false,
+		{constants.ReturnStatusReceived, constants.ReturnStatusReceived}:  false,
+		{constants.ReturnStatusRefunded, constants.ReturnStatusRefunded}:  false,
+	}
+	for step, want := range cases {
+		assert.Equal(t, want, isAllowedTransition(returnTransitions, step[0], step[1]), "%s -> %s", step[0], step[1])
+	}
+}
+
+// racedReturnRepo serves a return in one status but reports that another
+// request moved it before the update landed.
+type racedReturnRepo struct {
+	interfaces.ReturnRepository
+	status string
+}
+
+func (r *racedReturnRepo) GetReturnByID(ctx context.Context, id string) (*models.ReturnRequest, error) {
+	return &models.ReturnRequest{ID: uuid.MustParse(id), Status: r.status}, nil
+}
+
+func (r *racedReturnRepo) UpdateReturnStatus(ctx context.Context, ret *models.ReturnRequest, from, note, actor string) error {
+	return interfaces.ErrReturnStatusChanged
+}
+
+func TestMarkReceivedLosesRace(t *testing.T) {
+	// The product repository is left nil, so restocking would panic.
+	s := &ReturnService{ReturnRepo: &racedReturnRepo{status: constants.ReturnStatusApproved}}
+	_, err := s.MarkReceived(context.Background(), uuid.NewString(), "admin", "")
+	assert.Equal(t, ErrInvalidTransition, err)
+}
+
+// orderStub serves a single order.
+type orderStub struct {
+	interfaces.OrderRepository
+	order *models.Order
+}
+
+func (r *orderStub) GetOrderByID(ctx context.Context, orderID string) (*models.Order, error) {
+	return r.order, nil
+}
+
+func TestRequestReturnRejectsCancelledLine(t *testing.T) {
+	deliveredAt := time.Now().Add(-24 * time.Hour)
+	line := models.OrderItem{ID: uuid.New(), Quantity: 2, Status: constants.OrderItemStatusCancelled}
+	order := &models.Order{
+		ID:          uuid.New(),
+		UserID:      "customer",
+		Status:      constants.OrderStatusDelivered,
+		DeliveredAt: &deliveredAt,
+		Items:       []models.OrderItem{line},
+	}
+	// The return repository is left nil, so nothing past the line check runs.
+	s := &ReturnService{OrderRepo: &orderStub{order: order}, Config: config.ReturnsConfig{Window: 7 * 24 * time.Hour}}
+	_, err := s.RequestReturn(context.Background(), "customer", order.ID.String(), line.ID.String(), 1, "Too small")
+	assert.Equal(t, ErrReturnNotAllowed, err)
+}
+
+func TestCreateReturnError(t *testing.T) {
+	cases := map[error]error{
+		interfaces.ErrReturnExceedsOrder: ErrReturnQuantity,
+		interfaces.ErrOrderItemsChanged:  ErrReturnNotAllowed,
+		context.Canceled:                 context.Canceled,
+	}
+	for err, want := range cases {
+		assert.Equal(t, want, createReturnError(err), err.Error())
+	}
+}
+
+// memoryReturnRepo keeps one return in memory.
+type memoryReturnRepo struct {
+	interfaces.ReturnRepository
+	ret models.ReturnRequest
+}
+
+func (r *memoryReturnRepo) GetReturnByID(ctx context.Context, id string) (*models.ReturnRequest, error) {
+	copied := r.ret
+	return &copied, nil
+}
+
+func (r *memoryReturnRepo) UpdateReturnStatus(ctx context.Context, ret *models.ReturnRequest, from, note, actor string) error {
+	if r.ret.Status != from {
+		return interfaces.ErrReturnStatusChanged
+	}
+	r.ret.Status = ret.Status
+	return nil
+}
+
+func (r *memoryReturnRepo) ClaimRestock(ctx context.Context, id string, at time.Time) (bool, error) {
+	if r.ret.RestockedAt != nil {
+		return false, nil
+	}
+	r.ret.RestockedAt = &at
+	return true, nil
+}
+
+func (r *memoryReturnRepo) ReleaseRestock(ctx context.Context, id string) error {
+	r.ret.RestockedAt = nil
+	return nil
+}
+
+// flakyStockRepo fails the first stock adjustment and records the rest.
+type flakyStockRepo struct {
+	interfaces.ProductRepository
+	failed   bool
+	restored map[string]int
+}
+
+func (r *flakyStockRepo) AdjustStock(ctx context.Context, id string, delta int) error {
+	if !r.failed {
+		r.failed = true
+		return context.DeadlineExceeded
+	}
+	r.restored[id] += delta
+	return nil
+}
+
+func TestMarkReceivedRetriesRestock(t *testing.T) {
+	line := models.OrderItem{ID: uuid.New(), ProductID: "tee", Quantity: 2, Status: constants.OrderItemStatusActive}
+	order := &models.Order{ID: uuid.New(), Items: []models.OrderItem{line}}
+	returns := &memoryReturnRepo{ret: models.ReturnRequest{
+		ID:          uuid.New(),
+		OrderID:     order.ID,
+		OrderItemID: line.ID,
+		Quantity:    2,
+		Status:      constants.ReturnStatusApproved,
+	}}
+	stock := &flakyStockRepo{restored: map[string]int{}}
+	s := &ReturnService{ReturnRepo: returns, OrderRepo: &orderStub{order: order}, ProductRepo: stock}
+	id := returns.ret.ID.String()
+
+	_, err := s.MarkReceived(context.Background(), id, "admin", "")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, constants.ReturnStatusReceived, returns.ret.Status)
+	assert.Nil(t, returns.ret.RestockedAt)
+
+	ret, err := s.MarkReceived(context.Background(), id, "admin", "")
+	assert.NoError(t, err)
+	assert.NotNil(t, ret.RestockedAt)
+	assert.Equal(t, map[string]int{"tee": 2}, stock.restored)
+
+	_, err = s.MarkReceived(context.Background(), id, "admin", "")
+	assert.Equal(t, ErrInvalidTransition, err)
+	assert.Equal(t, map[string]int{"tee": 2}, stock.restored)
+}
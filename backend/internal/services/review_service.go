@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+	"github.com/Shrey-Yash/Masked11/internal/utils"
+)
+
+var (
+	ErrReviewNotFound       = errors.New("review not found")
+	ErrProductNotFound      = errors.New("product not found")
+	ErrInvalidReview        = errors.New("rating must be 1-5 and the review at least 10 characters, with up to 5 photo URLs")
+	ErrNotVerifiedPurchaser = errors.New("only customers who received this product can review it")
+	ErrReviewNotModeratable = errors.New("review has already been moderated")
+	ErrOwnReviewVote        = errors.New("you cannot vote on your own review")
+)
+
+type ReviewService struct {
+	ReviewRepo  interfaces.ReviewRepository
+	OrderRepo   interfaces.OrderRepository
+	ProductRepo interfaces.ProductRepository
+	UserRepo    interfaces.UserRepository
+}
+
+func NewReviewService(reviewRepo interfaces.ReviewRepository, orderRepo interfaces.OrderRepository, productRepo interfaces.ProductRepository, userRepo interfaces.UserRepository) *ReviewService {
+	return &ReviewService{
+		ReviewRepo:  reviewRepo,
+		OrderRepo:   orderRepo,
+		ProductRepo: productRepo,
+		UserRepo:    userRepo,
+	}
+}
+
+// CreateReview submits a review for moderation. The user must have a
+// delivered order containing the product.
+func (s *ReviewService) CreateReview(ctx context.Context, userID, productID string, req models.CreateReviewRequest) (*models.Review, error) {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Body = strings.TrimSpace(req.Body)
+	if err := validate.Struct(req); err != nil {
+		return nil, ErrInvalidReview
+	}
+
+	product, err := findProduct(ctx, s.ProductRepo, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Orders are owned by the customer's cart key rather than their bare ID.
+	orderID, err := s.OrderRepo.GetDeliveredOrderID(ctx, utils.UserScopedKey(userID, "cart"), productID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotVerifiedPurchaser
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	review := &models.Review{
+		ProductID: product.ID,
+		UserID:    userID,
+		UserName:  displayName(s.UserRepo, userID),
+		OrderID:   orderID,
+		Rating:    req.Rating,
+		Title:     req.Title,
+		Body:      req.Body,
+		Photos:    req.Photos,
+		Status:    constants.ReviewStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.ReviewRepo.CreateReview(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// GetProductReviews pages through a product's approved reviews.
+func (s *ReviewService) GetProductReviews(ctx context.Context, productID, sortBy string, page, limit int) ([]*models.Review, int64, error) {
+	if _, err := findProduct(ctx, s.ProductRepo, productID); err != nil {
+		return nil, 0, err
+	}
+	return s.ReviewRepo.ListReviews(ctx, map[string]interface{}{
+		"productId": productID,
+		"status":    constants.ReviewStatusApproved,
+		"sortBy":    sortBy,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+// GetModerationQueue lists reviews by status, oldest pending first by default.
+func (s *ReviewService) GetModerationQueue(ctx context.Context, status string, page, limit int) ([]*models.Review, int64, error) {
+	if status == "" {
+		status = constants.ReviewStatusPending
+	}
+	return s.ReviewRepo.ListReviews(ctx, map[string]interface{}{
+		"status": status,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+func (s *ReviewService) ApproveReview(ctx context.Context, reviewID, adminID, note string) (*models.Review, error) {
+	return s.moderateReview(ctx, reviewID, constants.ReviewStatusApproved, adminID, note)
+}
+
+func (s *ReviewService) RejectReview(ctx context.Context, reviewID, adminID, note string) (*models.Review, error) {
+	return s.moderateReview(ctx, reviewID, constants.ReviewStatusRejected, adminID, note)
+}
+
+// moderateReview approves or rejects a pending review and refreshes the
+// product's rating.
+func (s *ReviewService) moderateReview(ctx context.Context, reviewID, status, adminID, note string) (*models.Review, error) {
+	review, err := s.ReviewRepo.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, ErrReviewNotFound
+	}
+	if review.Status != constants.ReviewStatusPending {
+		return nil, ErrReviewNotModeratable
+	}
+
+	now := time.Now()
+	review.Status = status
+	review.ModeratedBy = adminID
+	review.ModerationNote = strings.TrimSpace(note)
+	review.ModeratedAt = &now
+	review.UpdatedAt = now
+	if err := s.ReviewRepo.UpdateReviewStatus(ctx, review); err != nil {
+		return nil, err
+	}
+
+	if status == constants.ReviewStatusApproved {
+		if err := s.refreshProductRating(ctx, review.ProductID.Hex()); err != nil {
+			return nil, err
+		}
+	}
+	return review, nil
+}
+
+// MarkHelpful records a helpful vote on an approved review.
+func (s *ReviewService) MarkHelpful(ctx context.Context, reviewID, userID string) error {
+	review, err := s.ReviewRepo.GetReviewByID(ctx, reviewID)
+	if err != nil || review.Status != constants.ReviewStatusApproved {
+		return ErrReviewNotFound
+	}
+	if review.UserID == userID {
+		return ErrOwnReviewVote
+	}
+	return s.ReviewRepo.AddHelpfulVote(ctx, reviewID, userID)
+}
+
+// refreshProductRating recomputes the rating summary denormalized onto the
+// product so listings can sort by it.
+func (s *ReviewService) refreshProductRating(ctx context.Context, productID string) error {
+	summary, err := s.ReviewRepo.GetRatingSummary(ctx, productID)
+	if err != nil {
+		return err
+	}
+	return s.ProductRepo.UpdateProduct(ctx, productID, map[string]interface{}{
+		"ratingAverage": summary.Average,
+		"ratingCount":   summary.Count,
+	})
+}
+
+// findProduct loads a product, reporting unknown or malformed IDs and
+// deleted products as ErrProductNotFound.
+func findProduct(ctx context.Context, productRepo interfaces.ProductRepository, productID string) (*models.Product, error) {
+	product, err := productRepo.GetProductByID(ctx, productID)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) ||
+		(err == nil && product.DeletedAt != nil) {
+		return nil, ErrProductNotFound
+	}
+	return product, err
+}
+
+// displayName returns the user's name for public attribution, or an empty
+// string if the user cannot be found.
+func displayName(userRepo interfaces.UserRepository, userID string) string {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return ""
+	}
+	user, err := userRepo.GetUserByID(objectID)
+	if err != nil {
+		return ""
+	}
+	return user.Name
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runEvery runs task once straight away and then on every tick of interval
+// for the life of the process. Failures are logged and retried on the next
+// tick.
+func runEvery(interval time.Duration, name string, task func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Printf("Scheduled task %s disabled", name)
+		return
+	}
+
+	run := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		defer cancel()
+		if err := task(ctx); err != nil {
+			log.Printf("Scheduled task %s failed: %v", name, err)
+		}
+	}
+
+	go func() {
+		run()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run()
+		}
+	}()
+}
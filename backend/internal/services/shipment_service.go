@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+var (
+	ErrOrderNotShippable   = errors.New("order cannot be shipped in its current status")
+	ErrTrackingRequired    = errors.New("carrier and tracking number are required")
+	ErrShipmentQuantity    = errors.New("invalid shipment quantity")
+	ErrNothingToShip       = errors.New("all items of this order have already been shipped")
+	ErrInvalidTrackingStep = errors.New("invalid tracking status")
+)
+
+// shippableStatuses are the order statuses from which a shipment may be created.
+var shippableStatuses = []string{
+	constants.OrderStatusPaid,
+	constants.OrderStatusCODPending,
+	constants.OrderStatusPartiallyShipped,
+}
+
+var trackingStatuses = []string{
+	constants.ShipmentStatusShipped,
+	constants.ShipmentStatusInTransit,
+	constants.ShipmentStatusOutForDelivery,
+	constants.ShipmentStatusDelivered,
+	constants.ShipmentStatusException,
+}
+
+type ShipmentService struct {
+	ShipmentRepo interfaces.ShipmentRepository
+	OrderRepo    interfaces.OrderRepository
+	OrderService *OrderService
+	Carriers     map[string]Carrier
+}
+
+// NewShipmentService registers the given carrier integrations by name.
+// Shipments with any other carrier are tracked manually.
+func NewShipmentService(shipmentRepo interfaces.ShipmentRepository, orderRepo interfaces.OrderRepository, orderService *OrderService, carriers ...Carrier) *ShipmentService {
+	registry := make(map[string]Carrier, len(carriers))
+	for _, c := range carriers {
+		registry[strings.ToLower(c.Name())] = c
+	}
+	return &ShipmentService{
+		ShipmentRepo: shipmentRepo,
+		OrderRepo:    orderRepo,
+		OrderService: orderService,
+		Carriers:     registry,
+	}
+}
+
+// CreateShipment records a parcel for some or all of an order's unshipped
+// units and moves the order to PARTIALLY_SHIPPED or SHIPPED. With no items in
+// the request every remaining unit is shipped. The quantities are checked
+// again under a lock on the order when the shipment is stored.
+func (s *ShipmentService) CreateShipment(ctx context.Context, orderID string, req models.CreateShipmentRequest) (*models.Shipment, error) {
+	req.Carrier = strings.TrimSpace(req.Carrier)
+	req.TrackingNumber = strings.TrimSpace(req.TrackingNumber)
+	if req.Carrier == "" || req.TrackingNumber == "" {
+		return nil, ErrTrackingRequired
+	}
+
+	order, err := s.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsStatus(shippableStatuses, order.Status) {
+		return nil, ErrOrderNotShippable
+	}
+
+	shipped, err := s.ShipmentRepo.GetShippedQuantities(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := map[string]int{}
+	for _, item := range order.Items {
+		if item.Status != constants.OrderItemStatusActive {
+			continue
+		}
+		if left := item.Quantity - shipped[item.ID.String()]; left > 0 {
+			remaining[item.ID.String()] = left
+		}
+	}
+	if len(remaining) == 0 {
+		return nil, ErrNothingToShip
+	}
+
+	items := req.Items
+	if len(items) == 0 {
+		for _, item := range order.Items {
+			if qty := remaining[item.ID.String()]; qty > 0 {
+				items = append(items, models.ShipmentItem{OrderItemID: item.ID, Quantity: qty})
+			}
+		}
+	}
+	seen := map[string]bool{}
+	for _, item := range items {
+		id := item.OrderItemID.String()
+		if seen[id] || item.Quantity < 1 || item.Quantity > remaining[id] {
+			return nil, ErrShipmentQuantity
+		}
+		seen[id] = true
+	}
+
+	now := time.Now()
+	shipment := &models.Shipment{
+		ID:                uuid.New(),
+		OrderID:           order.ID,
+		Carrier:           req.Carrier,
+		TrackingNumber:    req.TrackingNumber,
+		Status:            constants.ShipmentStatusShipped,
+		Items:             items,
+		EstimatedDelivery: req.EstimatedDelivery,
+		ShippedAt:         now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	shipment.Events = []models.TrackingEvent{{
+		ID:          uuid.New(),
+		ShipmentID:  shipment.ID,
+		Status:      constants.ShipmentStatusShipped,
+		Description: "Handed over to " + req.Carrier,
+		OccurredAt:  now,
+	}}
+	if err := s.ShipmentRepo.CreateShipment(ctx, shipment, shippableStatuses); err != nil {
+		return nil, shipmentError(err)
+	}
+	return shipment, nil
+}
+
+// AddTrackingEvent records a progress update entered by an admin.
+func (s *ShipmentService) AddTrackingEvent(ctx context.Context, shipmentID string, event models.TrackingEvent) (*models.Shipment, error) {
+	event.Status = strings.ToUpper(strings.TrimSpace(event.Status))
+	if !containsStatus(trackingStatuses, event.Status) {
+		return nil, ErrInvalidTrackingStep
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	shipment, err := s.ShipmentRepo.GetShipmentByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.recordEvents(ctx, shipment, []models.TrackingEvent{event})
+}
+
+// SyncTracking pulls the latest events for a shipment from its carrier
+// integration, if there is one.
+func (s *ShipmentService) SyncTracking(ctx context.Context, shipmentID string) (*models.Shipment, error) {
+	shipment, err := s.ShipmentRepo.GetShipmentByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	carrier, ok := s.Carriers[strings.ToLower(shipment.Carrier)]
+	if !ok {
+		carrier = ManualCarrier{}
+	}
+	events, err := carrier.Track(ctx, shipment.TrackingNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return shipment, nil
+	}
+	return s.recordEvents(ctx, shipment, events)
+}
+
+// GetTracking returns the shipments of an order with their tracking timelines.
+func (s *ShipmentService) GetTracking(ctx context.Context, orderID string, isAdmin bool, userID string) ([]models.Shipment, error) {
+	order, err := s.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && order.UserID != userID {
+		return nil, ErrOrderForbidden
+	}
+	return s.ShipmentRepo.GetShipmentsByOrderID(ctx, orderID)
+}
+
+// recordEvents stores new tracking events, moves the shipment to the status of
+// the latest one and marks the order delivered once every unit has arrived.
+func (s *ShipmentService) recordEvents(ctx context.Context, shipment *models.Shipment, events []models.TrackingEvent) (*models.Shipment, error) {
+	events = newTrackingEvents(shipment.Events, events)
+	if len(events) == 0 {
+		return shipment, nil
+	}
+	for i := range events {
+		events[i].ID = uuid.New()
+		events[i].ShipmentID = shipment.ID
+	}
+	timeline := append(append([]models.TrackingEvent{}, shipment.Events...), events...)
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].OccurredAt.Before(timeline[j].OccurredAt)
+	})
+
+	// A delivered shipment stays delivered even if a late event arrives.
+	for _, e := range timeline {
+		if shipment.DeliveredAt != nil {
+			break
+		}
+		shipment.Status = e.Status
+		if e.Status == constants.ShipmentStatusDelivered {
+			at := e.OccurredAt
+			shipment.DeliveredAt = &at
+		}
+	}
+
+	if err := s.ShipmentRepo.AddTrackingEvents(ctx, shipment, events); err != nil {
+		return nil, err
+	}
+	shipment.Events = timeline
+
+	if shipment.DeliveredAt != nil {
+		if err := s.completeDelivery(ctx, shipment.OrderID.String()); err != nil {
+			return nil, err
+		}
+	}
+	return shipment, nil
+}
+
+// newTrackingEvents drops the events already on a shipment or repeated within
+// the batch. Carriers return their whole history on every sync, and an event
+// is identified by its status and time, as in the shipment_events table.
+func newTrackingEvents(existing, events []models.TrackingEvent) []models.TrackingEvent {
+	type key struct {
+		status string
+		at     int64
+	}
+	// Postgres keeps microseconds, so stored events compare at that precision.
+	keyOf := func(e models.TrackingEvent) key {
+		return key{e.Status, e.OccurredAt.UnixMicro()}
+	}
+
+	seen := make(map[key]bool, len(existing))
+	for _, e := range existing {
+		seen[keyOf(e)] = true
+	}
+	var fresh []models.TrackingEvent
+	for _, e := range events {
+		k := keyOf(e)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		fresh = append(fresh, e)
+	}
+	return fresh
+}
+
+// shipmentError maps an order that changed while the shipment was being
+// stored to the error its new state would have produced up front.
+func shipmentError(err error) error {
+	switch {
+	case errors.Is(err, interfaces.ErrOrderStatusChanged):
+		return ErrOrderNotShippable
+	case errors.Is(err, interfaces.ErrShipmentExceedsOrder):
+		return ErrShipmentQuantity
+	}
+	return err
+}
+
+// completeDelivery marks an order DELIVERED when it is fully shipped and all
+// of its shipments have been delivered.
+func (s *ShipmentService) completeDelivery(ctx context.Context, orderID string) error {
+	order, err := s.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != constants.OrderStatusShipped {
+		return nil
+	}
+
+	shipments, err := s.ShipmentRepo.GetShipmentsByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, sh := range shipments {
+		if sh.DeliveredAt == nil {
+			return nil
+		}
+	}
+	return s.OrderService.transitionOrder(order, constants.OrderStatusDelivered)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Shrey-Yash/Masked11/internal/constants"
+	"github.com/Shrey-Yash/Masked11/internal/models"
+	"github.com/Shrey-Yash/Masked11/internal/repositories/interfaces"
+)
+
+func TestNewTrackingEvents(t *testing.T) {
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	event := func(status string, minutes int) models.TrackingEvent {
+		return models.TrackingEvent{Status: status, OccurredAt: base.Add(time.Duration(minutes) * time.Minute)}
+	}
+	shipped, inTransit := constants.ShipmentStatusShipped, constants.ShipmentStatusInTransit
+	stored := []models.TrackingEvent{event(shipped, 0), event(inTransit, 60)}
+
+	cases := map[string]struct {
+		events []models.TrackingEvent
+		want   []models.TrackingEvent
+	}{
+		"full history again": {
+			events: []models.TrackingEvent{event(shipped, 0), event(inTransit, 60)},
+			want:   nil,
+		},
+		"history with a new step": {
+			events: []models.TrackingEvent{event(shipped, 0), event(inTransit, 60), event(inTransit, 120)},
+			want:   []models.TrackingEvent{event(inTransit, 120)},
+		},
+		"repeated within the batch": {
+			events: []models.TrackingEvent{event(inTransit, 120), event(inTransit, 120)},
+			want:   []models.TrackingEvent{event(inTransit, 120)},
+		},
+		"same time, different status": {
+			events: []models.TrackingEvent{event(constants.ShipmentStatusException, 60)},
+			want:   []models.TrackingEvent{event(constants.ShipmentStatusException, 60)},
+		},
+		// Stored events come back from Postgres rounded to microseconds.
+		"nanoseconds the database dropped": {
+			events: []models.TrackingEvent{{Status: inTransit, OccurredAt: base.Add(time.Hour + 500*time.Nanosecond)}},
+			want:   nil,
+		},
+		"same instant in another zone": {
+			events: []models.TrackingEvent{{Status: shipped, OccurredAt: base.In(time.FixedZone("IST", 5*3600+1800))}},
+			want:   nil,
+		},
+	}
+	for name, tc := range cases {
+		assert.Equal(t, tc.want, newTrackingEvents(stored, tc.events), name)
+	}
+}
+
+func TestShipmentError(t *testing.T) {
+	cases := map[error]error{
+		interfaces.ErrOrderStatusChanged:   ErrOrderNotShippable,
+		interfaces.ErrShipmentExceedsOrder: ErrShipmentQuantity,
+		context.Canceled:                   context.Canceled,
+	}
+	for err, want := range cases {
+		assert.ErrorIs(t, shipmentError(err), want, err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -8,7 +9,20 @@ import (
 	"github.com/Shrey-Yash/Masked11/internal/models"
 )
 
-var validate = validator.New()
+var validate = newValidator()
+
+// newValidator returns a validator that checks Money fields by their amount
+// in minor units, so tags such as gt=0 work on prices.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if m, ok := field.Interface().(models.Money); ok {
+			return m.Amount
+		}
+		return nil
+	}, models.Money{})
+	return v
+}
 
 func ValidateUserInput(u *models.User) map[string]string {
 	err := validate.Struct(u)
@@ -7,11 +7,31 @@ import (
 )
 
 func GetCartKey(c *fiber.Ctx) (string, error) {
+	return sessionScopedKey(c, "cart")
+}
+
+// GetRecentlyViewedKey returns the key of the recently viewed list for the
+// signed-in user or, failing that, the guest session.
+func GetRecentlyViewedKey(c *fiber.Ctx) (string, error) {
+	return sessionScopedKey(c, "recently_viewed")
+}
+
+// UserScopedKey and GuestScopedKey build the keys GetCartKey and friends
+// return, for callers that know the IDs but have no request context.
+func UserScopedKey(userID, name string) string {
+	return fmt.Sprintf("user:%s:%s", userID, name)
+}
+
+func GuestScopedKey(sessionID, name string) string {
+	return fmt.Sprintf("guest:%s:%s", sessionID, name)
+}
+
+func sessionScopedKey(c *fiber.Ctx, name string) (string, error) {
 	if uid, ok := c.Locals("userID").(string); ok && uid != "" {
-		return fmt.Sprintf("user:%s:cart", uid), nil
+		return UserScopedKey(uid, name), nil
 	}
-	if sid := c.Locals("sessionID"); sid != "" {
-		return fmt.Sprintf("guest:%s:cart", sid), nil
+	if sid, ok := c.Locals("sessionID").(string); ok && sid != "" {
+		return GuestScopedKey(sid, name), nil
 	}
 	return "", fiber.NewError(fiber.StatusUnauthorized, "Unable to identify session")
 }
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Classic Cotton T-Shirt":       "classic-cotton-t-shirt",
+		"  Men's Slim Fit Jeans (32) ": "men-s-slim-fit-jeans-32",
+		"Café Crème Hoodie":            "cafe-creme-hoodie",
+		"100% Linen -- Summer Edit!":   "100-linen-summer-edit",
+		"कुर्ता":                       "",
+	}
+	for title, want := range cases {
+		assert.Equal(t, want, Slugify(title), title)
+	}
+
+	long := Slugify(strings.Repeat("oversized ", 20))
+	assert.LessOrEqual(t, len(long), maxSlugLength)
+	assert.False(t, strings.HasSuffix(long, "-"))
+}
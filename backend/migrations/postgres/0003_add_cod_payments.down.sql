@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS payments;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS cod_fee,
+    DROP COLUMN IF EXISTS payment_method;
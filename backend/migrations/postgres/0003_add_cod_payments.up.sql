@@ -0,0 +1,21 @@
+ALTER TABLE orders
+    ADD COLUMN payment_method VARCHAR(20) NOT NULL DEFAULT 'ONLINE',
+    ADD COLUMN cod_fee DECIMAL(10, 2) NOT NULL DEFAULT 0.00;
+
+CREATE TABLE payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    reference VARCHAR(255),
+    collected_at TIMESTAMP WITH TIME ZONE,
+    reconciled_at TIMESTAMP WITH TIME ZONE,
+    reconciled_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX payments_order_method_unique ON payments (order_id, method);
+CREATE INDEX payments_method_status_index ON payments (method, status);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS return_events;
+DROP TABLE IF EXISTS returns;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS delivered_at;
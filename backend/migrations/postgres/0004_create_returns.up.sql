@@ -0,0 +1,45 @@
+ALTER TABLE orders ADD COLUMN delivered_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE returns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL,
+    order_item_id UUID NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'REQUESTED',
+    refund_amount DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    admin_note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (order_item_id) REFERENCES order_items(id) ON DELETE CASCADE
+);
+
+CREATE INDEX returns_user_id_index ON returns (user_id, created_at DESC);
+CREATE INDEX returns_status_index ON returns (status, created_at);
+CREATE INDEX returns_order_item_id_index ON returns (order_item_id);
+
+CREATE TABLE return_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    return_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    note TEXT,
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (return_id) REFERENCES returns(id) ON DELETE CASCADE
+);
+
+CREATE INDEX return_events_return_id_index ON return_events (return_id, created_at);
+
+CREATE TABLE refunds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    reference VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
+
+CREATE INDEX refunds_order_id_index ON refunds (order_id);
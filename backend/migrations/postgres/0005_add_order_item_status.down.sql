@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS order_items_order_id_status_index;
+
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS status;
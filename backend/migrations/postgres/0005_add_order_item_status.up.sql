@@ -0,0 +1,5 @@
+ALTER TABLE order_items
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+    ADD COLUMN cancelled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX order_items_order_id_status_index ON order_items (order_id, status);
@@ -0,0 +1,14 @@
+CREATE TABLE order_addresses (
+    order_id UUID NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    line1 VARCHAR(500) NOT NULL,
+    line2 VARCHAR(500),
+    city VARCHAR(100) NOT NULL,
+    state VARCHAR(100) NOT NULL,
+    pincode VARCHAR(10) NOT NULL,
+    country VARCHAR(2) NOT NULL DEFAULT 'IN',
+    PRIMARY KEY (order_id, type),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
@@ -0,0 +1,21 @@
+CREATE TABLE invoice_counters (
+    financial_year VARCHAR(7) PRIMARY KEY,
+    last_number INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL UNIQUE,
+    invoice_number VARCHAR(50) NOT NULL UNIQUE,
+    financial_year VARCHAR(7) NOT NULL,
+    sequence INTEGER NOT NULL,
+    subtotal DECIMAL(10, 2) NOT NULL,
+    discount DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    tax DECIMAL(10, 2) NOT NULL,
+    total DECIMAL(10, 2) NOT NULL,
+    pdf BYTEA NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE RESTRICT,
+    UNIQUE (financial_year, sequence)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS orders_order_number_index;
+ALTER TABLE orders DROP COLUMN IF EXISTS order_number;
+DROP SEQUENCE IF EXISTS order_number_seq;
@@ -0,0 +1,13 @@
+CREATE SEQUENCE order_number_seq;
+
+ALTER TABLE orders ADD COLUMN order_number VARCHAR(32);
+
+UPDATE orders o SET order_number = 'M11-' || EXTRACT(YEAR FROM o.created_at)::int || '-' || LPAD(n.seq::text, 6, '0')
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY created_at, id) AS seq FROM orders) n
+WHERE o.id = n.id;
+
+SELECT setval('order_number_seq', GREATEST((SELECT COUNT(*) FROM orders), 1), (SELECT COUNT(*) FROM orders) > 0);
+
+ALTER TABLE orders ALTER COLUMN order_number SET NOT NULL;
+
+CREATE UNIQUE INDEX orders_order_number_index ON orders (order_number);
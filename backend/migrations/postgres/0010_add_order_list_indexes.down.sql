@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS orders_customer_email_pattern_index;
+DROP INDEX IF EXISTS orders_order_number_pattern_index;
+DROP INDEX IF EXISTS orders_payment_method_created_at_index;
+DROP INDEX IF EXISTS orders_user_id_created_at_index;
+DROP INDEX IF EXISTS orders_status_created_at_index;
+DROP INDEX IF EXISTS orders_total_index;
+DROP INDEX IF EXISTS orders_created_at_index;
+ALTER TABLE orders DROP COLUMN IF EXISTS customer_email;
@@ -0,0 +1,9 @@
+ALTER TABLE orders ADD COLUMN customer_email VARCHAR(255);
+
+CREATE INDEX orders_created_at_index ON orders (created_at DESC, id DESC);
+CREATE INDEX orders_total_index ON orders (total DESC, id DESC);
+CREATE INDEX orders_status_created_at_index ON orders (status, created_at DESC, id DESC);
+CREATE INDEX orders_user_id_created_at_index ON orders (user_id, created_at DESC, id DESC);
+CREATE INDEX orders_payment_method_created_at_index ON orders (payment_method, created_at DESC, id DESC);
+CREATE INDEX orders_order_number_pattern_index ON orders (order_number text_pattern_ops);
+CREATE INDEX orders_customer_email_pattern_index ON orders (LOWER(customer_email) text_pattern_ops);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_sales_daily;
+DROP TABLE IF EXISTS sales_daily;
+ALTER TABLE order_items DROP COLUMN IF EXISTS category;
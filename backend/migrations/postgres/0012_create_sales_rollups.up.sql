@@ -0,0 +1,24 @@
+ALTER TABLE order_items ADD COLUMN category VARCHAR(255);
+
+CREATE TABLE sales_daily (
+    day DATE PRIMARY KEY,
+    orders INTEGER NOT NULL DEFAULT 0,
+    revenue DECIMAL(12, 2) NOT NULL DEFAULT 0.00,
+    new_customer_orders INTEGER NOT NULL DEFAULT 0,
+    returning_customer_orders INTEGER NOT NULL DEFAULT 0,
+    refunds DECIMAL(12, 2) NOT NULL DEFAULT 0.00,
+    refunded_orders INTEGER NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE product_sales_daily (
+    day DATE NOT NULL,
+    product_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    category VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    revenue DECIMAL(12, 2) NOT NULL DEFAULT 0.00,
+    PRIMARY KEY (day, product_id)
+);
+
+CREATE INDEX product_sales_daily_category_index ON product_sales_daily (category, day);
@@ -0,0 +1,35 @@
+ALTER TABLE product_sales_daily
+    ALTER COLUMN revenue TYPE DECIMAL(12, 2) USING revenue / 100.0;
+
+ALTER TABLE sales_daily
+    ALTER COLUMN revenue TYPE DECIMAL(12, 2) USING revenue / 100.0,
+    ALTER COLUMN refunds TYPE DECIMAL(12, 2) USING refunds / 100.0;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN subtotal TYPE DECIMAL(10, 2) USING subtotal / 100.0,
+    ALTER COLUMN discount TYPE DECIMAL(10, 2) USING discount / 100.0,
+    ALTER COLUMN tax TYPE DECIMAL(10, 2) USING tax / 100.0,
+    ALTER COLUMN total TYPE DECIMAL(10, 2) USING total / 100.0;
+
+ALTER TABLE refunds
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+
+ALTER TABLE returns
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN refund_amount TYPE DECIMAL(10, 2) USING refund_amount / 100.0;
+
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN price TYPE DECIMAL(10, 2) USING price / 100.0,
+    ALTER COLUMN subtotal TYPE DECIMAL(10, 2) USING subtotal / 100.0;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN total TYPE DECIMAL(10, 2) USING total / 100.0,
+    ALTER COLUMN cod_fee TYPE DECIMAL(10, 2) USING cod_fee / 100.0;
@@ -0,0 +1,35 @@
+ALTER TABLE orders
+    ALTER COLUMN total TYPE BIGINT USING ROUND(total * 100)::BIGINT,
+    ALTER COLUMN cod_fee TYPE BIGINT USING ROUND(cod_fee * 100)::BIGINT,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR';
+
+ALTER TABLE order_items
+    ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100)::BIGINT,
+    ALTER COLUMN subtotal TYPE BIGINT USING ROUND(subtotal * 100)::BIGINT,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR';
+
+ALTER TABLE payments
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100)::BIGINT,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR';
+
+ALTER TABLE returns
+    ALTER COLUMN refund_amount TYPE BIGINT USING ROUND(refund_amount * 100)::BIGINT,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR';
+
+ALTER TABLE refunds
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100)::BIGINT,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR';
+
+ALTER TABLE invoices
+    ALTER COLUMN subtotal TYPE BIGINT USING ROUND(subtotal * 100)::BIGINT,
+    ALTER COLUMN discount TYPE BIGINT USING ROUND(discount * 100)::BIGINT,
+    ALTER COLUMN tax TYPE BIGINT USING ROUND(tax * 100)::BIGINT,
+    ALTER COLUMN total TYPE BIGINT USING ROUND(total * 100)::BIGINT,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR';
+
+ALTER TABLE sales_daily
+    ALTER COLUMN revenue TYPE BIGINT USING ROUND(revenue * 100)::BIGINT,
+    ALTER COLUMN refunds TYPE BIGINT USING ROUND(refunds * 100)::BIGINT;
+
+ALTER TABLE product_sales_daily
+    ALTER COLUMN revenue TYPE BIGINT USING ROUND(revenue * 100)::BIGINT;
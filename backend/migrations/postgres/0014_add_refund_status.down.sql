@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS refunds_status_index;
+
+ALTER TABLE refunds
+    DROP COLUMN status,
+    DROP COLUMN settled_at,
+    DROP COLUMN settled_by,
+    DROP COLUMN updated_at;
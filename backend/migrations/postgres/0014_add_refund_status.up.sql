@@ -0,0 +1,11 @@
+ALTER TABLE refunds
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN settled_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN settled_by VARCHAR(255),
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+
+-- Refunds with a gateway reference were sent. The rest went through the
+-- manual gateway, which moved no money, so they wait for finance.
+UPDATE refunds SET status = CASE WHEN reference IS NULL THEN 'SETTLEMENT_PENDING' ELSE 'COMPLETED' END;
+
+CREATE INDEX refunds_status_index ON refunds (status, created_at);
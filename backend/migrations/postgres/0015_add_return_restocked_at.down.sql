@@ -0,0 +1 @@
+ALTER TABLE returns DROP COLUMN restocked_at;
@@ -0,0 +1,4 @@
+ALTER TABLE returns ADD COLUMN restocked_at TIMESTAMP WITH TIME ZONE;
+
+-- Returns already received were restocked when they were marked received.
+UPDATE returns SET restocked_at = updated_at WHERE status IN ('RECEIVED', 'REFUNDED');